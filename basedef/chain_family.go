@@ -0,0 +1,64 @@
+package basedef
+
+import (
+	"strings"
+	"sync"
+)
+
+// chain families, every chain belongs to one of them and is served by the listener,
+// fee, monitor and sdk implementations registered for its family
+const (
+	FAMILY_POLY     = "POLY"
+	FAMILY_EVM      = "EVM"
+	FAMILY_ONTEVM   = "ONTEVM"
+	FAMILY_O3       = "O3"
+	FAMILY_NEO      = "NEO"
+	FAMILY_NEO3     = "NEO3"
+	FAMILY_ONTOLOGY = "ONTOLOGY"
+	FAMILY_SWITCHEO = "SWITCHEO"
+	FAMILY_ZILLIQA  = "ZILLIQA"
+	FAMILY_STARCOIN = "STARCOIN"
+	FAMILY_RIPPLE   = "RIPPLE"
+	FAMILY_APTOS    = "APTOS"
+	FAMILY_BFC      = "BFC"
+)
+
+var (
+	chainFamilyLock sync.RWMutex
+	chainFamilies   = defaultChainFamilies()
+)
+
+func defaultChainFamilies() map[uint64]string {
+	families := make(map[uint64]string)
+	for _, chainId := range ETH_CHAINS {
+		families[chainId] = FAMILY_EVM
+	}
+	families[O3_CROSSCHAIN_ID] = FAMILY_O3
+	families[ONTEVM_CROSSCHAIN_ID] = FAMILY_ONTEVM
+	families[POLY_CROSSCHAIN_ID] = FAMILY_POLY
+	families[NEO_CROSSCHAIN_ID] = FAMILY_NEO
+	families[NEO3_CROSSCHAIN_ID] = FAMILY_NEO3
+	families[ONT_CROSSCHAIN_ID] = FAMILY_ONTOLOGY
+	families[SWITCHEO_CROSSCHAIN_ID] = FAMILY_SWITCHEO
+	families[ZILLIQA_CROSSCHAIN_ID] = FAMILY_ZILLIQA
+	families[STARCOIN_CROSSCHAIN_ID] = FAMILY_STARCOIN
+	families[RIPPLE_CROSSCHAIN_ID] = FAMILY_RIPPLE
+	families[APTOS_CROSSCHAIN_ID] = FAMILY_APTOS
+	families[BFC_CROSSCHAIN_ID] = FAMILY_BFC
+	return families
+}
+
+// RegisterChainFamily declares the family of a chain, it overrides the built-in one,
+// so that a new chain of a known family can be added by configuration only
+func RegisterChainFamily(chainId uint64, family string) {
+	chainFamilyLock.Lock()
+	defer chainFamilyLock.Unlock()
+	chainFamilies[chainId] = strings.ToUpper(strings.TrimSpace(family))
+}
+
+// GetChainFamily returns the family of the chain, or "" when it is unknown
+func GetChainFamily(chainId uint64) string {
+	chainFamilyLock.RLock()
+	defer chainFamilyLock.RUnlock()
+	return chainFamilies[chainId]
+}
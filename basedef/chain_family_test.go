@@ -0,0 +1,19 @@
+package basedef
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestChainFamily(t *testing.T) {
+	assert.Equal(t, FAMILY_EVM, GetChainFamily(ETHEREUM_CROSSCHAIN_ID))
+	assert.Equal(t, FAMILY_ONTEVM, GetChainFamily(ONTEVM_CROSSCHAIN_ID))
+	assert.Equal(t, FAMILY_POLY, GetChainFamily(POLY_CROSSCHAIN_ID))
+
+	chainId := uint64(99)
+	assert.Equal(t, "", GetChainFamily(chainId))
+	assert.False(t, IsETHChain(chainId))
+	RegisterChainFamily(chainId, " evm")
+	assert.Equal(t, FAMILY_EVM, GetChainFamily(chainId))
+	assert.True(t, IsETHChain(chainId))
+}
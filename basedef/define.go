@@ -63,6 +63,32 @@ const (
 	StatusOk = "OK"
 )
 
+const (
+	DEAD_LETTER_PENDING = iota
+	DEAD_LETTER_RESOLVED
+	DEAD_LETTER_ACKED
+)
+
+const (
+	CIRCUIT_CHAIN = "chain"
+	CIRCUIT_ROUTE = "route"
+	CIRCUIT_TOKEN = "token"
+)
+
+const (
+	WEBHOOK_EVENT_WRAPPER  = "wrapper"
+	WEBHOOK_EVENT_SRC      = "src"
+	WEBHOOK_EVENT_POLY     = "poly"
+	WEBHOOK_EVENT_DST      = "dst"
+	WEBHOOK_EVENT_FINISHED = "finished"
+)
+
+const (
+	WEBHOOK_DELIVERY_PENDING = iota
+	WEBHOOK_DELIVERY_DELIVERED
+	WEBHOOK_DELIVERY_FAILED
+)
+
 const (
 	Chain_Status_All_Nodes_No_Growth        = "All Nodes No Growth"
 	Chain_Status_All_Nodes_Unavaiable       = "All Nodes Unavailable"
@@ -100,6 +126,40 @@ type ChainStatus struct {
 	Time          int64
 }
 
+// CircuitBreak pauses a chain, a route "srcChainId-dstChainId" or a token basic name
+type CircuitBreak struct {
+	Scope    string
+	Target   string
+	Reason   string
+	Operator string
+	Auto     bool
+	Time     int64
+}
+
+func (c *CircuitBreak) Key() string {
+	return CircuitKey(c.Scope, c.Target)
+}
+
+func CircuitKey(scope, target string) string {
+	return scope + "_" + target
+}
+
+func ChainCircuitKey(chainId uint64) string {
+	return CircuitKey(CIRCUIT_CHAIN, fmt.Sprint(chainId))
+}
+
+func RouteCircuitTarget(srcChainId, dstChainId uint64) string {
+	return fmt.Sprintf("%d-%d", srcChainId, dstChainId)
+}
+
+func RouteCircuitKey(srcChainId, dstChainId uint64) string {
+	return CircuitKey(CIRCUIT_ROUTE, RouteCircuitTarget(srcChainId, dstChainId))
+}
+
+func TokenCircuitKey(tokenBasicName string) string {
+	return CircuitKey(CIRCUIT_TOKEN, tokenBasicName)
+}
+
 type RelayerAccountStatus struct {
 	ChainId   uint64
 	ChainName string
@@ -69,7 +69,7 @@ const (
 )
 
 const (
-	BSC_NORMAL_GASPRICE   = 5000000000
+	// the gas price node of astar returns is lower than the average, it is the floor of the astar fee by default
 	ASTAR_NORMAL_GASPRICE = 60000000000
 )
 
@@ -0,0 +1,57 @@
+package basedef
+
+import "net/http"
+
+// Error codes of the http responses, they are stable for the clients to branch on and to localise,
+// the messages of the responses are only descriptions of the errors
+const (
+	ERROR_INVALID_PARAMETER         = "INVALID_PARAMETER"
+	ERROR_INVALID_API_KEY           = "INVALID_API_KEY"
+	ERROR_ACCESS_DENIED             = "ACCESS_DENIED"
+	ERROR_NOT_FOUND                 = "NOT_FOUND"
+	ERROR_CHAIN_NOT_FOUND           = "CHAIN_NOT_FOUND"
+	ERROR_TOKEN_NOT_FOUND           = "TOKEN_NOT_FOUND"
+	ERROR_TOKEN_MAP_NOT_FOUND       = "TOKEN_MAP_NOT_FOUND"
+	ERROR_TRANSACTION_NOT_FOUND     = "TRANSACTION_NOT_FOUND"
+	ERROR_TRANSACTION_NOT_RELAYABLE = "TRANSACTION_NOT_RELAYABLE"
+	ERROR_TRANSACTION_RELAYED       = "TRANSACTION_RELAYED"
+	ERROR_CIRCUIT_NOT_PAUSED        = "CIRCUIT_NOT_PAUSED"
+	ERROR_RATE_LIMITED              = "RATE_LIMITED"
+	ERROR_CIRCUIT_PAUSED            = "CIRCUIT_PAUSED"
+	ERROR_FEE_UNAVAILABLE           = "FEE_UNAVAILABLE"
+	ERROR_PRICE_ZERO                = "PRICE_ZERO"
+	ERROR_RELAY_PENDING             = "RELAY_PENDING"
+	ERROR_RELAY_TIMEOUT             = "RELAY_TIMEOUT"
+	ERROR_NODE_ERROR                = "NODE_ERROR"
+	ERROR_SERVICE_ERROR             = "SERVICE_ERROR"
+)
+
+var errorStatuses = map[string]int{
+	ERROR_INVALID_PARAMETER:         http.StatusBadRequest,
+	ERROR_INVALID_API_KEY:           http.StatusUnauthorized,
+	ERROR_ACCESS_DENIED:             http.StatusForbidden,
+	ERROR_NOT_FOUND:                 http.StatusNotFound,
+	ERROR_CHAIN_NOT_FOUND:           http.StatusNotFound,
+	ERROR_TOKEN_NOT_FOUND:           http.StatusNotFound,
+	ERROR_TOKEN_MAP_NOT_FOUND:       http.StatusNotFound,
+	ERROR_TRANSACTION_NOT_FOUND:     http.StatusNotFound,
+	ERROR_TRANSACTION_NOT_RELAYABLE: http.StatusConflict,
+	ERROR_TRANSACTION_RELAYED:       http.StatusConflict,
+	ERROR_CIRCUIT_NOT_PAUSED:        http.StatusConflict,
+	ERROR_RATE_LIMITED:              http.StatusTooManyRequests,
+	ERROR_CIRCUIT_PAUSED:            http.StatusServiceUnavailable,
+	ERROR_FEE_UNAVAILABLE:           http.StatusServiceUnavailable,
+	ERROR_PRICE_ZERO:                http.StatusServiceUnavailable,
+	ERROR_RELAY_PENDING:             http.StatusServiceUnavailable,
+	ERROR_RELAY_TIMEOUT:             http.StatusGatewayTimeout,
+	ERROR_NODE_ERROR:                http.StatusBadGateway,
+	ERROR_SERVICE_ERROR:             http.StatusInternalServerError,
+}
+
+// ErrorStatus returns the http status of the error code, 500 for the codes out of the catalogue
+func ErrorStatus(code string) int {
+	if status, ok := errorStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -418,5 +418,5 @@ func IsETHChain(chainId uint64) bool {
 			return true
 		}
 	}
-	return false
+	return GetChainFamily(chainId) == FAMILY_EVM
 }
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/crosschaindao"
+	"poly-bridge/crosschainlisten"
+	"strconv"
+	"strings"
+)
+
+// backfill scans the blocks [BR_HEIGHT, END_HEIGHT] of chain BR_CHAIN again and prints how the
+// indexed events differ, with -apply the missing and changed events are saved
+func backfill(config *conf.Config, apply bool) {
+	chain, _ := strconv.ParseUint(os.Getenv("BR_CHAIN"), 10, 64)
+	height, _ := strconv.ParseUint(os.Getenv("BR_HEIGHT"), 10, 64)
+	endHeight, _ := strconv.ParseUint(os.Getenv("END_HEIGHT"), 10, 64)
+	if endHeight < height {
+		endHeight = height
+	}
+	cfg := config.GetChainListenConfig(chain)
+	if cfg == nil {
+		panic(fmt.Sprintf("chain %d handler is invalid", chain))
+	}
+	handle := crosschainlisten.NewChainHandle(cfg)
+	if handle == nil {
+		panic(fmt.Sprintf("chain %d handler is invalid", chain))
+	}
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_BRIDGE, false, config.DBConfig)
+	if dao == nil {
+		panic("server is not valid")
+	}
+
+	diff, err := crosschainlisten.Backfill(handle, dao, config, height, endHeight, apply)
+	checkError(err, "Backfill")
+	for _, tx := range diff.Missing.WrapperTransactions {
+		fmt.Printf("missing wrapper %s: %+v\n", tx.Hash, *tx)
+	}
+	for _, tx := range diff.Missing.SrcTransactions {
+		fmt.Printf("missing src %s: %+v srcTransfer:%+v\n", tx.Hash, *tx, tx.SrcTransfer)
+	}
+	for _, tx := range diff.Missing.PolyTransactions {
+		fmt.Printf("missing poly %s: %+v\n", tx.Hash, *tx)
+	}
+	for _, tx := range diff.Missing.DstTransactions {
+		fmt.Printf("missing dst %s: %+v dstTransfer:%+v\n", tx.Hash, *tx, tx.DstTransfer)
+	}
+	for _, change := range diff.Changes {
+		fmt.Printf("changed %s %s: %s\n", change.Table, change.Hash, strings.Join(change.Fields, ", "))
+	}
+	for _, tx := range diff.Extra.WrapperTransactions {
+		fmt.Printf("extra wrapper %s height %d\n", tx.Hash, tx.BlockHeight)
+	}
+	for _, tx := range diff.Extra.SrcTransactions {
+		fmt.Printf("extra src %s height %d\n", tx.Hash, tx.Height)
+	}
+	for _, tx := range diff.Extra.PolyTransactions {
+		fmt.Printf("extra poly %s height %d\n", tx.Hash, tx.Height)
+	}
+	for _, tx := range diff.Extra.DstTransactions {
+		fmt.Printf("extra dst %s height %d\n", tx.Hash, tx.Height)
+	}
+	fmt.Printf("chain %d block [%d, %d] missing %d changed %d extra %d\n",
+		chain, height, endHeight, diff.Missing.Len(), diff.Changed.Len(), diff.Extra.Len())
+	if apply && diff.Missing.Len()+diff.Changed.Len() > 0 {
+		fmt.Printf("saved %d missing and %d changed events, extra events are left as they are\n", diff.Missing.Len(), diff.Changed.Len())
+	}
+}
@@ -21,11 +21,10 @@ import (
 	"poly-bridge/bridge_tools/conf"
 	serverconf "poly-bridge/conf"
 	"poly-bridge/crosschaindao"
-	"poly-bridge/models"
+	"poly-bridge/dbconn"
+	"poly-bridge/migrations"
 	"strings"
 
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
@@ -35,29 +34,11 @@ func startDeploy(cfg *conf.DeployConfig, servercfg *serverconf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
-	err = db.Debug().AutoMigrate(
-		&models.ChainFee{},
-		&models.Chain{},
-		&models.DstSwap{},
-		&models.DstTransaction{},
-		&models.DstTransfer{},
-		&models.NFTProfile{},
-		&models.PolyTransaction{},
-		&models.PriceMarket{},
-		&models.SrcSwap{},
-		&models.SrcTransaction{},
-		&models.SrcTransfer{},
-		&models.TimeStatistic{},
-		&models.TokenBasic{},
-		&models.TokenMap{},
-		&models.Token{},
-		&models.WrapperTransaction{},
-	)
+	_, err = migrations.Up(db.Debug(), 0)
 	if err != nil {
 		panic(err)
 	}
@@ -21,10 +21,9 @@ import (
 	"fmt"
 	"poly-bridge/bridge_tools/conf"
 	serverconf "poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
@@ -33,8 +32,7 @@ func dumpStatus(dbCfg *serverconf.DBConfig) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -89,8 +87,7 @@ func dumpAffectedRows(cfg *conf.UpdateConfig, dbCfg *serverconf.DBConfig) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
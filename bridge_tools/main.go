@@ -73,6 +73,10 @@ var (
 		Usage: "rate of increase for dying token",
 		Value: 0,
 	}
+	applyFlag = cli.BoolFlag{
+		Name:  "apply",
+		Usage: "save the discrepancies found by the backfill method",
+	}
 )
 
 //getFlagName deal with short flag, and return the flag name whether flag name have short name
@@ -99,8 +103,11 @@ func setupApp() *cli.App {
 		methodFlag,
 		dyingTokensFlag,
 		dyingTokensRisingRateFlag,
+		applyFlag,
+	}
+	app.Commands = []cli.Command{
+		migrateCommand,
 	}
-	app.Commands = []cli.Command{}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
 		return nil
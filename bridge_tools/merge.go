@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/cosmos/cosmos-sdk/types"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"os"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	"poly-bridge/crosschaindao/explorerdao"
+	"poly-bridge/dbconn"
+	"poly-bridge/migrations"
 	"poly-bridge/models"
 	"reflect"
 	"strings"
@@ -100,8 +101,7 @@ func merge() {
 	conn := func(cfg *conf.DBConfig) *gorm.DB {
 		Logger := logger.Default
 		Logger = Logger.LogMode(logger.Info)
-		db, err := gorm.Open(mysql.Open(cfg.User+":"+cfg.Password+"@tcp("+cfg.URL+")/"+
-			cfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+		db, err := dbconn.Open(cfg, Logger)
 		checkError(err, "Connecting to db")
 		return db
 	}
@@ -225,27 +225,7 @@ func migrateExplorerBasicTables(exp, db *gorm.DB) {
 }
 
 func createTables(db *gorm.DB) {
-	err := db.Debug().AutoMigrate(
-		&models.DstTransaction{},
-		&models.SrcTransaction{},
-		&models.WrapperTransaction{},
-		&models.TokenBasic{},
-		&models.PriceMarket{},
-		&models.Chain{},
-		&models.ChainFee{},
-		&models.DstSwap{},
-		&models.DstTransfer{},
-		&models.NFTProfile{},
-		&models.PolyTransaction{},
-		&models.SrcSwap{},
-		&models.SrcTransfer{},
-		&models.TimeStatistic{},
-		&models.Token{},
-		&models.TokenMap{},
-		&models.TokenStatistic{},
-		&models.ChainStatistic{},
-		&models.AssetStatistic{},
-	)
+	_, err := migrations.Up(db.Debug(), 0)
 	checkError(err, "Creating tables")
 }
 
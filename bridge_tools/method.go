@@ -23,8 +23,6 @@ import (
 	"fmt"
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/urfave/cli"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"os"
 	"poly-bridge/basedef"
@@ -34,6 +32,7 @@ import (
 	"poly-bridge/crosschaindao"
 	"poly-bridge/crosschainlisten"
 	"poly-bridge/crosschainlisten/aptoslisten"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"strconv"
 	"strings"
@@ -44,6 +43,7 @@ import (
 
 const (
 	FETCH_BLOCK = "fetch_block"
+	BACKFILL    = "backfill"
 )
 
 func executeMethod(method string, ctx *cli.Context) {
@@ -58,14 +58,18 @@ func executeMethod(method string, ctx *cli.Context) {
 	switch method {
 	case FETCH_BLOCK:
 		fetchBlock(config)
+	case BACKFILL:
+		backfill(config, ctx.GlobalBool(getFlagName(applyFlag)))
 	case "initcoinmarketid":
 		initcoinmarketid(config)
-	case "migrateLockTokenStatisticTable":
-		migrateLockTokenStatisticTable(config)
+	case "listDeadLetters":
+		listDeadLetters(config)
+	case "retryDeadLetter":
+		updateDeadLetter(config, basedef.DEAD_LETTER_PENDING)
+	case "ackDeadLetter":
+		updateDeadLetter(config, basedef.DEAD_LETTER_ACKED)
 	case "updateZilliqaPolyOldData":
 		updateZilliqaPolyOldData(config)
-	case "updateRippleTables":
-		updateRippleTables(config)
 	case "airdrop":
 		toolsmethod.AirDropNft(config)
 	case "createaccount":
@@ -78,7 +82,7 @@ func executeMethod(method string, ctx *cli.Context) {
 		updateNeo3WrapperTransactions(config)
 
 	default:
-		fmt.Printf("Available methods: \n %s", strings.Join([]string{FETCH_BLOCK}, "\n"))
+		fmt.Printf("Available methods: \n %s", strings.Join([]string{FETCH_BLOCK, BACKFILL}, "\n"))
 	}
 }
 
@@ -227,8 +231,7 @@ func initcoinmarketid(config *conf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 
 	var coinmarketsdk *coinmarketcap.CoinMarketCapSdk
 	for _, coinconfig := range config.CoinPriceListenConfig {
@@ -265,21 +268,47 @@ func initcoinmarketid(config *conf.Config) {
 	}
 }
 
-func migrateLockTokenStatisticTable(config *conf.Config) {
-	Logger := logger.Default
-	dbCfg := config.DBConfig
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
+func newDeadLetterDao(config *conf.Config) crosschaindao.DeadLetterDao {
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_BRIDGE, false, config.DBConfig)
+	if dao == nil {
+		panic("server is not valid")
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		logs.Error("Open mysql err", err)
+	deadLetterDao, ok := dao.(crosschaindao.DeadLetterDao)
+	if !ok {
+		panic("dao does not keep dead letters")
 	}
-	err = db.Debug().AutoMigrate(
-		&models.LockTokenStatistic{},
-	)
-	checkError(err, "Creating tables")
+	return deadLetterDao
+}
+
+// listDeadLetters prints the dead-lettered block ranges, DL_STATUS selects 0:pending(default) 1:resolved 2:acked
+func listDeadLetters(config *conf.Config) {
+	status, _ := strconv.Atoi(os.Getenv("DL_STATUS"))
+	blocks, err := newDeadLetterDao(config).GetDeadLetterBlocks(status)
+	checkError(err, "Get dead letters")
+	for _, block := range blocks {
+		fmt.Printf("id %d chain %d block [%d, %d] attempts %d next retry %s err: %s\n",
+			block.Id, block.ChainId, block.StartHeight, block.EndHeight, block.Attempts,
+			time.Unix(block.NextRetryTime, 0).Format(time.RFC3339), block.Error)
+	}
+	fmt.Printf("%d dead letters with status %d\n", len(blocks), status)
+}
+
+// updateDeadLetter retries the dead letter DL_ID on the next round, or acknowledges it so it is not retried any more
+func updateDeadLetter(config *conf.Config, status int) {
+	id, err := strconv.ParseInt(os.Getenv("DL_ID"), 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid param DL_ID, %v", err))
+	}
+	dao := newDeadLetterDao(config)
+	block, err := dao.GetDeadLetterBlock(id)
+	checkError(err, "Get dead letter")
+	block.Status = status
+	block.UpdateTime = time.Now().Unix()
+	if status == basedef.DEAD_LETTER_PENDING {
+		block.NextRetryTime = block.UpdateTime
+	}
+	checkError(dao.UpdateDeadLetterBlock(block), "Update dead letter")
+	fmt.Printf("dead letter %d chain %d block [%d, %d] status %d\n", block.Id, block.ChainId, block.StartHeight, block.EndHeight, block.Status)
 }
 
 func updateZilliqaPolyOldData(config *conf.Config) {
@@ -297,14 +326,13 @@ func updateZilliqaPolyOldData(config *conf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Warn)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(fmt.Sprintf("db err,%v", err))
 	}
 
 	var count int
-	err = db.Raw("select count(1) from poly_transactions where  src_chain_id=? and `key` ='' and src_hash like '%00000000' and time<?", chainId, tt).
+	err = db.Raw("select count(1) from poly_transactions where  src_chain_id=? and poly_transactions.key ='' and src_hash like '%00000000' and time<?", chainId, tt).
 		Find(&count).Error
 	if err != nil {
 		panic(fmt.Sprintf("db.Raw err,%v", err))
@@ -317,7 +345,7 @@ func updateZilliqaPolyOldData(config *conf.Config) {
 	flag := 0
 	for i := 0; i < x; i++ {
 		srcs := make([]*y, 0)
-		err = db.Raw("select id,src_hash from poly_transactions where  src_chain_id=? and `key` ='' and src_hash like '%00000000' and time<? limit 100", chainId, tt).
+		err = db.Raw("select id,src_hash from poly_transactions where  src_chain_id=? and poly_transactions.key ='' and src_hash like '%00000000' and time<? limit 100", chainId, tt).
 			Find(&srcs).Error
 		if err != nil {
 			panic(fmt.Sprintf("Find srcs err,%v", err))
@@ -343,68 +371,6 @@ func updateZilliqaPolyOldData(config *conf.Config) {
 	}
 }
 
-func updateRippleTables(config *conf.Config) {
-	Logger := logger.Default
-	dbCfg := config.DBConfig
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		logs.Error("updateRippleTables: Open mysql err", err)
-		return
-	}
-	{ //PolyTransaction dst_sequence
-		var num int
-		db.Raw("select count(*) from information_schema.columns where table_name = ? and column_name = ?", "poly_transactions", "dst_sequence").Scan(&num)
-		fmt.Println("num", num)
-
-		err = db.Debug().Migrator().AddColumn(&models.PolyTransaction{}, "dst_sequence")
-		if err != nil {
-			logs.Error("table PolyTransaction AddColumn dst_sequence err", err)
-			panic("table PolyTransaction AddColumn dst_sequence err")
-		}
-	}
-	{ //DstTransaction sequence
-		var num int
-		res := db.Debug().Raw("select count(*) from information_schema.columns where table_name = ? and column_name = ?", "dst_transactions", "sequence").Scan(&num)
-		if res.Error != nil {
-			panic("dst_transactions Raw num err")
-		}
-		if num == 0 {
-			logs.Info("dst_transactions not exist sequence")
-			err = db.Debug().Migrator().AddColumn(&models.DstTransaction{}, "sequence")
-			if err != nil {
-				logs.Error("table DstTransaction AddColumn sequence err", err)
-				panic("table DstTransaction AddColumn sequence err")
-			}
-		}
-	}
-	{ //wrapper_details
-		res := db.Debug().Migrator().HasTable(&models.WrapperDetail{})
-		if !res {
-			logs.Info("wrapper_details not exist")
-			err := db.Debug().AutoMigrate(&models.WrapperDetail{})
-			if err != nil {
-				logs.Error("table WrapperDetail AutoMigrate err", err)
-				panic("table WrapperDetail AutoMigrate err")
-			}
-		}
-	}
-	{ //poly_details
-		res := db.Debug().Migrator().HasTable(&models.PolyDetail{})
-		if !res {
-			logs.Info("poly_details not exist")
-			err := db.Debug().AutoMigrate(&models.PolyDetail{})
-			if err != nil {
-				logs.Error("table PolyDetail AutoMigrate err", err)
-				panic("table PolyDetail AutoMigrate err")
-			}
-		}
-	}
-}
-
 type wrapperUsers struct {
 	Id      int
 	Ids     int
@@ -428,13 +394,12 @@ func updateNeo3WrapperTransactions(config *conf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Warn)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(fmt.Sprintf("db err,%v", err))
 	}
 	srcs := make([]*wrapperUsers, 0)
-	err = db.Raw("select a.id,a.`user`, a.dst_user,b.id  AS ids ,b.`from` AS userf, b.dst_user AS usert from wrapper_transactions a INNER JOIN src_transfers b ON a.hash = b.tx_hash  where  a.id < ? and a.`src_chain_id` = ?", Id, chainId).
+	err = db.Raw("select a.id,a.user, a.dst_user,b.id  AS ids ,b.from AS userf, b.dst_user AS usert from wrapper_transactions a INNER JOIN src_transfers b ON a.hash = b.tx_hash  where  a.id < ? and a.src_chain_id = ?", Id, chainId).
 		Find(&srcs).Error
 	if err != nil {
 		panic(fmt.Sprintf("Find srcs err,%v", err))
@@ -445,7 +410,8 @@ func updateNeo3WrapperTransactions(config *conf.Config) {
 				continue
 			}
 			if v.Userf == basedef.HexStringReverse(v.User) && v.Usert == basedef.HexStringReverse(v.DstUser) {
-				err = db.Exec("update wrapper_transactions set `user`=?, dst_user=? where id=?", v.Userf, v.Usert, v.Id).
+				err = db.Table("wrapper_transactions").Where("id = ?", v.Id).
+					Updates(map[string]interface{}{"user": v.Userf, "dst_user": v.Usert}).
 					Error
 				if err != nil {
 					panic(fmt.Sprintf("update wrapper_transaction err,%v", err))
@@ -453,7 +419,8 @@ func updateNeo3WrapperTransactions(config *conf.Config) {
 					logs.Info("success update wrapper tx id: ", v.Id)
 				}
 			} else if v.Usert == "" {
-				err = db.Exec("update wrapper_transactions set `user`=?, dst_user=? where id=?", v.Userf, basedef.HexStringReverse(v.DstUser), v.Id).
+				err = db.Table("wrapper_transactions").Where("id = ?", v.Id).
+					Updates(map[string]interface{}{"user": v.Userf, "dst_user": basedef.HexStringReverse(v.DstUser)}).
 					Error
 				if err != nil {
 					panic(fmt.Sprintf("update wrapper_transaction err,%v", err))
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/migrations"
+	"time"
+
+	"github.com/urfave/cli"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	migrateToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "migrate up to `<version>`, or down to it, the latest version for up and the previous one for down by default",
+	}
+
+	migrateCommand = cli.Command{
+		Name:  "migrate",
+		Usage: "Show, apply or revert the versioned schema migrations of the database of the server config",
+		Subcommands: []cli.Command{
+			{
+				Name:   "status",
+				Usage:  "list the applied and the pending migrations",
+				Action: migrateStatus,
+			},
+			{
+				Name:   "up",
+				Usage:  "apply the pending migrations",
+				Flags:  []cli.Flag{migrateToFlag},
+				Action: migrateUp,
+			},
+			{
+				Name:   "down",
+				Usage:  "revert the last migration",
+				Flags:  []cli.Flag{migrateToFlag},
+				Action: migrateDown,
+			},
+		},
+	}
+)
+
+func migrateDB(ctx *cli.Context) (*gorm.DB, error) {
+	configFile := ctx.GlobalString(getFlagName(configPathFlag))
+	config := conf.NewConfig(configFile)
+	if config == nil || config.DBConfig == nil {
+		return nil, fmt.Errorf("read config %s failed", configFile)
+	}
+	Logger := logger.Default
+	if config.DBConfig.Debug {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	return dbconn.Open(config.DBConfig, Logger)
+}
+
+func migrateStatus(ctx *cli.Context) error {
+	db, err := migrateDB(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := migrations.Status(db)
+	if err != nil {
+		return err
+	}
+	for _, v := range status {
+		state := "pending"
+		if v.AppliedAt > 0 {
+			state = "applied at " + time.Unix(v.AppliedAt, 0).Format("2006-01-02 15:04:05")
+		}
+		if v.Unknown {
+			state += ", unknown to this binary"
+		}
+		fmt.Printf("%6d %-40s %s\n", v.Version, v.Name, state)
+	}
+	fmt.Printf("binary schema version: %d\n", migrations.Latest())
+	return nil
+}
+
+func migrateUp(ctx *cli.Context) error {
+	db, err := migrateDB(ctx)
+	if err != nil {
+		return err
+	}
+	done, err := migrations.Up(db, ctx.Uint64(getFlagName(migrateToFlag)))
+	for _, migration := range done {
+		fmt.Printf("applied %d %s\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(done) == 0 {
+		fmt.Println("schema is up to date")
+	}
+	return err
+}
+
+func migrateDown(ctx *cli.Context) error {
+	db, err := migrateDB(ctx)
+	if err != nil {
+		return err
+	}
+	to := ctx.Uint64(getFlagName(migrateToFlag))
+	if !ctx.IsSet(getFlagName(migrateToFlag)) {
+		status, err := migrations.Status(db)
+		if err != nil {
+			return err
+		}
+		var applied []uint64
+		for _, v := range status {
+			if v.AppliedAt > 0 && !v.Unknown {
+				applied = append(applied, v.Version)
+			}
+		}
+		if len(applied) == 0 {
+			fmt.Println("no migration is applied")
+			return nil
+		}
+		to = 0
+		if len(applied) > 1 {
+			to = applied[len(applied)-2]
+		}
+	}
+	done, err := migrations.Down(db, to)
+	for _, migration := range done {
+		fmt.Printf("reverted %d %s\n", migration.Version, migration.Name)
+	}
+	return err
+}
@@ -2,10 +2,9 @@ package toolsmethod
 
 import (
 	"fmt"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"time"
 )
@@ -16,23 +15,10 @@ func AirDrop(cfg *conf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(fmt.Sprintf("database err", err))
 	}
-	err = db.Debug().AutoMigrate(
-		&models.AirDropInfo{},
-	)
-	if err != nil {
-		panic(fmt.Sprintf("AutoMigrate AirDropInfo err:%v", err))
-	}
-	err = db.Debug().AutoMigrate(
-		&models.TokenPriceAvg{},
-	)
-	if err != nil {
-		panic(fmt.Sprintf("AutoMigrate TokenPriceAvg err:%v", err))
-	}
 	var count int64
 	err = db.Model(&models.TokenPriceAvg{}).Count(&count).
 		Error
@@ -69,8 +55,7 @@ func UpdateAirDropAmount(cfg *conf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(fmt.Sprintf("database err", err))
 	}
@@ -2,11 +2,10 @@ package toolsmethod
 
 import (
 	"fmt"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"os"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"strconv"
 )
 
@@ -34,8 +33,7 @@ func AirDropNft(cfg *conf.Config) {
 		Logger = Logger.LogMode(logger.Info)
 	}
 
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(fmt.Sprintf("database err", err))
 	}
@@ -13,6 +13,7 @@ import (
 	"os"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"strconv"
 	"strings"
@@ -20,22 +21,19 @@ import (
 
 func initAirDropNft(db *gorm.DB) {
 	logs.Info("*** start initAirDropNft ***")
-	err := db.Debug().AutoMigrate(
-		&models.AirDropNft{},
-	)
-	if err != nil {
-		panic(fmt.Sprintf("AutoMigrate AirDropInfo err:%v", err))
-	}
-
 	airDropRanks := make([]*models.AirDropRank, 0)
-	db.Table("(?) as b", db.Table("(select @curRank := 0) as r, (?) as t",
-		db.Model(&models.AirDropInfo{}).Select("sum(amount) as sum_amount, bind_addr").Group("bind_addr").Order("sum_amount desc, bind_addr")).Select("t.sum_amount as amount,t.bind_addr,@curRank := @curRank + 1 as rank")).
+	sumAmounts := db.Model(&models.AirDropInfo{}).Select("sum(amount) as sum_amount, bind_addr").Group("bind_addr").Order("sum_amount desc, bind_addr")
+	ranks := db.Table("(select @curRank := 0) as r, (?) as t", sumAmounts).Select("t.sum_amount as amount,t.bind_addr,@curRank := @curRank + 1 as rank")
+	if !dbconn.IsMysql(db) {
+		ranks = db.Table("(?) as t", sumAmounts).Select("t.sum_amount as amount,t.bind_addr,row_number() over (order by t.sum_amount desc, t.bind_addr) as rank")
+	}
+	db.Table("(?) as b", ranks).
 		Where("b.rank <= 1000").
 		Find(&airDropRanks)
 	airDropNfts := make([]*models.AirDropNft, 0)
 	for _, v := range airDropRanks {
 		var bindChain uint64
-		err = db.Model(&models.AirDropInfo{}).Select("bind_chain_id").Where("bind_addr = ?", v.BindAddr).Limit(1).Scan(&bindChain).
+		err := db.Model(&models.AirDropInfo{}).Select("bind_chain_id").Where("bind_addr = ?", v.BindAddr).Limit(1).Scan(&bindChain).
 			Error
 		if err != nil {
 			logs.Error("Select bind_chain_id err,bind_addr:", v.BindAddr, err)
@@ -54,7 +52,7 @@ func initAirDropNft(db *gorm.DB) {
 		airDropNft.NftDfId = v.Rank - 1
 		airDropNfts = append(airDropNfts, airDropNft)
 	}
-	err = db.Save(airDropNfts).Error
+	err := db.Save(airDropNfts).Error
 	if err != nil {
 		panic(fmt.Sprintf("Save airDropNfts err:%v", err))
 	}
@@ -19,13 +19,12 @@ package main
 
 import (
 	"fmt"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/bridge_tools/conf"
 	"poly-bridge/cacheRedis"
 	serverconf "poly-bridge/conf"
 	"poly-bridge/crosschaindao"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"strings"
 	"time"
@@ -37,8 +36,7 @@ func startUpdateToken(cfg *conf.DeployConfig, servercfg *serverconf.Config) {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
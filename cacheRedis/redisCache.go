@@ -8,6 +8,7 @@ import (
 	"github.com/beego/beego/v2/core/logs"
 	goredis "github.com/go-redis/redis"
 	"math/big"
+	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	"poly-bridge/models"
 	"strconv"
@@ -37,6 +38,18 @@ const (
 	RelayerAccountStatusAlarmPrefix = "RelayerAccountStatusAlarmPrefix_"
 	_ChainTVLAmount                 = "ChainTVLAmount_"
 	MarkTokenAsDying                = "MarkTokenAsDying_"
+	UnlockInvariantAlarmPrefix      = "UnlockInvariantAlarm_"
+	UnlockInvariantPausePrefix      = "UnlockInvariantPause_"
+	OutflowCountedPrefix            = "OutflowCounted_"
+	OutflowBucketPrefix             = "OutflowBucket_"
+	OutflowAlarmPrefix              = "OutflowAlarm_"
+	PriceAlarmPrefix                = "PriceAlarm_"
+	FeeAlarmPrefix                  = "FeeAlarm_"
+	CircuitBreaker                  = "CircuitBreaker"
+	CircuitBreakerLog               = "CircuitBreakerLog"
+	TransactionCountPrefix          = "TransactionCount_"
+	TransactionStatusChannel        = "TransactionStatus"
+	RateLimitPrefix                 = "RateLimit_"
 )
 
 type RedisCache struct {
@@ -104,6 +117,62 @@ func (r *RedisCache) GetCrossTxCounter() (counter int64, err error) {
 	return
 }
 
+// SetTransactionCount caches the count of a filtered transaction list for a minute
+func (r *RedisCache) SetTransactionCount(key string, count int64) (err error) {
+	if _, err = r.c.Set(TransactionCountPrefix+key, count, time.Minute).Result(); err != nil {
+		err = errors.New(err.Error() + "add SetTransactionCount")
+	}
+	return
+}
+
+func (r *RedisCache) GetTransactionCount(key string) (count int64, err error) {
+	resp, err := r.c.Get(TransactionCountPrefix + key).Result()
+	if err != nil {
+		err = errors.New(err.Error() + "cache GetTransactionCount")
+		return
+	}
+	count, err = strconv.ParseInt(resp, 10, 64)
+	if err != nil {
+		err = errors.New(err.Error() + "cache GetTransactionCount ParseInt")
+	}
+	return
+}
+
+// PublishTransactionStatus pushes the status of a wrapper transaction to the subscribers of all the http servers
+func (r *RedisCache) PublishTransactionStatus(status *models.WrapperTransactionRsp) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err = r.c.Publish(TransactionStatusChannel, string(data)).Err(); err != nil {
+		return errors.New(err.Error() + "publish PublishTransactionStatus")
+	}
+	return nil
+}
+
+// SubscribeTransactionStatus receives the published status of wrapper transactions, the subscription is kept
+// for the lifetime of the server and reconnects by itself.
+func (r *RedisCache) SubscribeTransactionStatus() (<-chan *models.WrapperTransactionRsp, error) {
+	pubSub := r.c.Subscribe(TransactionStatusChannel)
+	if _, err := pubSub.Receive(); err != nil {
+		pubSub.Close()
+		return nil, errors.New(err.Error() + "subscribe SubscribeTransactionStatus")
+	}
+	statuses := make(chan *models.WrapperTransactionRsp, 1024)
+	go func() {
+		defer close(statuses)
+		for msg := range pubSub.Channel() {
+			status := new(models.WrapperTransactionRsp)
+			if err := json.Unmarshal([]byte(msg.Payload), status); err != nil {
+				logs.Error("unmarshal transaction status %s err: %v", msg.Payload, err)
+				continue
+			}
+			statuses <- status
+		}
+	}()
+	return statuses, nil
+}
+
 func (r *RedisCache) SetAllTransferResp(resp *models.AllTransferStatisticResp) (err error) {
 	key := _TransferStatisticResp
 	jsons, err := json.Marshal(resp)
@@ -241,6 +310,16 @@ func (r *RedisCache) Lock(key string, value interface{}, expiration time.Duratio
 	return isSet, nil
 }
 
+// LockAlarm takes the key of an alarm for the interval, it is false if another server has taken it or redis is not
+// initialized, so that the alarm is sent once in the interval
+func (r *RedisCache) LockAlarm(key string, interval time.Duration) bool {
+	if r == nil {
+		return false
+	}
+	locked, err := r.Lock(key, "done", interval)
+	return err == nil && locked
+}
+
 func (r *RedisCache) UnLock(key string) (int64, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -269,6 +348,42 @@ func (r *RedisCache) SetManualTx(polyhash string, manualTx string) (err error) {
 	return
 }
 
+// IncrByFloat adds value to the float of key and refreshes its expiration
+func (r *RedisCache) IncrByFloat(key string, value float64, expiration time.Duration) error {
+	_, err := r.c.Pipelined(func(pipe goredis.Pipeliner) error {
+		pipe.IncrByFloat(key, value)
+		pipe.Expire(key, expiration)
+		return nil
+	})
+	if err != nil {
+		logs.Error("IncrByFloat key %s err: %s", key, err)
+	}
+	return err
+}
+
+// SumFloat sums the floats of the keys, the missing ones count as zero
+func (r *RedisCache) SumFloat(keys ...string) (float64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	vals, err := r.c.MGet(keys...).Result()
+	if err != nil {
+		logs.Error("MGet keys %v err: %s", keys, err)
+		return 0, err
+	}
+	sum := float64(0)
+	for _, val := range vals {
+		if s, ok := val.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return 0, err
+			}
+			sum += f
+		}
+	}
+	return sum, nil
+}
+
 func (r *RedisCache) RPush(key string, value ...interface{}) error {
 	if err := r.c.RPush(key, value).Err(); err != nil {
 		logs.Error("Redis Push[%s: %v] err: %s", key, value, err)
@@ -304,3 +419,147 @@ func (r *RedisCache) GetChainTvl(chain uint64) (amount string, err error) {
 	}
 	return resp, nil
 }
+
+// PauseCircuit pauses the chain, route or token of the circuit break. An automatic pause does not replace
+// the pause already there, it returns false then.
+func (r *RedisCache) PauseCircuit(circuit *basedef.CircuitBreak) (bool, error) {
+	data, err := json.Marshal(circuit)
+	if err != nil {
+		return false, err
+	}
+	paused := true
+	if circuit.Auto {
+		paused, err = r.c.HSetNX(CircuitBreaker, circuit.Key(), data).Result()
+	} else {
+		err = r.c.HSet(CircuitBreaker, circuit.Key(), data).Err()
+	}
+	if err != nil {
+		logs.Error("pause circuit %s err: %s", circuit.Key(), err)
+		return false, err
+	}
+	if paused {
+		r.logCircuit("pause", circuit)
+	}
+	return paused, nil
+}
+
+// ResumeCircuit resumes the chain, route or token of the circuit break, it returns false if it is not paused
+func (r *RedisCache) ResumeCircuit(circuit *basedef.CircuitBreak) (bool, error) {
+	cnt, err := r.c.HDel(CircuitBreaker, circuit.Key()).Result()
+	if err != nil {
+		logs.Error("resume circuit %s err: %s", circuit.Key(), err)
+		return false, err
+	}
+	if cnt > 0 {
+		r.logCircuit("resume", circuit)
+	}
+	return cnt > 0, nil
+}
+
+func (r *RedisCache) logCircuit(action string, circuit *basedef.CircuitBreak) {
+	logs.Warn("circuit %s %s by %s, auto: %v, reason: %s", circuit.Key(), action, circuit.Operator, circuit.Auto, circuit.Reason)
+	data, _ := json.Marshal(map[string]interface{}{"Action": action, "CircuitBreak": circuit})
+	if err := r.c.RPush(CircuitBreakerLog, data).Err(); err != nil {
+		logs.Error("log circuit %s %s err: %s", circuit.Key(), action, err)
+	}
+}
+
+// GetCircuitBreaks returns the paused circuits by key
+func (r *RedisCache) GetCircuitBreaks() (map[string]*basedef.CircuitBreak, error) {
+	vals, err := r.c.HGetAll(CircuitBreaker).Result()
+	if err != nil {
+		logs.Error("get circuit breaks err: %s", err)
+		return nil, err
+	}
+	circuits := make(map[string]*basedef.CircuitBreak, len(vals))
+	for key, val := range vals {
+		circuit := new(basedef.CircuitBreak)
+		if err := json.Unmarshal([]byte(val), circuit); err != nil {
+			logs.Error("circuit %s data Unmarshal err: %s", key, err)
+			continue
+		}
+		circuits[key] = circuit
+	}
+	return circuits, nil
+}
+
+// GetCircuitBreakLogs returns the last count pauses and resumes
+func (r *RedisCache) GetCircuitBreakLogs(count int64) ([]string, error) {
+	return r.LRange(CircuitBreakerLog, -count, -1)
+}
+
+// CheckCircuit returns the circuit break pausing the transfer of the token from the source to the destination
+// chain, or nil. An empty token basic name is not checked.
+func (r *RedisCache) CheckCircuit(srcChainId, dstChainId uint64, tokenBasicName string) (*basedef.CircuitBreak, error) {
+	circuits, err := r.GetCircuitBreaks()
+	if err != nil {
+		return nil, err
+	}
+	return MatchCircuit(circuits, srcChainId, dstChainId, tokenBasicName), nil
+}
+
+// MatchCircuit returns the circuit break of the circuits pausing the transfer as CheckCircuit, for the checks of
+// many transfers with the circuits got once
+func MatchCircuit(circuits map[string]*basedef.CircuitBreak, srcChainId, dstChainId uint64, tokenBasicName string) *basedef.CircuitBreak {
+	keys := []string{
+		basedef.ChainCircuitKey(srcChainId),
+		basedef.ChainCircuitKey(dstChainId),
+		basedef.RouteCircuitKey(srcChainId, dstChainId),
+	}
+	if tokenBasicName != "" {
+		keys = append(keys, basedef.TokenCircuitKey(tokenBasicName))
+	}
+	for _, key := range keys {
+		if circuit, ok := circuits[key]; ok {
+			return circuit
+		}
+	}
+	return nil
+}
+
+// takeTokenScript takes a token of the bucket of KEYS[1] refilled at the rate ARGV[1] per second up to the
+// burst ARGV[2] at the milliseconds ARGV[3], it returns whether it is taken and the tokens left
+var takeTokenScript = goredis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'time')
+local tokens = tonumber(bucket[1])
+local time = tonumber(bucket[2])
+if tokens == nil or time == nil then
+	tokens = burst
+	time = now
+end
+if now > time then
+	tokens = math.min(burst, tokens + (now - time) * rate / 1000)
+	time = now
+end
+local taken = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	taken = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'time', time)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {taken, tostring(tokens)}
+`)
+
+// TakeToken takes a token of the rate limit bucket of key, the buckets are shared by the http servers
+func (r *RedisCache) TakeToken(key string, rate float64, burst int64, now time.Time) (taken bool, tokens float64, err error) {
+	res, err := takeTokenScript.Run(r.c, []string{RateLimitPrefix + key}, rate, burst, now.UnixNano()/int64(time.Millisecond)).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return false, 0, fmt.Errorf("TakeToken key %s unexpected result %v", key, res)
+	}
+	left, ok := vals[1].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("TakeToken key %s unexpected result %v", key, res)
+	}
+	if tokens, err = strconv.ParseFloat(left, 64); err != nil {
+		return false, 0, err
+	}
+	return vals[0] == int64(1), tokens, nil
+}
@@ -20,12 +20,12 @@ package bridgedao
 import (
 	"fmt"
 	"github.com/polynetwork/bridge-common/metrics"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"poly-bridge/utils/decimal"
 )
@@ -43,8 +43,7 @@ func NewBridgeDao(dbCfg *conf.DBConfig) *BridgeDao {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -82,6 +81,26 @@ func (dao *BridgeDao) SaveFees(fees []*models.ChainFee) error {
 	return nil
 }
 
+func (dao *BridgeDao) GetFeeHistory(chainId uint64, limit int) ([]*models.ChainFeeHistory, error) {
+	histories := make([]*models.ChainFeeHistory, 0)
+	res := dao.db.Where("chain_id = ?", chainId).Order("time desc, id desc").Limit(limit).Find(&histories)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return histories, nil
+}
+
+func (dao *BridgeDao) SaveFeeHistory(histories []*models.ChainFeeHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	return dao.db.Create(histories).Error
+}
+
+func (dao *BridgeDao) DeleteFeeHistory(before int64) error {
+	return dao.db.Where("time < ?", before).Delete(&models.ChainFeeHistory{}).Error
+}
+
 func (dao *BridgeDao) Name() string {
 	return basedef.SERVER_POLY_BRIDGE
 }
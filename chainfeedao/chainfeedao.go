@@ -29,6 +29,11 @@ import (
 type ChainFeeDao interface {
 	GetFees() ([]*models.ChainFee, error)
 	SaveFees(fees []*models.ChainFee) error
+	// GetFeeHistory returns the latest samples of the chain fee, latest first
+	GetFeeHistory(chainId uint64, limit int) ([]*models.ChainFeeHistory, error)
+	SaveFeeHistory(histories []*models.ChainFeeHistory) error
+	// DeleteFeeHistory deletes the samples before the time
+	DeleteFeeHistory(before int64) error
 	Name() string
 }
 
@@ -25,7 +25,8 @@ import (
 )
 
 type StakeDao struct {
-	fees []*models.ChainFee
+	fees      []*models.ChainFee
+	histories []*models.ChainFeeHistory
 }
 
 func NewStakeDao() *StakeDao {
@@ -51,6 +52,32 @@ func (dao *StakeDao) SaveFees(fees []*models.ChainFee) error {
 	return nil
 }
 
+func (dao *StakeDao) GetFeeHistory(chainId uint64, limit int) ([]*models.ChainFeeHistory, error) {
+	histories := make([]*models.ChainFeeHistory, 0)
+	for i := len(dao.histories) - 1; i >= 0 && len(histories) < limit; i-- {
+		if dao.histories[i].ChainId == chainId {
+			histories = append(histories, dao.histories[i])
+		}
+	}
+	return histories, nil
+}
+
+func (dao *StakeDao) SaveFeeHistory(histories []*models.ChainFeeHistory) error {
+	dao.histories = append(dao.histories, histories...)
+	return nil
+}
+
+func (dao *StakeDao) DeleteFeeHistory(before int64) error {
+	histories := make([]*models.ChainFeeHistory, 0, len(dao.histories))
+	for _, history := range dao.histories {
+		if history.Time >= before {
+			histories = append(histories, history)
+		}
+	}
+	dao.histories = histories
+	return nil
+}
+
 func (dao *StakeDao) Name() string {
 	return basedef.SERVER_STAKE
 }
@@ -19,11 +19,11 @@ package swapdao
 
 import (
 	"fmt"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 )
 
@@ -40,8 +40,7 @@ func NewSwapDao(dbCfg *conf.DBConfig) *SwapDao {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -70,6 +69,26 @@ func (dao *SwapDao) SaveFees(fees []*models.ChainFee) error {
 	return nil
 }
 
+func (dao *SwapDao) GetFeeHistory(chainId uint64, limit int) ([]*models.ChainFeeHistory, error) {
+	histories := make([]*models.ChainFeeHistory, 0)
+	res := dao.db.Where("chain_id = ?", chainId).Order("time desc, id desc").Limit(limit).Find(&histories)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return histories, nil
+}
+
+func (dao *SwapDao) SaveFeeHistory(histories []*models.ChainFeeHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	return dao.db.Create(histories).Error
+}
+
+func (dao *SwapDao) DeleteFeeHistory(before int64) error {
+	return dao.db.Where("time < ?", before).Delete(&models.ChainFeeHistory{}).Error
+}
+
 func (dao *SwapDao) Name() string {
 	return basedef.SERVER_POLY_SWAP
 }
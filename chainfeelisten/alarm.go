@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainfeelisten
+
+import (
+	"fmt"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/common"
+	"poly-bridge/models"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// the alarm of a chain whose fee changes beyond the bound is sent once in the interval
+const feeAlarmInterval = time.Hour
+
+// alarmFee alarms the sample alerted against the max fee published before it, once in feeAlarmInterval among the servers
+func alarmFee(sample *models.ChainFeeHistory, publishedMaxFee *models.BigInt) {
+	title := fmt.Sprintf("*Fee Alarm!!! Chain %d*\n", sample.ChainId)
+	text := fmt.Sprintf("%s\nThe fee changes beyond the bound of the smoothing\n*Max Fee*: %s\n*Published Max Fee*: %s\n*Time*: %s\n%s",
+		title,
+		sample.MaxFee.String(),
+		feeInt(publishedMaxFee).String(),
+		time.Now().Format("2006-01-02 15:04:05"),
+		"-----------------------------------------",
+	)
+	key := fmt.Sprintf("%s%d", cacheRedis.FeeAlarmPrefix, sample.ChainId)
+	if err := common.SendTgAlarmOnce(cacheRedis.Redis, key, feeAlarmInterval, text); err != nil {
+		logs.Error("send fee alarm of chain %d failed, err: %v", sample.ChainId, err)
+	}
+}
@@ -18,11 +18,14 @@
 package ethereumfee
 
 import (
-	"errors"
+	"fmt"
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/chainsdk"
 	"poly-bridge/conf"
+	"sort"
+
+	"github.com/beego/beego/v2/core/logs"
 )
 
 type EthereumFee struct {
@@ -40,22 +43,19 @@ func NewEthereumFee(ethCfg *conf.FeeListenConfig, feeUpdateSlot int64) *Ethereum
 	return ethereumFee
 }
 
+const (
+	defaultFeeHistoryBlocks   = 20
+	defaultPriorityPercentile = 50
+)
+
 func (this *EthereumFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
-	gasPrice, err := this.ethSdk.SuggestGasPrice()
+	gasPrice, err := this.gasPrice()
 	if err != nil {
 		return nil, nil, nil, err
 	}
-
-	// astar average price is 60 Gwei while node returns 1 Gwei
-	if this.GetChainId() == basedef.ASTAR_CROSSCHAIN_ID {
-		if gasPrice.Cmp(big.NewInt(basedef.ASTAR_NORMAL_GASPRICE)) < 0 {
-			gasPrice = big.NewInt(basedef.ASTAR_NORMAL_GASPRICE)
-		}
-	}
-
-	//bsc mainnet gasprice normal 5Gwei
-	if this.GetChainId() == basedef.BSC_CROSSCHAIN_ID && gasPrice.Cmp(big.NewInt(basedef.BSC_NORMAL_GASPRICE*0.84)) < 0 {
-		return nil, nil, nil, errors.New("BSC fee no need to update")
+	gasPrice, err = applyGasPricePolicy(this.ethCfg, gasPrice)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(basedef.FEE_PRECISION))
 	gasPrice = new(big.Int).Mul(gasPrice, big.NewInt(this.ethCfg.GasLimit))
@@ -66,6 +66,74 @@ func (this *EthereumFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
 	return minFee, gasPrice, proxyFee, nil
 }
 
+// gasPrice prices the gas by eth_feeHistory if the chain is configured with eip1559, otherwise or if the chain does
+// not support it by eth_gasPrice
+func (this *EthereumFee) gasPrice() (*big.Int, error) {
+	if this.ethCfg.Eip1559 {
+		blocks := this.ethCfg.FeeHistoryBlocks
+		if blocks <= 0 {
+			blocks = defaultFeeHistoryBlocks
+		}
+		percentile := this.ethCfg.PriorityPercentile
+		if percentile <= 0 {
+			percentile = defaultPriorityPercentile
+		}
+		history, err := this.ethSdk.FeeHistory(blocks, []float64{percentile})
+		if err != nil {
+			logs.Warn("chain %d fee history err: %v, use the legacy gas price", this.GetChainId(), err)
+		} else if gasPrice := eip1559GasPrice(history); gasPrice != nil {
+			return gasPrice, nil
+		} else {
+			logs.Warn("chain %d has no base fee, use the legacy gas price", this.GetChainId())
+		}
+	}
+	return this.ethSdk.SuggestGasPrice()
+}
+
+// eip1559GasPrice is the highest base fee of the blocks and the next block, so that a rising base fee is not
+// under-quoted, plus the median of the priority fee percentiles of the blocks. It returns nil without base fees.
+func eip1559GasPrice(history *chainsdk.FeeHistory) *big.Int {
+	baseFee := new(big.Int)
+	for _, fee := range history.BaseFee {
+		if fee != nil && fee.ToInt().Cmp(baseFee) > 0 {
+			baseFee = fee.ToInt()
+		}
+	}
+	if baseFee.Sign() == 0 {
+		return nil
+	}
+	rewards := make([]*big.Int, 0, len(history.Reward))
+	for _, reward := range history.Reward {
+		if len(reward) > 0 && reward[0] != nil {
+			rewards = append(rewards, reward[0].ToInt())
+		}
+	}
+	if len(rewards) == 0 {
+		return new(big.Int).Set(baseFee)
+	}
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	return new(big.Int).Add(baseFee, rewards[len(rewards)/2])
+}
+
+// applyGasPricePolicy raises the gas price to the floor and caps it to the ceiling of the chain, and skips the
+// update of the fee when the gas price is lower than the skip price. The floor of astar is 60 gwei by default.
+func applyGasPricePolicy(cfg *conf.FeeListenConfig, gasPrice *big.Int) (*big.Int, error) {
+	minGasPrice := cfg.MinGasPrice
+	if minGasPrice == 0 && cfg.ChainId == basedef.ASTAR_CROSSCHAIN_ID {
+		minGasPrice = basedef.ASTAR_NORMAL_GASPRICE
+	}
+	if minGasPrice > 0 && gasPrice.Cmp(big.NewInt(minGasPrice)) < 0 {
+		gasPrice = big.NewInt(minGasPrice)
+	}
+	if cfg.MaxGasPrice > 0 && gasPrice.Cmp(big.NewInt(cfg.MaxGasPrice)) > 0 {
+		gasPrice = big.NewInt(cfg.MaxGasPrice)
+	}
+	if cfg.SkipGasPrice > 0 && gasPrice.Cmp(big.NewInt(cfg.SkipGasPrice)) < 0 {
+		return nil, fmt.Errorf("%s fee no need to update, gas price %s is lower than %d", cfg.ChainName, gasPrice.String(), cfg.SkipGasPrice)
+	}
+	return gasPrice, nil
+}
+
 func (this *EthereumFee) GetChainId() uint64 {
 	return this.ethCfg.ChainId
 }
@@ -0,0 +1,56 @@
+package ethereumfee
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+)
+
+func gwei(n int64) *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Mul(big.NewInt(n), big.NewInt(1000000000)))
+}
+
+func TestEip1559GasPrice(t *testing.T) {
+	// the base fee rises in the next block
+	history := &chainsdk.FeeHistory{
+		BaseFee: []*hexutil.Big{gwei(10), gwei(12), gwei(15)},
+		Reward:  [][]*hexutil.Big{{gwei(3)}, {gwei(1)}},
+	}
+	assert.Equal(t, gwei(18).ToInt(), eip1559GasPrice(history))
+	history.Reward = [][]*hexutil.Big{{gwei(3)}, {gwei(1)}, {gwei(2)}}
+	assert.Equal(t, gwei(17).ToInt(), eip1559GasPrice(history))
+	history.Reward = nil
+	assert.Equal(t, gwei(15).ToInt(), eip1559GasPrice(history))
+
+	// the chain has no base fee
+	assert.Nil(t, eip1559GasPrice(&chainsdk.FeeHistory{BaseFee: []*hexutil.Big{gwei(0), gwei(0)}}))
+	assert.Nil(t, eip1559GasPrice(&chainsdk.FeeHistory{}))
+}
+
+func TestApplyGasPricePolicy(t *testing.T) {
+	cfg := &conf.FeeListenConfig{ChainName: "BSC", MinGasPrice: 5, MaxGasPrice: 100, SkipGasPrice: 10}
+	_, err := applyGasPricePolicy(cfg, big.NewInt(1))
+	assert.Error(t, err)
+	gasPrice, err := applyGasPricePolicy(cfg, big.NewInt(200))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), gasPrice)
+
+	cfg = &conf.FeeListenConfig{ChainName: "Astar", MinGasPrice: 60}
+	gasPrice, err = applyGasPricePolicy(cfg, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(60), gasPrice)
+	gasPrice, err = applyGasPricePolicy(cfg, big.NewInt(1000))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), gasPrice)
+
+	// astar is raised to 60 gwei without the floor configured
+	cfg = &conf.FeeListenConfig{ChainId: basedef.ASTAR_CROSSCHAIN_ID, ChainName: "Astar"}
+	gasPrice, err = applyGasPricePolicy(cfg, big.NewInt(1000000000))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(basedef.ASTAR_NORMAL_GASPRICE), gasPrice)
+}
@@ -0,0 +1,83 @@
+package ethereumfee
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"poly-bridge/basedef"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// the oracles of the L1 data fees of the layer 2 chains. Arbitrum has none, as it charges the L1 data in the gas of
+// the transactions, which its gas limits already cover
+const (
+	L1FeeOracleOptimism = "optimism"
+)
+
+const defaultL1DataSize = 1024
+
+// the predeploys of the oracles
+var l1FeeOracleAddresses = map[string]string{
+	L1FeeOracleOptimism: "0x420000000000000000000000000000000000000F",
+}
+
+// getL1Fee of the gas price oracle of the op stack chains
+const l1FeeOracleAbiJson = `[
+	{"name":"getL1Fee","type":"function","stateMutability":"view","inputs":[{"name":"_data","type":"bytes"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var l1FeeOracleAbi abi.ABI
+
+func init() {
+	var err error
+	l1FeeOracleAbi, err = abi.JSON(strings.NewReader(l1FeeOracleAbiJson))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetL1Fee reads the L1 data fee of an unlock on the chain from its oracle, multiplied by the fee precision as the
+// fees of GetFee. It is nil if the chain has no oracle.
+func (this *EthereumFee) GetL1Fee() (*big.Int, error) {
+	if this.ethCfg.L1FeeOracle == "" {
+		return nil, nil
+	}
+	address := this.ethCfg.L1FeeOracleAddress
+	if address == "" {
+		address = l1FeeOracleAddresses[this.ethCfg.L1FeeOracle]
+	}
+	if address == "" {
+		return nil, fmt.Errorf("unknown L1 fee oracle %s", this.ethCfg.L1FeeOracle)
+	}
+	size := this.ethCfg.L1DataSize
+	if size <= 0 {
+		size = defaultL1DataSize
+	}
+	oracle := common.HexToAddress(address)
+
+	var l1Fee *big.Int
+	switch this.ethCfg.L1FeeOracle {
+	case L1FeeOracleOptimism:
+		// the calldata of the size without zero bytes, which costs the most
+		data, err := l1FeeOracleAbi.Pack("getL1Fee", bytes.Repeat([]byte{0xff}, int(size)))
+		if err != nil {
+			return nil, err
+		}
+		result, err := this.ethSdk.CallContract(ethereum.CallMsg{To: &oracle, Data: data})
+		if err != nil {
+			return nil, err
+		}
+		values, err := l1FeeOracleAbi.Unpack("getL1Fee", result)
+		if err != nil {
+			return nil, err
+		}
+		l1Fee = values[0].(*big.Int)
+	default:
+		return nil, fmt.Errorf("unknown L1 fee oracle %s", this.ethCfg.L1FeeOracle)
+	}
+	return new(big.Int).Mul(l1Fee, big.NewInt(basedef.FEE_PRECISION)), nil
+}
@@ -73,36 +73,41 @@ type ChainFee interface {
 	Name() string
 }
 
+// L1ChainFee is a ChainFee of a layer 2 chain which reads the L1 data fee of the unlocks from an oracle
+type L1ChainFee interface {
+	GetL1Fee() (*big.Int, error)
+}
+
+type ChainFeeFactory func(cfg *conf.FeeListenConfig, feeUpdateSlot int64) ChainFee
+
+func newEthereumFee(cfg *conf.FeeListenConfig, feeUpdateSlot int64) ChainFee {
+	return ethereumfee.NewEthereumFee(cfg, feeUpdateSlot)
+}
+
+var chainFeeFactories = map[string]ChainFeeFactory{
+	basedef.FAMILY_EVM:      newEthereumFee,
+	basedef.FAMILY_ONTEVM:   newEthereumFee,
+	basedef.FAMILY_NEO:      func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return neofee.NewNeoFee(cfg, slot) },
+	basedef.FAMILY_NEO3:     func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return neo3fee.NewNeo3Fee(cfg, slot) },
+	basedef.FAMILY_ONTOLOGY: func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return ontologyfee.NewOntologyFee(cfg, slot) },
+	basedef.FAMILY_SWITCHEO: func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return switcheofee.NewSwitcheoFee(cfg, slot) },
+	basedef.FAMILY_ZILLIQA:  func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return zilliqafee.NewZilliqaFee(cfg, slot) },
+	basedef.FAMILY_STARCOIN: func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return starcoinfee.NewStarcoinFee(cfg, slot) },
+	basedef.FAMILY_RIPPLE:   func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return ripplefee.NewRippleFee(cfg, slot) },
+	basedef.FAMILY_APTOS:    func(cfg *conf.FeeListenConfig, slot int64) ChainFee { return aptosfee.NewAptosFee(cfg, slot) },
+}
+
+// RegisterChainFee sets the fee source factory of a chain family
+func RegisterChainFee(family string, factory ChainFeeFactory) {
+	chainFeeFactories[family] = factory
+}
+
 func NewChainFee(cfg *conf.FeeListenConfig, feeUpdateSlot int64) ChainFee {
-	switch cfg.ChainId {
-	case basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.HECO_CROSSCHAIN_ID, basedef.OK_CROSSCHAIN_ID,
-		basedef.MATIC_CROSSCHAIN_ID, basedef.PLT_CROSSCHAIN_ID, basedef.ARBITRUM_CROSSCHAIN_ID, basedef.XDAI_CROSSCHAIN_ID,
-		basedef.FANTOM_CROSSCHAIN_ID, basedef.AVAX_CROSSCHAIN_ID, basedef.OPTIMISTIC_CROSSCHAIN_ID, basedef.METIS_CROSSCHAIN_ID,
-		basedef.BOBA_CROSSCHAIN_ID, basedef.RINKEBY_CROSSCHAIN_ID, basedef.OASIS_CROSSCHAIN_ID, basedef.HARMONY_CROSSCHAIN_ID,
-		basedef.KCC_CROSSCHAIN_ID, basedef.BYTOM_CROSSCHAIN_ID, basedef.HSC_CROSSCHAIN_ID, basedef.KAVA_CROSSCHAIN_ID,
-		basedef.CUBE_CROSSCHAIN_ID, basedef.ZKSYNC_CROSSCHAIN_ID, basedef.CELO_CROSSCHAIN_ID, basedef.CLOVER_CROSSCHAIN_ID,
-		basedef.CONFLUX_CROSSCHAIN_ID, basedef.ASTAR_CROSSCHAIN_ID, basedef.BRISE_CROSSCHAIN_ID, basedef.DEXIT_CROSSCHAIN_ID,
-		basedef.CLOUDTX_CROSSCHAIN_ID, basedef.XINFIN_CROSSCHAIN_ID, basedef.ONTEVM_CROSSCHAIN_ID:
-		return ethereumfee.NewEthereumFee(cfg, feeUpdateSlot)
-	case basedef.NEO_CROSSCHAIN_ID:
-		return neofee.NewNeoFee(cfg, feeUpdateSlot)
-	case basedef.NEO3_CROSSCHAIN_ID:
-		return neo3fee.NewNeo3Fee(cfg, feeUpdateSlot)
-	case basedef.ONT_CROSSCHAIN_ID:
-		return ontologyfee.NewOntologyFee(cfg, feeUpdateSlot)
-	case basedef.SWITCHEO_CROSSCHAIN_ID:
-		return switcheofee.NewSwitcheoFee(cfg, feeUpdateSlot)
-	case basedef.ZILLIQA_CROSSCHAIN_ID:
-		return zilliqafee.NewZilliqaFee(cfg, feeUpdateSlot)
-	case basedef.STARCOIN_CROSSCHAIN_ID:
-		return starcoinfee.NewStarcoinFee(cfg, feeUpdateSlot)
-	case basedef.RIPPLE_CROSSCHAIN_ID:
-		return ripplefee.NewRippleFee(cfg, feeUpdateSlot)
-	case basedef.APTOS_CROSSCHAIN_ID:
-		return aptosfee.NewAptosFee(cfg, feeUpdateSlot)
-	default:
+	factory, ok := chainFeeFactories[basedef.GetChainFamily(cfg.ChainId)]
+	if !ok {
 		return nil
 	}
+	return factory(cfg, feeUpdateSlot)
 }
 
 type FeeListen struct {
@@ -126,7 +131,7 @@ func NewFeeListen(feeUpdateSlot int64, fees []ChainFee, db chainfeedao.ChainFeeD
 	if err != nil {
 		panic(err)
 	}
-	err = feeListen.updateChainFees(chainFees)
+	histories, err := feeListen.updateChainFees(chainFees)
 	if err != nil {
 		panic(err)
 	}
@@ -134,6 +139,7 @@ func NewFeeListen(feeUpdateSlot int64, fees []ChainFee, db chainfeedao.ChainFeeD
 	if err != nil {
 		panic(err)
 	}
+	feeListen.saveFeeHistory(histories)
 	return feeListen
 }
 
@@ -186,7 +192,7 @@ func (fl *FeeListen) listenFee() (exit bool) {
 					logs.Error("get chain fees err: %v", err)
 					continue
 				}
-				err = fl.updateChainFees(chainFees)
+				histories, err := fl.updateChainFees(chainFees)
 				if err != nil {
 					logs.Error("updateChainFees err: %v", err)
 					continue
@@ -196,6 +202,7 @@ func (fl *FeeListen) listenFee() (exit bool) {
 					logs.Error("save fees err: %v", err)
 					continue
 				}
+				fl.saveFeeHistory(histories)
 				break
 			}
 		case <-fl.exit:
@@ -205,7 +212,11 @@ func (fl *FeeListen) listenFee() (exit bool) {
 	}
 }
 
-func (fl *FeeListen) updateChainFees(chainFees []*models.ChainFee) error {
+// updateChainFees publishes the fees read of the chains smoothed by the smoothing config, and returns the samples of them
+func (fl *FeeListen) updateChainFees(chainFees []*models.ChainFee) ([]*models.ChainFeeHistory, error) {
+	cfg := smoothingConfig()
+	now := time.Now().Unix()
+	histories := make([]*models.ChainFeeHistory, 0)
 	chainFee := make(map[uint64]*models.ChainFee, 0)
 	for _, fee := range chainFees {
 		chainFee[fee.ChainId] = fee
@@ -223,10 +234,37 @@ func (fl *FeeListen) updateChainFees(chainFees []*models.ChainFee) error {
 			continue
 		}
 		logs.Info("get fee of chain: %d successful", chainId)
-		fee.MinFee = models.NewBigInt(minFee)
-		fee.MaxFee = models.NewBigInt(maxFee)
-		fee.ProxyFee = models.NewBigInt(proxyFee)
-		fee.Time = time.Now().Unix()
+		sample := &models.ChainFeeHistory{
+			ChainId:  chainId,
+			Time:     now,
+			MinFee:   models.NewBigInt(minFee),
+			MaxFee:   models.NewBigInt(maxFee),
+			ProxyFee: models.NewBigInt(proxyFee),
+		}
+		samples, err := fl.db.GetFeeHistory(chainId, cfg.Window+cfg.MaxRejects)
+		if err != nil {
+			logs.Error("get fee history of chain: %d err: %v", chainId, err)
+		}
+		smoothFee(cfg, fee, sample, samples)
+		if sample.State == models.FeeSampleAlerted {
+			alarmFee(sample, fee.MaxFee)
+		}
+		fee.MinFee = sample.PublishedMinFee
+		fee.MaxFee = sample.PublishedMaxFee
+		fee.ProxyFee = sample.PublishedProxyFee
+		if l1Query, ok := query.(L1ChainFee); ok {
+			// the last L1 fee read is kept when the oracle fails
+			l1Fee, err := l1Query.GetL1Fee()
+			if err != nil {
+				logs.Error("get L1 fee of chain: %d err: %v", chainId, err)
+			} else if l1Fee != nil {
+				fee.L1Fee = models.NewBigInt(l1Fee)
+				fee.L1FeeTime = now
+			}
+		}
+		sample.L1Fee = models.NewBigInt(feeInt(fee.L1Fee))
+		histories = append(histories, sample)
+		fee.Time = now
 		fee.Ind = 1
 	}
 	for _, fee := range chainFees {
@@ -234,7 +272,18 @@ func (fl *FeeListen) updateChainFees(chainFees []*models.ChainFee) error {
 			logs.Error("fee of chain %d is not update", fee.ChainId)
 		}
 	}
-	return nil
+	return histories, nil
+}
+
+// saveFeeHistory saves the samples of the chain fees and deletes the ones out of the retention, the published fees
+// are not affected by its errors
+func (fl *FeeListen) saveFeeHistory(histories []*models.ChainFeeHistory) {
+	if err := fl.db.SaveFeeHistory(histories); err != nil {
+		logs.Error("save fee history err: %v", err)
+	}
+	if err := fl.db.DeleteFeeHistory(time.Now().Unix() - smoothingConfig().Retention); err != nil {
+		logs.Error("delete fee history err: %v", err)
+	}
 }
 
 func (fl *FeeListen) GetChainFees() string {
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package chainfeelisten
+
+import (
+	"math/big"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"sort"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// the smoothing methods of the published fees
+const (
+	SmoothingEma    = "ema"
+	SmoothingMedian = "median"
+)
+
+// the actions on the samples beyond the bound of the change
+const (
+	SpikeReject = "reject"
+	SpikeAlert  = "alert"
+)
+
+const (
+	defaultSmoothingWindow     = 5
+	defaultSmoothingMaxRejects = 3
+	defaultFeeHistoryRetention = 2592000
+)
+
+// smoothingConfig returns the smoothing config with the defaults, which publishes the fees as read without the config
+func smoothingConfig() *conf.FeeSmoothingConfig {
+	cfg := conf.FeeSmoothingConfig{}
+	if conf.GlobalConfig != nil && conf.GlobalConfig.FeeSmoothingConfig != nil {
+		cfg = *conf.GlobalConfig.FeeSmoothingConfig
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultSmoothingWindow
+	}
+	if cfg.SpikeAction == "" {
+		cfg.SpikeAction = SpikeReject
+	}
+	if cfg.MaxRejects <= 0 {
+		cfg.MaxRejects = defaultSmoothingMaxRejects
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultFeeHistoryRetention
+	}
+	return &cfg
+}
+
+func feeInt(fee *models.BigInt) *big.Int {
+	if fee == nil {
+		return big.NewInt(0)
+	}
+	return &fee.Int
+}
+
+// isSpike reports whether the fee read changes from the published one by more than maxChange percent
+func isSpike(published, read *big.Int, maxChange int64) bool {
+	if maxChange <= 0 || published.Sign() <= 0 {
+		return false
+	}
+	change := new(big.Int).Sub(read, published)
+	change.Abs(change).Mul(change, big.NewInt(100))
+	return change.Cmp(new(big.Int).Mul(published, big.NewInt(maxChange))) > 0
+}
+
+// ema moves the published fee towards the fee read by 2/(window+1)
+func ema(published, read *big.Int, window int) *big.Int {
+	if published.Sign() <= 0 {
+		return new(big.Int).Set(read)
+	}
+	fee := new(big.Int).Mul(read, big.NewInt(2))
+	fee.Add(fee, new(big.Int).Mul(published, big.NewInt(int64(window-1))))
+	return fee.Div(fee, big.NewInt(int64(window+1)))
+}
+
+func median(fees []*big.Int) *big.Int {
+	sorted := make([]*big.Int, len(fees))
+	copy(sorted, fees)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return new(big.Int).Set(sorted[mid])
+	}
+	fee := new(big.Int).Add(sorted[mid-1], sorted[mid])
+	return fee.Div(fee, big.NewInt(2))
+}
+
+// smoothFee sets the state of the sample and the fees published with it, from the published chain fee and the
+// latest samples of the chain, latest first. A sample beyond MaxChange is rejected, unless MaxRejects samples
+// before it are rejected in a row, which means the fee has moved to a new level.
+func smoothFee(cfg *conf.FeeSmoothingConfig, fee *models.ChainFee, sample *models.ChainFeeHistory, samples []*models.ChainFeeHistory) {
+	sample.State = models.FeeSampleAccepted
+	if isSpike(feeInt(fee.MaxFee), feeInt(sample.MaxFee), cfg.MaxChange) {
+		rejects := 0
+		for rejects < len(samples) && samples[rejects].State == models.FeeSampleRejected {
+			rejects++
+		}
+		if cfg.SpikeAction != SpikeAlert && rejects < cfg.MaxRejects {
+			logs.Error("fee of chain %d is rejected, max fee: %s, published max fee: %s", fee.ChainId, sample.MaxFee.String(), feeInt(fee.MaxFee).String())
+			sample.State = models.FeeSampleRejected
+			sample.PublishedMinFee = models.NewBigInt(feeInt(fee.MinFee))
+			sample.PublishedMaxFee = models.NewBigInt(feeInt(fee.MaxFee))
+			sample.PublishedProxyFee = models.NewBigInt(feeInt(fee.ProxyFee))
+			return
+		}
+		logs.Error("fee of chain %d changes beyond %d%%, max fee: %s, published max fee: %s", fee.ChainId, cfg.MaxChange, sample.MaxFee.String(), feeInt(fee.MaxFee).String())
+		sample.State = models.FeeSampleAlerted
+	}
+
+	switch cfg.Method {
+	case SmoothingEma:
+		sample.PublishedMinFee = models.NewBigInt(ema(feeInt(fee.MinFee), feeInt(sample.MinFee), cfg.Window))
+		sample.PublishedMaxFee = models.NewBigInt(ema(feeInt(fee.MaxFee), feeInt(sample.MaxFee), cfg.Window))
+		sample.PublishedProxyFee = models.NewBigInt(ema(feeInt(fee.ProxyFee), feeInt(sample.ProxyFee), cfg.Window))
+	case SmoothingMedian:
+		minFees := []*big.Int{feeInt(sample.MinFee)}
+		maxFees := []*big.Int{feeInt(sample.MaxFee)}
+		proxyFees := []*big.Int{feeInt(sample.ProxyFee)}
+		for _, history := range samples {
+			if len(minFees) >= cfg.Window {
+				break
+			}
+			if history.State == models.FeeSampleRejected {
+				continue
+			}
+			minFees = append(minFees, feeInt(history.MinFee))
+			maxFees = append(maxFees, feeInt(history.MaxFee))
+			proxyFees = append(proxyFees, feeInt(history.ProxyFee))
+		}
+		sample.PublishedMinFee = models.NewBigInt(median(minFees))
+		sample.PublishedMaxFee = models.NewBigInt(median(maxFees))
+		sample.PublishedProxyFee = models.NewBigInt(median(proxyFees))
+	default:
+		sample.PublishedMinFee = models.NewBigInt(feeInt(sample.MinFee))
+		sample.PublishedMaxFee = models.NewBigInt(feeInt(sample.MaxFee))
+		sample.PublishedProxyFee = models.NewBigInt(feeInt(sample.ProxyFee))
+	}
+}
@@ -0,0 +1,68 @@
+package chainfeelisten
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/chainfeedao/stakedao"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+)
+
+type fakeChainFee struct {
+	chainId uint64
+	fees    []int64
+}
+
+func (fee *fakeChainFee) GetFee() (*big.Int, *big.Int, *big.Int, error) {
+	read := big.NewInt(fee.fees[0])
+	fee.fees = fee.fees[1:]
+	return new(big.Int).Div(read, big.NewInt(2)), read, new(big.Int).Mul(read, big.NewInt(2)), nil
+}
+
+func (fee *fakeChainFee) GetChainId() uint64 {
+	return fee.chainId
+}
+
+func (fee *fakeChainFee) Name() string {
+	return "fake"
+}
+
+// publishedFees updates the chain fees with the fees read and returns the published max fees
+func publishedFees(t *testing.T, fees []int64) []int64 {
+	query := &fakeChainFee{chainId: 2, fees: fees}
+	listen := NewFeeListen(1, []ChainFee{query}, stakedao.NewStakeDao())
+	chainFees, err := listen.db.GetFees()
+	assert.NoError(t, err)
+	published := []int64{chainFees[0].MaxFee.Int64()}
+	for len(query.fees) > 0 {
+		histories, err := listen.updateChainFees(chainFees)
+		assert.NoError(t, err)
+		listen.saveFeeHistory(histories)
+		published = append(published, chainFees[0].MaxFee.Int64())
+	}
+	return published
+}
+
+func TestSmoothFee(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{}
+	assert.Equal(t, []int64{100, 120, 1000}, publishedFees(t, []int64{100, 120, 1000}))
+
+	// the samples beyond 50% are rejected until 2 of them are rejected in a row
+	conf.GlobalConfig.FeeSmoothingConfig = &conf.FeeSmoothingConfig{Method: SmoothingMedian, Window: 3, MaxChange: 50, MaxRejects: 2}
+	assert.Equal(t, []int64{100, 120, 120, 100, 100, 100, 140}, publishedFees(t, []int64{100, 140, 1000, 80, 1000, 1000, 1000}))
+
+	conf.GlobalConfig.FeeSmoothingConfig = &conf.FeeSmoothingConfig{Method: SmoothingEma, Window: 3, MaxChange: 50, SpikeAction: SpikeAlert}
+	assert.Equal(t, []int64{100, 120, 560}, publishedFees(t, []int64{100, 140, 1000}))
+
+	fee := &models.ChainFee{ChainId: 2, MinFee: models.NewBigIntFromInt(50), MaxFee: models.NewBigIntFromInt(100), ProxyFee: models.NewBigIntFromInt(200)}
+	sample := &models.ChainFeeHistory{ChainId: 2, MinFee: models.NewBigIntFromInt(500), MaxFee: models.NewBigIntFromInt(1000), ProxyFee: models.NewBigIntFromInt(2000)}
+	cfg := &conf.FeeSmoothingConfig{MaxChange: 50, MaxRejects: 1}
+	smoothFee(cfg, fee, sample, nil)
+	assert.Equal(t, models.FeeSampleRejected, sample.State)
+	assert.Equal(t, int64(200), sample.PublishedProxyFee.Int64())
+	smoothFee(cfg, fee, sample, []*models.ChainFeeHistory{{State: models.FeeSampleRejected}})
+	assert.Equal(t, models.FeeSampleAlerted, sample.State)
+	assert.Equal(t, int64(2000), sample.PublishedProxyFee.Int64())
+}
@@ -186,6 +186,27 @@ func (s *EthereumSdk) SuggestGasPrice() (*big.Int, error) {
 	return gasPrice, err
 }
 
+// FeeHistory is the result of eth_feeHistory, BaseFee has the base fee of the next block after the blocks
+type FeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+func (s *EthereumSdk) FeeHistory(blocks int, percentiles []float64) (*FeeHistory, error) {
+	var history FeeHistory
+	err := s.rpcClient.CallContext(context.Background(), &history, "eth_feeHistory", hexutil.Uint(blocks), "latest", percentiles)
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (s *EthereumSdk) CallContract(msg ethereum.CallMsg) ([]byte, error) {
+	return s.rawClient.CallContract(context.Background(), msg, nil)
+}
+
 func (s *EthereumSdk) EstimateGas(msg ethereum.CallMsg) (uint64, error) {
 	gasLimit, err := s.rawClient.EstimateGas(context.Background(), msg)
 	for err != nil {
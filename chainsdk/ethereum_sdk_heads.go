@@ -0,0 +1,97 @@
+package chainsdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// a subscription without any head for this long is treated as dropped
+const newHeadTimeout = time.Minute
+
+func isWebsocketUrl(url string) bool {
+	url = strings.ToLower(url)
+	return strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://")
+}
+
+// SubscribeNewHeads subscribes eth_subscribe("newHeads") on the websocket nodes. While the
+// subscription of a node is alive its height follows the heads and is no longer polled, when
+// it drops the node falls back to polling until the subscription is made again.
+// The returned channel is notified of new heads, it is nil when no node is a websocket one.
+func (pro *EthereumSdkPro) SubscribeNewHeads() <-chan uint64 {
+	pro.mutex.Lock()
+	defer pro.mutex.Unlock()
+	if pro.heads != nil {
+		return pro.heads
+	}
+	heads := make(chan uint64, 1)
+	for url, info := range pro.infos {
+		if isWebsocketUrl(url) {
+			pro.heads = heads
+			go pro.subscribeNewHeads(url, info)
+		}
+	}
+	return pro.heads
+}
+
+func (pro *EthereumSdkPro) subscribeNewHeads(url string, info *EthereumInfo) {
+	retrySlot := pro.selectionSlot
+	if retrySlot == 0 {
+		retrySlot = 1
+	}
+	for {
+		err := pro.watchNewHeads(info)
+		logs.Error("new heads subscription of chain %d dropped, fall back to polling, url: %s, err: %v", pro.id, url, err)
+		time.Sleep(time.Second * time.Duration(retrySlot))
+	}
+}
+
+func (pro *EthereumSdkPro) watchNewHeads(info *EthereumInfo) error {
+	headers := make(chan *types.Header, 16)
+	sub, err := info.sdk.GetClient().SubscribeNewHead(context.Background(), headers)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	defer pro.setSubscribed(info, false)
+	pro.setSubscribed(info, true)
+
+	timer := time.NewTimer(newHeadTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case header := <-headers:
+			if header == nil || header.Number == nil {
+				continue
+			}
+			height := header.Number.Uint64()
+			pro.mutex.Lock()
+			if height > 0 {
+				info.latestHeight = height - 1
+			}
+			pro.mutex.Unlock()
+			select {
+			case pro.heads <- height:
+			default:
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(newHeadTimeout)
+		case err := <-sub.Err():
+			return err
+		case <-timer.C:
+			return fmt.Errorf("no new head in %v", newHeadTimeout)
+		}
+	}
+}
+
+func (pro *EthereumSdkPro) setSubscribed(info *EthereumInfo, subscribed bool) {
+	pro.mutex.Lock()
+	info.subscribed = subscribed
+	pro.mutex.Unlock()
+}
@@ -33,11 +33,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type EthereumInfo struct {
 	sdk          *EthereumSdk
 	latestHeight uint64
+	subscribed   bool
 }
 
 func NewEthereumInfo(url string) *EthereumInfo {
@@ -56,6 +58,7 @@ type EthereumSdkPro struct {
 	selectionSlot uint64
 	id            uint64
 	mutex         sync.Mutex
+	heads         chan uint64
 }
 
 func NewEthereumSdkPro(urls []string, slot uint64, id uint64) *EthereumSdkPro {
@@ -93,6 +96,12 @@ func (pro *EthereumSdkPro) nodeSelection() {
 
 func (pro *EthereumSdkPro) selection() {
 	for url, info := range pro.infos {
+		pro.mutex.Lock()
+		subscribed := info.subscribed
+		pro.mutex.Unlock()
+		if subscribed {
+			continue
+		}
 		height, err := info.sdk.GetCurrentBlockHeight()
 		if err != nil || height == math.MaxUint64 || height == 0 {
 			logs.Error("nodeselection get current block height err, chain %v, url: %s", pro.id, url)
@@ -336,6 +345,48 @@ func (pro *EthereumSdkPro) SuggestGasPrice() (*big.Int, error) {
 	return nil, fmt.Errorf("all node is not working")
 }
 
+func (pro *EthereumSdkPro) FeeHistory(blocks int, percentiles []float64) (*FeeHistory, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		history, err := info.sdk.FeeHistory(blocks, percentiles)
+		if _, ok := err.(rpc.Error); ok {
+			// the node answers but does not support eth_feeHistory
+			return nil, err
+		} else if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return history, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
+func (pro *EthereumSdkPro) CallContract(msg ethereum.CallMsg) ([]byte, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		data, err := info.sdk.CallContract(msg)
+		if _, ok := err.(rpc.Error); ok {
+			// the node answers but the call reverts
+			return nil, err
+		} else if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
 func (pro *EthereumSdkPro) EstimateGas(msg ethereum.CallMsg) (uint64, error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -35,6 +35,7 @@ import (
 	"poly-bridge/crosschaineffect"
 	"poly-bridge/crosschainlisten"
 	"poly-bridge/crosschainstats"
+	"poly-bridge/dbconn"
 
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/beego/beego/v2/server/web"
@@ -78,6 +79,9 @@ func startServer(ctx *cli.Context) {
 		logs.Error("startServer - read config failed!")
 		return
 	}
+	if err := dbconn.CheckSchema(config); err != nil {
+		panic(err)
+	}
 	logs.SetLogger(logs.AdapterFile, fmt.Sprintf(`{"filename":"%s"}`, config.ServerLogFile))
 
 	//{
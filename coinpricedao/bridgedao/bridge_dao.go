@@ -19,11 +19,11 @@ package bridgedao
 
 import (
 	"fmt"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 )
 
@@ -40,8 +40,7 @@ func NewBridgeDao(dbCfg *conf.DBConfig) *BridgeDao {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -71,6 +70,49 @@ func (dao *BridgeDao) GetTokens() ([]*models.TokenBasic, error) {
 	return tokens, nil
 }
 
+func (dao *BridgeDao) SavePriceHistory(histories []*models.TokenPriceHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	return dao.db.Create(histories).Error
+}
+
+func (dao *BridgeDao) DownsamplePriceHistory(period int64, before int64, to int64) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		histories := make([]*models.TokenPriceHistory, 0)
+		if err := tx.Where("period = ? and time < ?", period, before).Find(&histories).Error; err != nil {
+			return err
+		}
+		if len(histories) == 0 {
+			return nil
+		}
+		downsampled := models.DownsampleTokenPrices(histories, to)
+		// the prices downsampled before are merged with the later ones of the same time
+		existing := make([]*models.TokenPriceHistory, 0)
+		if err := tx.Where("period = ? and time >= ?", to, downsampled[0].Time).Find(&existing).Error; err != nil {
+			return err
+		}
+		existingMap := make(map[string]*models.TokenPriceHistory, len(existing))
+		for _, history := range existing {
+			existingMap[fmt.Sprintf("%s:%s:%d", history.Name, history.Market, history.Time)] = history
+		}
+		for _, history := range downsampled {
+			if earlier, ok := existingMap[fmt.Sprintf("%s:%s:%d", history.Name, history.Market, history.Time)]; ok {
+				earlier.Merge(history)
+				history = earlier
+			}
+			if err := tx.Save(history).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("period = ? and time < ?", period, before).Delete(&models.TokenPriceHistory{}).Error
+	})
+}
+
+func (dao *BridgeDao) DeletePriceHistory(period int64, before int64) error {
+	return dao.db.Where("period = ? and time < ?", period, before).Delete(&models.TokenPriceHistory{}).Error
+}
+
 func (dao *BridgeDao) Name() string {
 	return basedef.SERVER_POLY_BRIDGE
 }
@@ -29,6 +29,11 @@ import (
 type CoinPriceDao interface {
 	GetTokens() ([]*models.TokenBasic, error)
 	SavePrices(tokens []*models.TokenBasic) error
+	SavePriceHistory(histories []*models.TokenPriceHistory) error
+	// DownsamplePriceHistory merges the prices of the period before the time into the prices of the longer period
+	DownsamplePriceHistory(period int64, before int64, to int64) error
+	// DeletePriceHistory deletes the prices of the period before the time
+	DeletePriceHistory(period int64, before int64) error
 	Name() string
 }
 
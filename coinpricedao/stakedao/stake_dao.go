@@ -26,6 +26,7 @@ import (
 
 type StakeDao struct {
 	tokenBasics []*models.TokenBasic
+	histories   []*models.TokenPriceHistory
 }
 
 func NewStakeDao() *StakeDao {
@@ -52,6 +53,42 @@ func (dao *StakeDao) GetTokens() ([]*models.TokenBasic, error) {
 	return dao.tokenBasics, nil
 }
 
+func (dao *StakeDao) SavePriceHistory(histories []*models.TokenPriceHistory) error {
+	dao.histories = append(dao.histories, histories...)
+	return nil
+}
+
+func (dao *StakeDao) DownsamplePriceHistory(period int64, before int64, to int64) error {
+	histories := make([]*models.TokenPriceHistory, 0, len(dao.histories))
+	// the prices downsampled before are merged with the later ones of the same time
+	aged := make([]*models.TokenPriceHistory, 0)
+	for _, history := range dao.histories {
+		if history.Period == to {
+			aged = append(aged, history)
+		}
+	}
+	for _, history := range dao.histories {
+		if history.Period == period && history.Time < before {
+			aged = append(aged, history)
+		} else if history.Period != to {
+			histories = append(histories, history)
+		}
+	}
+	dao.histories = append(histories, models.DownsampleTokenPrices(aged, to)...)
+	return nil
+}
+
+func (dao *StakeDao) DeletePriceHistory(period int64, before int64) error {
+	histories := make([]*models.TokenPriceHistory, 0, len(dao.histories))
+	for _, history := range dao.histories {
+		if history.Period != period || history.Time >= before {
+			histories = append(histories, history)
+		}
+	}
+	dao.histories = histories
+	return nil
+}
+
 func (dao *StakeDao) Name() string {
 	return basedef.SERVER_STAKE
 }
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/coinpricedao/bridgedao"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestDownsamplePriceHistory(t *testing.T) {
+	dao := bridgedao.NewBridgeDao(&conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "coinprice_price_history"})
+	db, err := dbconn.Open(&conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "coinprice_price_history"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&models.TokenPriceHistory{}))
+
+	price := func(usd int64) int64 { return usd * basedef.PRICE_PRECISION }
+	assert.NoError(t, dao.SavePriceHistory([]*models.TokenPriceHistory{
+		models.NewTokenPriceHistory("Ethereum", "", 7300, price(100)),
+		models.NewTokenPriceHistory("Ethereum", "", 7500, price(120)),
+		models.NewTokenPriceHistory("Ethereum", basedef.MARKET_BINANCE, 7500, price(121)),
+		models.NewTokenPriceHistory("Ethereum", "", 8000, price(90)),
+		models.NewTokenPriceHistory("Ethereum", "", 10900, price(110)),
+	}))
+	assert.NoError(t, dao.DownsamplePriceHistory(0, 10000, 3600))
+	// a late price of the hour is merged into the hour downsampled before
+	assert.NoError(t, dao.SavePriceHistory([]*models.TokenPriceHistory{models.NewTokenPriceHistory("Ethereum", "", 9500, price(95))}))
+	assert.NoError(t, dao.DownsamplePriceHistory(0, 10000, 3600))
+
+	histories := make([]*models.TokenPriceHistory, 0)
+	assert.NoError(t, db.Where("name = ? and market = ?", "Ethereum", "").Order("time").Find(&histories).Error)
+	if assert.Len(t, histories, 2) {
+		assert.Equal(t, models.TokenPriceHistory{
+			Id: histories[0].Id, Name: "Ethereum", Period: 3600, Time: 7200,
+			Open: price(100), High: price(120), Low: price(90), Close: price(95), Samples: 4,
+		}, *histories[0])
+		assert.Equal(t, int64(0), histories[1].Period)
+	}
+
+	rsp := models.MakeTokenPriceHistoryRsp(&models.TokenPriceHistoryReq{Name: "Ethereum", Interval: 86400}, histories)
+	if assert.Len(t, rsp.Prices, 1) {
+		assert.Equal(t, models.TokenPriceCandleRsp{Time: 0, Open: "100", High: "120", Low: "90", Close: "110", Samples: 5}, *rsp.Prices[0])
+	}
+
+	assert.NoError(t, dao.DownsamplePriceHistory(3600, 100000, 86400))
+	assert.NoError(t, dao.DeletePriceHistory(86400, 100000))
+	var count int64
+	assert.NoError(t, db.Model(&models.TokenPriceHistory{}).Count(&count).Error)
+	// the latest price is kept
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package coinpricelisten
+
+import (
+	"math"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"sort"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+const defaultMinSources = 1
+
+// aggregationConfig returns the price aggregation config with the defaults, which takes the median of the prices
+// read without rejection
+func aggregationConfig() *conf.PriceAggregationConfig {
+	cfg := conf.PriceAggregationConfig{}
+	if conf.GlobalConfig != nil && conf.GlobalConfig.PriceAggregationConfig != nil {
+		cfg = *conf.GlobalConfig.PriceAggregationConfig
+	}
+	if cfg.MinSources <= 0 {
+		cfg.MinSources = defaultMinSources
+	}
+	return &cfg
+}
+
+// minSources returns the sources the price of the token basic needs
+func minSources(cfg *conf.PriceAggregationConfig, name string) int {
+	if sources := cfg.TokenMinSources[name]; sources > 0 {
+		return sources
+	}
+	return cfg.MinSources
+}
+
+// marketWeight returns the weight of the prices of the market in the coin price listen configs
+func marketWeight(market string) int64 {
+	if conf.GlobalConfig != nil {
+		for _, cfg := range conf.GlobalConfig.CoinPriceListenConfig {
+			if cfg.MarketName == market && cfg.Weight > 0 {
+				return cfg.Weight
+			}
+		}
+	}
+	return 1
+}
+
+type sourcePrice struct {
+	market *models.PriceMarket
+	weight int64
+}
+
+// weightedMedian returns the median of the prices weighted by their sources, the mean of the two middle prices when
+// the weights split evenly between them
+func weightedMedian(sources []*sourcePrice) int64 {
+	sort.Slice(sources, func(i, j int) bool { return sources[i].market.Price < sources[j].market.Price })
+	total := int64(0)
+	for _, source := range sources {
+		total += source.weight
+	}
+	cumulative := int64(0)
+	for i, source := range sources {
+		cumulative += source.weight
+		if cumulative*2 == total && i+1 < len(sources) {
+			return (source.market.Price + sources[i+1].market.Price) / 2
+		}
+		if cumulative*2 > total {
+			return source.market.Price
+		}
+	}
+	return 0
+}
+
+// aggregatePrice returns the price of the token basic from the prices of its markets, which are read in this update
+// or within MaxAge. The sources deviating from their median by more than MaxDeviation are rejected and the median of
+// the rest is the price. It is false if less than the min sources of the token are left.
+func aggregatePrice(cfg *conf.PriceAggregationConfig, tokenBasic *models.TokenBasic, now int64) (int64, bool) {
+	if len(tokenBasic.PriceMarkets) == 0 {
+		return 0, false
+	}
+	sources := make([]*sourcePrice, 0)
+	for _, priceMarket := range tokenBasic.PriceMarkets {
+		fresh := priceMarket.Ind == 1 || (cfg.MaxAge > 0 && priceMarket.Time >= now-cfg.MaxAge)
+		if fresh && priceMarket.Price > 0 {
+			sources = append(sources, &sourcePrice{market: priceMarket, weight: marketWeight(priceMarket.MarketName)})
+		}
+	}
+	required := minSources(cfg, tokenBasic.Name)
+	if len(sources) == 0 || len(sources) < required {
+		logs.Error("price of token %s has %d sources, %d are required", tokenBasic.Name, len(sources), required)
+		return 0, false
+	}
+	median := weightedMedian(sources)
+	if cfg.MaxDeviation > 0 {
+		accepted := make([]*sourcePrice, 0, len(sources))
+		for _, source := range sources {
+			deviation := math.Abs(float64(source.market.Price-median)) * 100 / float64(median)
+			if deviation > cfg.MaxDeviation {
+				logs.Error("price %d of token %s from market %s deviates %.2f%% from the median %d, it is rejected",
+					source.market.Price, tokenBasic.Name, source.market.MarketName, deviation, median)
+				source.market.Ind = 0
+				continue
+			}
+			accepted = append(accepted, source)
+		}
+		if len(accepted) == 0 || len(accepted) < required {
+			logs.Error("price of token %s has %d sources after the rejection, %d are required", tokenBasic.Name, len(accepted), required)
+			return 0, false
+		}
+		median = weightedMedian(accepted)
+	}
+	return median, true
+}
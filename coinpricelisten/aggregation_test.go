@@ -0,0 +1,81 @@
+package coinpricelisten
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+)
+
+type fakePriceMarket struct {
+	name   string
+	prices map[string]float64
+}
+
+func (market *fakePriceMarket) GetCoinPriceAndRank(coins []models.NameAndmarketId) (map[string]float64, map[string]int, error) {
+	if market.prices == nil {
+		return nil, nil, fmt.Errorf("market %s is down", market.name)
+	}
+	return market.prices, map[string]int{}, nil
+}
+
+func (market *fakePriceMarket) GetMarketName() string {
+	return market.name
+}
+
+func tokenBasic(name string, price int64, markets ...*models.PriceMarket) *models.TokenBasic {
+	for _, market := range markets {
+		market.TokenBasicName = name
+		market.Name = name
+	}
+	return &models.TokenBasic{Name: name, Price: price, PriceMarkets: markets}
+}
+
+func TestUpdateCoinPrice(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		CoinPriceListenConfig: []*conf.CoinPriceListenConfig{{MarketName: basedef.MARKET_BINANCE, Weight: 2}},
+		PriceAggregationConfig: &conf.PriceAggregationConfig{
+			MaxDeviation: 10, MinSources: 2, TokenMinSources: map[string]int{"Ontology": 1}, MaxAge: 300,
+		},
+	}
+	listen := &CoinPriceListen{priceMarket: map[string]PriceMarket{
+		basedef.MARKET_BINANCE:   &fakePriceMarket{name: basedef.MARKET_BINANCE, prices: map[string]float64{"Ethereum": 2000, "Ontology": 3.1}},
+		basedef.MARKET_HUOBI:     &fakePriceMarket{name: basedef.MARKET_HUOBI, prices: map[string]float64{"Ethereum": 2010, "Neo": 10}},
+		basedef.MARKET_COINCHECK: &fakePriceMarket{name: basedef.MARKET_COINCHECK, prices: map[string]float64{"Ethereum": 1000}},
+		basedef.MARKET_GATEIO:    &fakePriceMarket{name: basedef.MARKET_GATEIO},
+	}}
+	now := time.Now().Unix()
+	glitch := &models.PriceMarket{MarketName: basedef.MARKET_COINCHECK}
+	tokenBasics := []*models.TokenBasic{
+		tokenBasic("Ethereum", 0,
+			&models.PriceMarket{MarketName: basedef.MARKET_BINANCE}, &models.PriceMarket{MarketName: basedef.MARKET_HUOBI}, glitch),
+		tokenBasic("Neo", 5*basedef.PRICE_PRECISION, &models.PriceMarket{MarketName: basedef.MARKET_HUOBI}),
+		// the price of gateio is read a minute ago
+		tokenBasic("Ontology", 0,
+			&models.PriceMarket{MarketName: basedef.MARKET_BINANCE}, &models.PriceMarket{MarketName: basedef.MARKET_GATEIO, Price: 3 * basedef.PRICE_PRECISION, Time: now - 60}),
+		// the price of gateio is out of MaxAge
+		tokenBasic("Zilliqa", 0, &models.PriceMarket{MarketName: basedef.MARKET_GATEIO, Price: basedef.PRICE_PRECISION, Time: now - 600}),
+	}
+	assert.NoError(t, listen.updateCoinPrice(tokenBasics))
+
+	// the glitch of coincheck is rejected and binance weighs twice huobi
+	assert.Equal(t, uint64(1), tokenBasics[0].Ind)
+	assert.Equal(t, 2000*basedef.PRICE_PRECISION, tokenBasics[0].Price)
+	assert.Equal(t, uint64(0), glitch.Ind)
+	// without enough sources the last price is kept
+	assert.Equal(t, uint64(0), tokenBasics[1].Ind)
+	assert.Equal(t, 5*basedef.PRICE_PRECISION, tokenBasics[1].Price)
+	assert.Equal(t, uint64(1), tokenBasics[2].Ind)
+	assert.Equal(t, int64(310000000), tokenBasics[2].Price)
+	assert.Equal(t, uint64(0), tokenBasics[3].Ind)
+
+	sources := []*sourcePrice{
+		{market: &models.PriceMarket{Price: 100}, weight: 1},
+		{market: &models.PriceMarket{Price: 110}, weight: 1},
+	}
+	assert.Equal(t, int64(105), weightedMedian(sources))
+}
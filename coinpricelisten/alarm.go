@@ -0,0 +1,48 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package coinpricelisten
+
+import (
+	"fmt"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/common"
+	"poly-bridge/models"
+	"poly-bridge/utils/decimal"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// the alarm of a token whose price is not updated is sent once in the interval
+const priceAlarmInterval = time.Hour
+
+// alarmPrice alarms the price of the token basic kept with Ind 0, once in priceAlarmInterval among the servers
+func alarmPrice(tokenBasic *models.TokenBasic) {
+	title := fmt.Sprintf("*Price Alarm!!! %s*\n", common.EscapeTgMarkdown(tokenBasic.Name))
+	text := fmt.Sprintf("%s\nThe price is not updated, the sources are not enough or stale\n*Last Price*: %s\n*Last Update*: %s\n*Time*: %s\n%s",
+		title,
+		decimal.NewFromInt(tokenBasic.Price).Div(decimal.NewFromInt(basedef.PRICE_PRECISION)).String(),
+		time.Unix(tokenBasic.Time, 0).Format("2006-01-02 15:04:05"),
+		time.Now().Format("2006-01-02 15:04:05"),
+		"-----------------------------------------",
+	)
+	if err := common.SendTgAlarmOnce(cacheRedis.Redis, cacheRedis.PriceAlarmPrefix+tokenBasic.Name, priceAlarmInterval, text); err != nil {
+		logs.Error("send price alarm of token %s failed, err: %v", tokenBasic.Name, err)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"os/signal"
 	"poly-bridge/coinpricelisten"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"runtime"
 	"strings"
 	"syscall"
@@ -100,6 +101,9 @@ func startServer(ctx *cli.Context) {
 		logs.Error("startServer - read config failed!")
 		return
 	}
+	if err := dbconn.CheckSchema(config); err != nil {
+		panic(err)
+	}
 	{
 		conf, _ := json.Marshal(config)
 		logs.Info("%s\n", string(conf))
@@ -119,6 +119,7 @@ func NewCoinPriceListen(priceUpdateSlot int64, priceMarkets []PriceMarket, db co
 	if err != nil {
 		panic(err)
 	}
+	cpListen.savePriceHistory(tokenBasics)
 	return cpListen
 }
 
@@ -184,6 +185,7 @@ func (cpl *CoinPriceListen) listenPrice() (exit bool) {
 					logs.Error("save price err: %v", err)
 					continue
 				}
+				cpl.savePriceHistory(tokenBasics)
 				break
 			}
 		case <-cpl.exit:
@@ -246,28 +248,27 @@ func (cpl *CoinPriceListen) updateCoinPrice(tokenBasics []*models.TokenBasic) er
 			}
 		}
 	}
+	cfg := aggregationConfig()
+	now := time.Now().Unix()
 	for _, tokenBasic := range tokenBasics {
-		price := int64(0)
 		rank := 0
-		counter := int64(0)
 		for _, tokenPrice := range tokenBasic.PriceMarkets {
-			if tokenPrice.Ind == 1 {
-				price += tokenPrice.Price
-				counter++
-			}
 			if tokenPrice.Rank != 0 {
 				rank = tokenPrice.Rank
 			}
 		}
-		if counter > 0 {
-			price = price / counter
+		// the last price is kept with Ind 0 when the price of the sources is not reliable
+		if price, ok := aggregatePrice(cfg, tokenBasic, now); ok {
 			tokenBasic.Price = price
 			tokenBasic.Rank = rank
 			tokenBasic.Ind = 1
-			tokenBasic.Time = time.Now().Unix()
+			tokenBasic.Time = now
 		}
 		if tokenBasic.Ind == 0 {
 			logs.Error("Price of token %s is not update", tokenBasic.Name)
+			if len(tokenBasic.PriceMarkets) > 0 {
+				alarmPrice(tokenBasic)
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package coinpricelisten
+
+import (
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// the periods the prices are downsampled to
+const (
+	PricePeriodHour = 3600
+	PricePeriodDay  = 86400
+)
+
+const (
+	defaultPriceRawRetention  = 604800
+	defaultPriceHourRetention = 7776000
+)
+
+// historyConfig returns the price history config with the defaults
+func historyConfig() *conf.PriceHistoryConfig {
+	cfg := conf.PriceHistoryConfig{}
+	if conf.GlobalConfig != nil && conf.GlobalConfig.PriceHistoryConfig != nil {
+		cfg = *conf.GlobalConfig.PriceHistoryConfig
+	}
+	if cfg.RawRetention <= 0 {
+		cfg.RawRetention = defaultPriceRawRetention
+	}
+	if cfg.HourRetention <= 0 {
+		cfg.HourRetention = defaultPriceHourRetention
+	}
+	return &cfg
+}
+
+// savePriceHistory appends the prices of the token basics and their markets updated, and downsamples the prices out
+// of the retentions. The prices saved are not affected by its errors.
+func (cpl *CoinPriceListen) savePriceHistory(tokenBasics []*models.TokenBasic) {
+	histories := make([]*models.TokenPriceHistory, 0)
+	for _, tokenBasic := range tokenBasics {
+		// the prices of the token basics without markets are not updated
+		if tokenBasic.Ind != 1 || len(tokenBasic.PriceMarkets) == 0 {
+			continue
+		}
+		histories = append(histories, models.NewTokenPriceHistory(tokenBasic.Name, "", tokenBasic.Time, tokenBasic.Price))
+		for _, priceMarket := range tokenBasic.PriceMarkets {
+			if priceMarket.Ind == 1 && priceMarket.Price > 0 {
+				histories = append(histories, models.NewTokenPriceHistory(tokenBasic.Name, priceMarket.MarketName, priceMarket.Time, priceMarket.Price))
+			}
+		}
+	}
+	if err := cpl.db.SavePriceHistory(histories); err != nil {
+		logs.Error("save price history err: %v", err)
+		return
+	}
+	cfg := historyConfig()
+	now := time.Now().Unix()
+	if err := cpl.db.DownsamplePriceHistory(0, now-cfg.RawRetention, PricePeriodHour); err != nil {
+		logs.Error("downsample price history to hours err: %v", err)
+		return
+	}
+	if err := cpl.db.DownsamplePriceHistory(PricePeriodHour, now-cfg.HourRetention, PricePeriodDay); err != nil {
+		logs.Error("downsample price history to days err: %v", err)
+		return
+	}
+	if cfg.DayRetention > 0 {
+		if err := cpl.db.DeletePriceHistory(PricePeriodDay, now-cfg.DayRetention); err != nil {
+			logs.Error("delete price history err: %v", err)
+		}
+	}
+}
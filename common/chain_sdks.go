@@ -0,0 +1,186 @@
+package common
+
+import (
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/chainsdk"
+	"poly-bridge/conf"
+	"strings"
+)
+
+// BalanceSdk is what GetBalance, GetTotalSupply and GetProxyBalance need from the sdk of a chain
+type BalanceSdk interface {
+	Balance(hash string, proxy string) (*big.Int, error)
+	TotalSupply(hash string, proxyContracts []string) (*big.Int, error)
+}
+
+// ChainSdkFactory creates the sdk of a chain, the BalanceSdk is nil when the family has no balance query
+type ChainSdkFactory func(cfg *conf.ChainListenConfig) (sdk interface{}, balanceSdk BalanceSdk)
+
+var chainSdkFactories = map[string]ChainSdkFactory{
+	basedef.FAMILY_EVM:      newEthereumSdk,
+	basedef.FAMILY_ONTEVM:   newEthereumSdk,
+	basedef.FAMILY_NEO:      newNeoSdk,
+	basedef.FAMILY_NEO3:     newNeo3Sdk,
+	basedef.FAMILY_ONTOLOGY: newOntologySdk,
+	basedef.FAMILY_SWITCHEO: newSwitcheoSdk,
+	basedef.FAMILY_ZILLIQA:  newZilliqaSdk,
+	basedef.FAMILY_STARCOIN: newStarcoinSdk,
+	basedef.FAMILY_RIPPLE:   newRippleSdk,
+	basedef.FAMILY_APTOS:    newAptosSdk,
+	basedef.FAMILY_BFC:      newBfcSdk,
+}
+
+// RegisterChainSdk sets the sdk factory of a chain family, it must be called before SetupChainsSDK
+func RegisterChainSdk(family string, factory ChainSdkFactory) {
+	chainSdkFactories[family] = factory
+}
+
+type ethereumBalanceSdk struct {
+	*chainsdk.EthereumSdkPro
+}
+
+func newEthereumSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewEthereumSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &ethereumBalanceSdk{sdk}
+}
+
+func (sdk *ethereumBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.Erc20Balance(hash, proxy)
+}
+
+func (sdk *ethereumBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return sdk.Erc20TotalSupply(hash)
+}
+
+type neoBalanceSdk struct {
+	*chainsdk.NeoSdkPro
+}
+
+func newNeoSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewNeoSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &neoBalanceSdk{sdk}
+}
+
+func (sdk *neoBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.Nep5Balance(hash, proxy)
+}
+
+func (sdk *neoBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return sdk.Nep5TotalSupply(hash)
+}
+
+type neo3BalanceSdk struct {
+	*chainsdk.Neo3SdkPro
+}
+
+func newNeo3Sdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewNeo3SdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &neo3BalanceSdk{sdk}
+}
+
+func (sdk *neo3BalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.Nep17Balance(hash, proxy)
+}
+
+func (sdk *neo3BalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return sdk.Nep17TotalSupply(hash)
+}
+
+type ontologyBalanceSdk struct {
+	*chainsdk.OntologySdkPro
+}
+
+func newOntologySdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewOntologySdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &ontologyBalanceSdk{sdk}
+}
+
+func (sdk *ontologyBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.Oep4Balance(hash, proxy)
+}
+
+func (sdk *ontologyBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	for _, v := range proxyContracts {
+		if len(strings.TrimSpace(v)) != 0 {
+			return sdk.Oep4TotalSupply(hash, v)
+		}
+	}
+	return new(big.Int).SetUint64(0), nil
+}
+
+type zilliqaBalanceSdk struct {
+	*chainsdk.ZilliqaSdkPro
+}
+
+func newZilliqaSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewZilliqaSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &zilliqaBalanceSdk{sdk}
+}
+
+func (sdk *zilliqaBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.Erc20Balance(hash, proxy)
+}
+
+func (sdk *zilliqaBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return new(big.Int).SetUint64(0), nil
+}
+
+type starcoinBalanceSdk struct {
+	*chainsdk.StarcoinSdkPro
+}
+
+func newStarcoinSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewStarcoinSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &starcoinBalanceSdk{sdk}
+}
+
+func (sdk *starcoinBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.GetBalance(hash, proxy)
+}
+
+func (sdk *starcoinBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return new(big.Int).SetUint64(0), nil
+}
+
+type rippleBalanceSdk struct {
+	*chainsdk.RippleSdkPro
+}
+
+func newRippleSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewRippleSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &rippleBalanceSdk{sdk}
+}
+
+func (sdk *rippleBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.XRPBalance(hash, proxy)
+}
+
+func (sdk *rippleBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return new(big.Int).SetUint64(0), nil
+}
+
+type aptosBalanceSdk struct {
+	*chainsdk.AptosSdkPro
+}
+
+func newAptosSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	sdk := chainsdk.NewAptosSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+	return sdk, &aptosBalanceSdk{sdk}
+}
+
+func (sdk *aptosBalanceSdk) Balance(hash string, proxy string) (*big.Int, error) {
+	return sdk.GetBalance(hash, proxy)
+}
+
+func (sdk *aptosBalanceSdk) TotalSupply(hash string, proxyContracts []string) (*big.Int, error) {
+	return new(big.Int).SetUint64(0), nil
+}
+
+func newSwitcheoSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	return chainsdk.NewSwitcheoSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId), nil
+}
+
+func newBfcSdk(cfg *conf.ChainListenConfig) (interface{}, BalanceSdk) {
+	return chainsdk.NewBfcSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId), nil
+}
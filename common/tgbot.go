@@ -4,6 +4,7 @@ import (
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"poly-bridge/conf"
+	"time"
 )
 
 var bot *tgbotapi.BotAPI
@@ -23,3 +24,30 @@ func TgBotInit() {
 func SendTgBotMessage(msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
 	return bot.Send(msg)
 }
+
+// AlarmLocker takes the key of an alarm among the servers, as cacheRedis.Redis does
+type AlarmLocker interface {
+	LockAlarm(key string, interval time.Duration) bool
+}
+
+// SendTgAlarmOnce sends the Markdown text to the chat of the large transactions if the locker takes the key for the
+// interval, so that the alarm is sent once in it. The values put in the text are escaped with EscapeTgMarkdown.
+func SendTgAlarmOnce(locker AlarmLocker, key string, interval time.Duration, text string) error {
+	if !locker.LockAlarm(key, interval) {
+		return nil
+	}
+	botConfig := conf.GlobalConfig.BotConfig
+	if botConfig == nil || bot == nil {
+		return fmt.Errorf("bot is not configured")
+	}
+	msg := tgbotapi.NewMessage(botConfig.LargeTxChatId, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	_, err := SendTgBotMessage(msg)
+	return err
+}
+
+// EscapeTgMarkdown escapes the text for a Markdown message, telegram rejects a message with an unpaired _ or *
+func EscapeTgMarkdown(text string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, text)
+}
@@ -46,10 +46,12 @@ func GetFlagName(flag cli.Flag) string {
 }
 
 type DBConfig struct {
-	URL      string
+	Dialect  string // mysql, postgres or sqlite, mysql by default
+	URL      string // the database file of sqlite, an in-process database named by Scheme when it is empty
 	User     string
 	Password string
 	Scheme   string
+	SSLMode  string // sslmode of postgres, disable by default
 	Debug    bool
 }
 
@@ -87,6 +89,7 @@ type OtherItemProxy struct {
 type ChainNodes struct {
 	ChainName   string
 	ChainId     uint64
+	Family      string // chain family, e.g. EVM, overrides the built-in one so a new chain needs no code change
 	Nodes       []*Restful
 	ExtendNodes []*Restful
 }
@@ -113,6 +116,9 @@ type ChainListenConfig struct {
 	SwapContract                  string
 	L1Url                         string
 	L1Contract                    string
+	ReorgDepth                    uint64 // how many recent block hashes to keep for reorg detection, 0 disables it
+	SubscribeNewHeads             bool   // listen new blocks by eth_subscribe("newHeads") on the ws nodes, polling is the fallback
+	DeadLetterAttempts            uint64 // failed rounds of a block range before it is dead-lettered and skipped, 0 disables it
 }
 
 type HealthMonitorConfig struct {
@@ -176,6 +182,7 @@ func (cfg *ChainListenConfig) GetExtendNodesKey() []string {
 type CoinPriceListenConfig struct {
 	MarketName string
 	Nodes      []*Restful
+	Weight     int64 // weight of the prices of the market in the median of the sources, 1 by default
 }
 
 func (cfg *CoinPriceListenConfig) GetNodesUrl() []string {
@@ -201,8 +208,28 @@ type FeeListenConfig struct {
 	ProxyFee      int64
 	MinFee        int64
 	GasLimit      int64
-	EthL1GasLimit int64
+	EthL1GasLimit int64 // gas of the L1 data of an unlock on a layer 2 chain, the L1 fee is scaled from the fee of ethereum by it
 	NftRatio      int64
+	// Eip1559 prices the gas by the base fee and a percentile of the priority fees of eth_feeHistory, falls back to
+	// eth_gasPrice when the chain does not support it
+	Eip1559            bool
+	FeeHistoryBlocks   int     // blocks of eth_feeHistory, 20 by default
+	PriorityPercentile float64 // percentile of the priority fees in each block, 50 by default
+	MinGasPrice        int64   // wei, a lower gas price is raised to it
+	MaxGasPrice        int64   // wei, a higher gas price is capped to it, 0 for no cap
+	SkipGasPrice       int64   // wei, the fee is not updated when the gas price is lower than it
+	// GasLimits override the gas limits of the proxy types "lock", "nft" and "swap", which are calibrated from the
+	// unlocks by GasCalibrationConfig or are GasLimit otherwise
+	GasLimits map[string]int64
+	// L1FeeOracle reads the L1 data fee of an unlock on a layer 2 chain, "optimism" from the gas price oracle of the op
+	// stack chains and of the ovm forks boba and metis. Without a reading of it within L1FeeMaxAge the fee of ethereum
+	// is scaled by EthL1GasLimit, which is required with it, and the fees are not paid when neither can be used.
+	// Arbitrum and zksync charge the L1 data in the gas of the unlocks, which is priced by their gas prices and
+	// calibrated gas limits, so they need neither.
+	L1FeeOracle        string
+	L1FeeOracleAddress string // the oracle, the predeploy of L1FeeOracle by default
+	L1DataSize         int64  // bytes of the calldata of an unlock posted to L1, 1024 by default
+	L1FeeMaxAge        int64  // seconds, the reading of the oracle is used within it since it is read, 600 by default
 }
 
 func (cfg *FeeListenConfig) GetNodesUrl() []string {
@@ -235,11 +262,13 @@ type StatsConfig struct {
 }
 
 type EventEffectConfig struct {
-	HowOld            int64
-	HowOld2           int64
-	ChainListening    int64
-	EffectSlot        int64
-	TimeStatisticSlot int64
+	HowOld             int64
+	HowOld2            int64
+	ChainListening     int64
+	EffectSlot         int64
+	TimeStatisticSlot  int64
+	InvariantCheckFrom int64 // seconds of unlocks checked against their locks, 0 disables the check
+	InvariantHowOld    int64 // seconds an unlock is left for its poly and source transactions to be indexed
 }
 
 type BotConfig struct {
@@ -267,6 +296,7 @@ type BotConfig struct {
 	AllNodesNoGrowthTimeMarkChainUnhealthy    int64
 	AllNodesUnavailableAlarmTime              int64
 	AllNodesNoGrowthAlarmTime                 int64
+	ExploitChatId                             int64
 }
 
 type HttpConfig struct {
@@ -292,38 +322,128 @@ type ActivityConfig struct {
 	AirDropInfoInterval   int64 //AirDropInfo stats interval in seconds
 }
 
+type OutflowLimitConfig struct {
+	Window             int64            // seconds of the sliding window, 3600 by default
+	TokenLimits        map[string]int64 // USD outflow limit of a token and of each of its routes in a window, by TokenBasic name
+	DefaultTokenLimit  int64            // USD outflow limit of the tokens not in TokenLimits, 0 for no limit
+	ChainLimits        map[uint64]int64 // USD outflow limit to a destination chain in a window, by chain id
+	BaselineDays       int64            // days the baseline of a window is averaged on, 0 disables the deviation alarm
+	DeviationRate      float64          // alarm when a window is this many times its baseline
+	DeviationMinAmount int64            // USD a window reaches before it is compared with its baseline
+}
+
+type CircuitBreakerConfig struct {
+	ApiToken  string // api token of the circuit breaker admin endpoint
+	AutoPause bool   // pause the chain, route or token of an anomaly automatically
+}
+
+type WebhookConfig struct {
+	ApiToken    string // api token of the webhook admin endpoints
+	MaxAttempts int    // attempts of a delivery before it is given up, 10 by default
+	Timeout     int64  // seconds of a delivery request, 10 by default
+}
+
+type FeeQuoteConfig struct {
+	Secret    string // secret signing the quotes of the fees, the quotes are not issued without it
+	Expiry    int64  // seconds a quote is valid for, 300 by default
+	Retention int64  // seconds the expired quotes are kept for the late checks of the fees, 86400 by default
+}
+
+type GasCalibrationConfig struct {
+	Window     int64 // seconds of the unlocks observed, 604800 by default
+	MinSamples int   // unlocks of a proxy type needed to calibrate its gas limit, 20 by default
+	MaxSamples int   // latest unlocks of a proxy type observed, 1000 by default
+	Percentile int   // percentile of the gas used taken as the gas limit, 90 by default
+	Margin     int64 // percent of the percentile taken as the gas limit, 110 by default
+}
+
+type PriceAggregationConfig struct {
+	MaxDeviation    float64        // percent a source may deviate from the median of the sources, 0 for no rejection
+	MinSources      int            // sources a price needs after the rejection, 1 by default
+	TokenMinSources map[string]int // MinSources of the token basics by name
+	MaxAge          int64          // seconds the last price of a market is used for when it fails, 0 for the prices read only
+}
+
+type PriceHistoryConfig struct {
+	RawRetention  int64 // seconds the prices of the updates are kept before they are downsampled to hours, 604800 by default
+	HourRetention int64 // seconds the hourly prices are kept before they are downsampled to days, 7776000 by default
+	DayRetention  int64 // seconds the daily prices are kept, 0 to keep them
+}
+
+type FeeSmoothingConfig struct {
+	Method      string // "ema" or "median" of the latest accepted samples, the fees are published as read without it
+	Window      int    // samples smoothed, 5 by default
+	MaxChange   int64  // percent the max fee read may change from the published one, 0 for no bound
+	SpikeAction string // "reject" the samples beyond MaxChange by default, or "alert" and publish them
+	MaxRejects  int    // consecutive rejected samples after which a sample is accepted as the new level, 3 by default
+	Retention   int64  // seconds the samples are kept, 2592000 by default
+}
+
+type RateLimitConfig struct {
+	Groups     []*RateLimitGroup // route groups matched in order, a group without routes matches all the routes
+	ApiKeys    []*ApiKeyConfig   // clients identified by the X-Api-Key header or the apikey query param
+	TrustProxy bool              // identify the clients without api keys by X-Forwarded-For instead of the remote address
+	ProxyHops  int               // trusted proxies appending to X-Forwarded-For in front of the server with TrustProxy, 1 by default
+}
+
+type RateLimitGroup struct {
+	Name   string
+	Routes []string // path prefixes of the routes, like /v1/bridge/transactions/
+	RateLimitQuota
+}
+
+type RateLimitQuota struct {
+	Rate  float64 // requests refilled per second, 0 for no limit
+	Burst int64   // requests of a full bucket, Rate by default
+}
+
+type ApiKeyConfig struct {
+	Client string
+	Key    string
+	Quotas map[string]*RateLimitQuota // quotas of the client by group name, the quota of the group by default
+}
+
 type OperationConfig struct {
 	ApiToken string //Operation api token
 }
 
 type Config struct {
-	Server                string
-	Env                   string
-	RunMode               string
-	Backup                bool
-	LargeTxAmount         int64
-	ServerLogFile         string
-	HttpLogFile           string
-	MonitorLogFile        string
-	HttpConfig            *HttpConfig
-	MetricConfig          *HttpConfig
-	ChainNodes            []*ChainNodes
-	ChainListenConfig     []*ChainListenConfig
-	CoinPriceUpdateSlot   int64
-	CoinPriceListenConfig []*CoinPriceListenConfig
-	FeeUpdateSlot         int64
-	RiskyCoinHandleConfig *RiskyCoinHandleConfig
-	FeeListenConfig       []*FeeListenConfig
-	EventEffectConfig     *EventEffectConfig
-	StatsConfig           *StatsConfig
-	DBConfig              *DBConfig
-	BotConfig             *BotConfig
-	RedisConfig           *RedisConfig
-	IPPortConfig          *IPPortConfig
-	NftConfig             *NftConfig
-	RelayUrl              string
-	ActivityConfig        *ActivityConfig
-	OperationConfig       *OperationConfig
+	Server                 string
+	Env                    string
+	RunMode                string
+	Backup                 bool
+	LargeTxAmount          int64
+	OutflowLimitConfig     *OutflowLimitConfig
+	CircuitBreakerConfig   *CircuitBreakerConfig
+	ServerLogFile          string
+	HttpLogFile            string
+	MonitorLogFile         string
+	HttpConfig             *HttpConfig
+	MetricConfig           *HttpConfig
+	ChainNodes             []*ChainNodes
+	ChainListenConfig      []*ChainListenConfig
+	CoinPriceUpdateSlot    int64
+	CoinPriceListenConfig  []*CoinPriceListenConfig
+	FeeUpdateSlot          int64
+	RiskyCoinHandleConfig  *RiskyCoinHandleConfig
+	FeeListenConfig        []*FeeListenConfig
+	EventEffectConfig      *EventEffectConfig
+	StatsConfig            *StatsConfig
+	DBConfig               *DBConfig
+	BotConfig              *BotConfig
+	RedisConfig            *RedisConfig
+	IPPortConfig           *IPPortConfig
+	NftConfig              *NftConfig
+	RelayUrl               string
+	ActivityConfig         *ActivityConfig
+	OperationConfig        *OperationConfig
+	WebhookConfig          *WebhookConfig
+	RateLimitConfig        *RateLimitConfig
+	FeeQuoteConfig         *FeeQuoteConfig
+	GasCalibrationConfig   *GasCalibrationConfig
+	FeeSmoothingConfig     *FeeSmoothingConfig
+	PriceAggregationConfig *PriceAggregationConfig
+	PriceHistoryConfig     *PriceHistoryConfig
 }
 
 func (cfg *Config) GetChainListenConfig(chainId uint64) *ChainListenConfig {
@@ -369,6 +489,9 @@ func NewConfig(filePath string) *Config {
 	chainNodeMap := make(map[uint64]*ChainNodes, 0)
 	for _, node := range config.ChainNodes {
 		chainNodeMap[node.ChainId] = node
+		if node.Family != "" {
+			basedef.RegisterChainFamily(node.ChainId, node.Family)
+		}
 	}
 
 	for _, listenConfig := range config.ChainListenConfig {
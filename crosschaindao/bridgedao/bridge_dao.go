@@ -21,16 +21,18 @@ import (
 	"errors"
 	"fmt"
 	"github.com/beego/beego/v2/core/logs"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	serverconf "poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"poly-bridge/utils/decimal"
 	"poly-bridge/utils/fee"
+	"poly-bridge/utils/webhook"
 	"strings"
 	"time"
 )
@@ -50,8 +52,7 @@ func NewBridgeDao(dbCfg *conf.DBConfig, backup bool) *BridgeDao {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -62,16 +63,38 @@ func NewBridgeDao(dbCfg *conf.DBConfig, backup bool) *BridgeDao {
 func (dao *BridgeDao) UpdateEvents(wrapperTransactions []*models.WrapperTransaction, srcTransactions []*models.SrcTransaction, polyTransactions []*models.PolyTransaction, dstTransactions []*models.DstTransaction, wrapperDetails []*models.WrapperDetail, polyDetails []*models.PolyDetail) error {
 	if !dao.backup {
 		if wrapperTransactions != nil && len(wrapperTransactions) > 0 {
-			res := dao.db.Save(wrapperTransactions)
+			res := dao.db.Clauses(dbconn.UpsertOn("hash")).Save(wrapperTransactions)
 			if res.Error != nil {
 				return res.Error
 			}
 		}
 		if srcTransactions != nil && len(srcTransactions) > 0 {
-			res := dao.db.Save(srcTransactions)
+			res := dao.db.Clauses(dbconn.UpsertOn("hash")).Omit(clause.Associations).Save(srcTransactions)
 			if res.Error != nil {
 				return res.Error
 			}
+			srcTransfers := make([]*models.SrcTransfer, 0)
+			srcSwaps := make([]*models.SrcSwap, 0)
+			for _, v := range srcTransactions {
+				if v.SrcTransfer != nil {
+					v.SrcTransfer.TxHash = v.Hash
+					srcTransfers = append(srcTransfers, v.SrcTransfer)
+				}
+				if v.SrcSwap != nil {
+					v.SrcSwap.TxHash = v.Hash
+					srcSwaps = append(srcSwaps, v.SrcSwap)
+				}
+			}
+			if len(srcTransfers) > 0 {
+				if err := dao.db.Clauses(dbconn.InsertOn("tx_hash")).Create(srcTransfers).Error; err != nil {
+					return err
+				}
+			}
+			if len(srcSwaps) > 0 {
+				if err := dao.db.Clauses(dbconn.InsertOn("tx_hash")).Create(srcSwaps).Error; err != nil {
+					return err
+				}
+			}
 			for _, v := range srcTransactions {
 				if v.SrcTransfer != nil && v.SrcTransfer.TxHash != "" {
 					res := dao.db.
@@ -85,16 +108,38 @@ func (dao *BridgeDao) UpdateEvents(wrapperTransactions []*models.WrapperTransact
 			}
 		}
 		if polyTransactions != nil && len(polyTransactions) > 0 {
-			res := dao.db.Save(polyTransactions)
+			res := dao.db.Clauses(dbconn.UpsertOn("hash")).Save(polyTransactions)
 			if res.Error != nil {
 				return res.Error
 			}
 		}
 		if dstTransactions != nil && len(dstTransactions) > 0 {
-			res := dao.db.Save(dstTransactions)
+			res := dao.db.Clauses(dbconn.UpsertOn("hash")).Omit(clause.Associations).Save(dstTransactions)
 			if res.Error != nil {
 				return res.Error
 			}
+			dstTransfers := make([]*models.DstTransfer, 0)
+			dstSwaps := make([]*models.DstSwap, 0)
+			for _, v := range dstTransactions {
+				if v.DstTransfer != nil {
+					v.DstTransfer.TxHash = v.Hash
+					dstTransfers = append(dstTransfers, v.DstTransfer)
+				}
+				if v.DstSwap != nil {
+					v.DstSwap.TxHash = v.Hash
+					dstSwaps = append(dstSwaps, v.DstSwap)
+				}
+			}
+			if len(dstTransfers) > 0 {
+				if err := dao.db.Clauses(dbconn.InsertOn("tx_hash")).Create(dstTransfers).Error; err != nil {
+					return err
+				}
+			}
+			if len(dstSwaps) > 0 {
+				if err := dao.db.Clauses(dbconn.InsertOn("tx_hash")).Create(dstSwaps).Error; err != nil {
+					return err
+				}
+			}
 			for _, v := range dstTransactions {
 				if v.DstTransfer != nil && v.DstTransfer.TxHash != "" {
 					res := dao.db.Table("dst_transfers").
@@ -107,17 +152,20 @@ func (dao *BridgeDao) UpdateEvents(wrapperTransactions []*models.WrapperTransact
 			}
 		}
 		if wrapperDetails != nil && len(wrapperDetails) > 0 {
-			res := dao.db.Save(wrapperDetails)
+			res := dao.db.Clauses(dbconn.UpsertOn("hash")).Save(wrapperDetails)
 			if res.Error != nil {
 				return res.Error
 			}
 		}
 		if polyDetails != nil && len(polyDetails) > 0 {
-			res := dao.db.Save(polyDetails)
+			res := dao.db.Clauses(dbconn.UpsertOn("hash")).Save(polyDetails)
 			if res.Error != nil {
 				return res.Error
 			}
 		}
+		if err := webhook.Emit(dao.db, wrapperTransactions, srcTransactions, polyTransactions, dstTransactions); err != nil {
+			logs.Error("emit webhook events err: %v", err)
+		}
 		return nil
 	} else {
 		if wrapperTransactions != nil && len(wrapperTransactions) > 0 {
@@ -164,17 +212,198 @@ func (dao *BridgeDao) UpdateEvents(wrapperTransactions []*models.WrapperTransact
 }
 
 func (dao *BridgeDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
-	dao.db.Where("`tx_hash` in ?", srcHashes).Delete(&models.SrcTransfer{})
-	dao.db.Where("`hash` in ?", srcHashes).Delete(&models.SrcTransaction{})
-	dao.db.Where("`hash` in ?", srcHashes).Delete(&models.WrapperTransaction{})
+	dao.db.Where("tx_hash in ?", srcHashes).Delete(&models.SrcTransfer{})
+	dao.db.Where("hash in ?", srcHashes).Delete(&models.SrcTransaction{})
+	dao.db.Where("hash in ?", srcHashes).Delete(&models.WrapperTransaction{})
+
+	dao.db.Where("hash in ?", polyHashes).Delete(&models.PolyTransaction{})
+
+	dao.db.Where("tx_hash in ?", dstHashes).Delete(&models.DstTransfer{})
+	dao.db.Where("hash in ?", dstHashes).Delete(&models.DstTransaction{})
+	return nil
+}
+
+func (dao *BridgeDao) GetEventHashesByHeight(chainId uint64, start, end uint64) (srcHashes []string, polyHashes []string, dstHashes []string, err error) {
+	srcHashes = make([]string, 0)
+	polyHashes = make([]string, 0)
+	dstHashes = make([]string, 0)
+	if chainId == basedef.POLY_CROSSCHAIN_ID {
+		err = dao.db.Model(&models.PolyTransaction{}).Where("chain_id = ? and height >= ? and height <= ?", chainId, start, end).Pluck("hash", &polyHashes).Error
+		return
+	}
+	err = dao.db.Model(&models.SrcTransaction{}).Where("chain_id = ? and height >= ? and height <= ?", chainId, start, end).Pluck("hash", &srcHashes).Error
+	if err != nil {
+		return
+	}
+	wrapperHashes := make([]string, 0)
+	err = dao.db.Model(&models.WrapperTransaction{}).Where("src_chain_id = ? and block_height >= ? and block_height <= ?", chainId, start, end).Pluck("hash", &wrapperHashes).Error
+	if err != nil {
+		return
+	}
+	srcHashes = append(srcHashes, wrapperHashes...)
+	err = dao.db.Model(&models.DstTransaction{}).Where("chain_id = ? and height >= ? and height <= ?", chainId, start, end).Pluck("hash", &dstHashes).Error
+	return
+}
 
-	dao.db.Where("`hash` in ?", polyHashes).Delete(&models.PolyTransaction{})
+// GetEvents loads the events of the chain within the block range, together with the ones of the hashes wherever they are
+func (dao *BridgeDao) GetEvents(chainId uint64, start, end uint64, hashes []string) (wrapperTransactions []*models.WrapperTransaction, srcTransactions []*models.SrcTransaction, polyTransactions []*models.PolyTransaction, dstTransactions []*models.DstTransaction, err error) {
+	wrapperTransactions = make([]*models.WrapperTransaction, 0)
+	srcTransactions = make([]*models.SrcTransaction, 0)
+	polyTransactions = make([]*models.PolyTransaction, 0)
+	dstTransactions = make([]*models.DstTransaction, 0)
+	if chainId == basedef.POLY_CROSSCHAIN_ID {
+		err = dao.db.Where("(chain_id = ? and height >= ? and height <= ?) or hash in ?", chainId, start, end, hashes).
+			Find(&polyTransactions).Error
+		return
+	}
+	err = dao.db.Where("(src_chain_id = ? and block_height >= ? and block_height <= ?) or hash in ?", chainId, start, end, hashes).
+		Find(&wrapperTransactions).Error
+	if err != nil {
+		return
+	}
+	err = dao.db.Preload("SrcTransfer").
+		Where("(chain_id = ? and height >= ? and height <= ?) or hash in ?", chainId, start, end, hashes).
+		Find(&srcTransactions).Error
+	if err != nil {
+		return
+	}
+	err = dao.db.Preload("DstTransfer").
+		Where("(chain_id = ? and height >= ? and height <= ?) or hash in ?", chainId, start, end, hashes).
+		Find(&dstTransactions).Error
+	if err != nil || len(hashes) == 0 {
+		return
+	}
+	err = dao.db.Where("hash in ?", hashes).Find(&polyTransactions).Error
+	return
+}
 
-	dao.db.Where("`tx_hash` in ?", dstHashes).Delete(&models.DstTransfer{})
-	dao.db.Where("`hash` in ?", dstHashes).Delete(&models.DstTransaction{})
+// UpdateEventFields updates only the fields of the indexed event of the hash to the ones of the event, the
+// fields of the transfer are prefixed with src_transfer. or dst_transfer., the transfer missing is inserted
+func (dao *BridgeDao) UpdateEventFields(event interface{}, hash string, fields []string) error {
+	if dao.backup || event == nil || len(fields) == 0 {
+		return nil
+	}
+	columns := make([]string, 0, len(fields))
+	transferColumns := make([]string, 0)
+	missingTransfer := false
+	for _, field := range fields {
+		switch {
+		case field == "src_transfer" || field == "dst_transfer":
+			missingTransfer = true
+		case strings.HasPrefix(field, "src_transfer.") || strings.HasPrefix(field, "dst_transfer."):
+			transferColumns = append(transferColumns, field[strings.Index(field, ".")+1:])
+		default:
+			columns = append(columns, field)
+		}
+	}
+	if len(columns) > 0 {
+		res := dao.db.Model(event).Omit(clause.Associations).Where("hash = ?", hash).Select(columns).Updates(event)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	var transfer interface{}
+	switch tx := event.(type) {
+	case *models.SrcTransaction:
+		if tx.SrcTransfer != nil {
+			tx.SrcTransfer.TxHash = hash
+			transfer = tx.SrcTransfer
+		}
+	case *models.DstTransaction:
+		if tx.DstTransfer != nil {
+			tx.DstTransfer.TxHash = hash
+			transfer = tx.DstTransfer
+		}
+	}
+	if transfer == nil {
+		return nil
+	}
+	if missingTransfer {
+		return dao.db.Clauses(dbconn.InsertOn("tx_hash")).Create(transfer).Error
+	}
+	if len(transferColumns) > 0 {
+		return dao.db.Model(transfer).Where("tx_hash = ?", hash).Select(transferColumns).Updates(transfer).Error
+	}
 	return nil
 }
 
+func (dao *BridgeDao) GetChainBlock(chainId uint64, height uint64) (*models.ChainBlock, error) {
+	block := new(models.ChainBlock)
+	res := dao.db.Where("chain_id = ? and height = ?", chainId, height).First(block)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return block, nil
+}
+
+func (dao *BridgeDao) SaveChainBlocks(blocks []*models.ChainBlock) error {
+	if len(blocks) == 0 || dao.backup {
+		return nil
+	}
+	return dao.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "height"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hash", "parent_hash"}),
+	}).Create(blocks).Error
+}
+
+// RemoveChainBlocks removes the recorded block hashes of chain in [start, end]
+func (dao *BridgeDao) RemoveChainBlocks(chainId uint64, start, end uint64) error {
+	if dao.backup {
+		return nil
+	}
+	return dao.db.Where("chain_id = ? and height >= ? and height <= ?", chainId, start, end).Delete(&models.ChainBlock{}).Error
+}
+
+// SaveDeadLetterBlocks records the failed block ranges, a range failed again is reset to be retried
+func (dao *BridgeDao) SaveDeadLetterBlocks(blocks []*models.DeadLetterBlock) error {
+	if len(blocks) == 0 || dao.backup {
+		return nil
+	}
+	return dao.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "start_height"}},
+		DoUpdates: clause.AssignmentColumns([]string{"end_height", "error", "attempts", "next_retry_time", "status", "update_time"}),
+	}).Create(blocks).Error
+}
+
+func (dao *BridgeDao) UpdateDeadLetterBlock(block *models.DeadLetterBlock) error {
+	if dao.backup {
+		return nil
+	}
+	return dao.db.Save(block).Error
+}
+
+func (dao *BridgeDao) GetDeadLetterBlock(id int64) (*models.DeadLetterBlock, error) {
+	block := new(models.DeadLetterBlock)
+	res := dao.db.Where("id = ?", id).First(block)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return block, nil
+}
+
+func (dao *BridgeDao) GetDeadLetterBlocks(status int) ([]*models.DeadLetterBlock, error) {
+	blocks := make([]*models.DeadLetterBlock, 0)
+	res := dao.db.Where("status = ?", status).Order("chain_id asc, start_height asc").Find(&blocks)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return blocks, nil
+}
+
+// GetDueDeadLetterBlocks gets the pending failed block ranges of chain whose retry time has come
+func (dao *BridgeDao) GetDueDeadLetterBlocks(chainId uint64, now int64, limit int) ([]*models.DeadLetterBlock, error) {
+	blocks := make([]*models.DeadLetterBlock, 0)
+	res := dao.db.Where("chain_id = ? and status = ? and next_retry_time <= ?", chainId, basedef.DEAD_LETTER_PENDING, now).
+		Order("next_retry_time asc").Limit(limit).Find(&blocks)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return blocks, nil
+}
+
 func (dao *BridgeDao) GetChain(chainId uint64) (*models.Chain, error) {
 	chain := new(models.Chain)
 	res := dao.db.Where("chain_id = ?", chainId).First(chain)
@@ -354,7 +583,7 @@ func (dao *BridgeDao) AggregateTokenBasicSrcTransfers(assetHashes [][]interface{
 		Sum   string
 		Count uint64
 	}
-	res := dao.db.Model(&models.SrcTransfer{}).Select("SUM(amount) as sum, COUNT(*) as count").Where("(chain_id, asset) in ? AND id > ? AND id <= ?", assetHashes, min, max).First(&v)
+	res := dao.db.Model(&models.SrcTransfer{}).Select("SUM(CAST(amount AS DECIMAL(65, 0))) as sum, COUNT(*) as count").Where("(chain_id, asset) in ? AND id > ? AND id <= ?", assetHashes, min, max).First(&v)
 	err = res.Error
 	if res.Error == nil {
 		sum := new(big.Float)
@@ -392,7 +621,7 @@ func (dao *BridgeDao) UpdateTokenAvailableAmount(hash string, chainId uint64, am
 
 func (dao *BridgeDao) CalculateInTokenStatistics(chainId uint64, hash string, lastId, nowId int64) (*models.TokenStatistic, error) {
 	tokenStatistic := new(models.TokenStatistic)
-	res := dao.db.Raw("select count(*) in_counter,  CONVERT(sum(amount), DECIMAL(37, 0)) as in_amount, chain_id as chain_id, asset as hash  from dst_transfers where  chain_id = ? and asset = ? and id > ? and id <= ? group by chain_id,hash", chainId, hash, lastId, nowId).
+	res := dao.db.Raw("select count(*) in_counter,  CAST(sum(CAST(amount AS DECIMAL(65, 0))) AS DECIMAL(37, 0)) as in_amount, chain_id as chain_id, asset as hash  from dst_transfers where  chain_id = ? and asset = ? and id > ? and id <= ? group by chain_id,asset", chainId, hash, lastId, nowId).
 		First(tokenStatistic)
 	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -402,7 +631,7 @@ func (dao *BridgeDao) CalculateInTokenStatistics(chainId uint64, hash string, la
 
 func (dao *BridgeDao) CalculateOutTokenStatistics(chainId uint64, hash string, lastId, nowId int64) (*models.TokenStatistic, error) {
 	tokenStatistic := new(models.TokenStatistic)
-	res := dao.db.Raw("select count(*) out_counter,  CONVERT(sum(amount), DECIMAL(37, 0)) as out_amount, chain_id as chain_id, asset as hash from src_transfers where chain_id = ? and asset = ? and id > ? and id<= ? group by chain_id,hash", chainId, hash, lastId, nowId).
+	res := dao.db.Raw("select count(*) out_counter,  CAST(sum(CAST(amount AS DECIMAL(65, 0))) AS DECIMAL(37, 0)) as out_amount, chain_id as chain_id, asset as hash from src_transfers where chain_id = ? and asset = ? and id > ? and id<= ? group by chain_id,asset", chainId, hash, lastId, nowId).
 		First(tokenStatistic)
 	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -451,7 +680,7 @@ func (dao *BridgeDao) GetNewChainSta() (*models.ChainStatistic, error) {
 	return chainStatistic, res.Error
 }
 func (dao *BridgeDao) CalculateChainStatisticAssets(chainStatistics interface{}) error {
-	res := dao.db.Raw("select count(distinct addresses) as addresses, chain_id from (select  `from` as addresses, chain_id from src_transfers union select `to` as addresses, chain_id from dst_transfers) u group by chain_id").
+	res := dao.db.Raw("select count(distinct addresses) as addresses, chain_id from (select  src_transfers.from as addresses, chain_id from src_transfers union select dst_transfers.to as addresses, chain_id from dst_transfers) u group by chain_id").
 		Scan(chainStatistics)
 	return res.Error
 }
@@ -472,12 +701,12 @@ func (dao *BridgeDao) GetPolyTransaction() (*models.PolyTransaction, error) {
 }
 
 func (dao *BridgeDao) CalculateInChainStatistics(lastId, nowId int64, chainStatistics interface{}) error {
-	res := dao.db.Raw("select count(*) as `in`, chain_id from dst_transactions where id > ? and id <= ? group by chain_id", lastId, nowId).
+	res := dao.db.Raw("select count(*) as \"in\", chain_id from dst_transactions where id > ? and id <= ? group by chain_id", lastId, nowId).
 		Scan(chainStatistics)
 	return res.Error
 }
 func (dao *BridgeDao) CalculateOutChainStatistics(lastId, nowId int64, chainStatistics interface{}) error {
-	res := dao.db.Raw("select count(*) as `out`, chain_id from src_transactions where id > ? and id <= ? group by chain_id", lastId, nowId).
+	res := dao.db.Raw("select count(*) as \"out\", chain_id from src_transactions where id > ? and id <= ? group by chain_id", lastId, nowId).
 		Scan(chainStatistics)
 	return res.Error
 }
@@ -498,9 +727,11 @@ func (dao *BridgeDao) GetNewAssetSta() (*models.AssetStatistic, error) {
 		Error
 	return assetStatistic, err
 }
+
+// CalculateAssets sums the src transfers of the token basic between the ids by token and day
 func (dao *BridgeDao) CalculateAssets(tokenBasicName string, lastId, nowId int64) ([]*models.AssetInfo, error) {
 	assetInfos := make([]*models.AssetInfo, 0)
-	err := dao.db.Debug().Raw("select CONVERT(sum(amount), DECIMAL(37, 0)) as amount, count(*) as txnum, b.token_basic_name, b.precision, c.price  from src_transfers a inner join tokens b on a.chain_id = b.chain_id and a.asset = b.hash left join token_basics c on c.name = b.token_basic_name where b.token_basic_name = ? and a.id > ? and a.id <= ? group by b.chain_id,b.`hash`", tokenBasicName, lastId, nowId).
+	err := dao.db.Debug().Raw("select CAST(sum(CAST(amount AS DECIMAL(65, 0))) AS DECIMAL(37, 0)) as amount, count(*) as txnum, b.token_basic_name, b.precision, c.price, a.time - a.time % 86400 as day  from src_transfers a inner join tokens b on a.chain_id = b.chain_id and a.asset = b.hash left join token_basics c on c.name = b.token_basic_name where b.token_basic_name = ? and a.id > ? and a.id <= ? group by b.chain_id,b.hash,b.token_basic_name,b.precision,c.price,a.time - a.time % 86400", tokenBasicName, lastId, nowId).
 		Find(&assetInfos).Error
 	return assetInfos, err
 }
@@ -516,7 +747,7 @@ func (dao *BridgeDao) SaveAssetStatistic(assetStatistic *models.AssetStatistic)
 }
 func (dao *BridgeDao) CalculateAssetAdress() ([]*models.AssetStatistic, error) {
 	assetStatistics := make([]*models.AssetStatistic, 0)
-	err := dao.db.Raw("select count(distinct `from`) as addressnum,b.token_basic_name from src_transfers a inner join tokens b on a.chain_id = b.chain_id and a.asset = b.hash  group by token_basic_name").
+	err := dao.db.Raw("select count(distinct a.from) as addressnum,b.token_basic_name from src_transfers a inner join tokens b on a.chain_id = b.chain_id and a.asset = b.hash  group by token_basic_name").
 		Find(&assetStatistics).Error
 	return assetStatistics, err
 }
@@ -531,6 +762,22 @@ func (dao *BridgeDao) GetBTCPrice() (*models.TokenBasic, error) {
 	err := dao.db.Where("name='WBTC'").First(tokenBasicBTC).Error
 	return tokenBasicBTC, err
 }
+
+// GetDailyClosePrices returns the closing prices of the token basic by the start of the days between start and end,
+// merged from the price history of any period. The days without the history are not in the prices.
+func (dao *BridgeDao) GetDailyClosePrices(tokenBasicName string, start, end int64) (map[int64]int64, error) {
+	histories := make([]*models.TokenPriceHistory, 0)
+	err := dao.db.Where("name = ? and market = ? and time >= ? and time < ?", tokenBasicName, "", start-start%86400, end).
+		Find(&histories).Error
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[int64]int64)
+	for _, history := range models.DownsampleTokenPrices(histories, 86400) {
+		prices[history.Time] = history.Close
+	}
+	return prices, nil
+}
 func (dao *BridgeDao) GetPropertytokenBasic() ([]*models.TokenBasic, error) {
 	tokenBasics := make([]*models.TokenBasic, 0)
 	err := dao.db.Where("property = ?", 1).
@@ -585,7 +832,7 @@ type TokenStatisticWithName struct {
 
 func (dao *BridgeDao) GetSourceTokenStatistics() ([]*TokenStatisticWithName, error) {
 	sourceTokenStatistics := make([]*TokenStatisticWithName, 0)
-	err := dao.db.Raw("SELECT b.token_basic_name,a.chain_id,a.in_amount,a.in_amount_usd from token_statistics a left join tokens b on a.chain_id=b.chain_id and a.`hash`=b.`hash` left join token_basics c on b.token_basic_name=c.`name` where c.chain_id=a.chain_id").
+	err := dao.db.Raw("SELECT b.token_basic_name,a.chain_id,a.in_amount,a.in_amount_usd from token_statistics a left join tokens b on a.chain_id=b.chain_id and a.hash=b.hash left join token_basics c on b.token_basic_name=c.name where c.chain_id=a.chain_id").
 		Find(&sourceTokenStatistics).Error
 	return sourceTokenStatistics, err
 }
@@ -638,6 +885,10 @@ func (dao *BridgeDao) WrapperTransactionCheckFee(wrapperTransactions []*models.W
 	for _, chainFee := range chainFees {
 		chain2Fees[chainFee.ChainId] = chainFee
 	}
+	gasLimits, err := fee.LoadGasLimits(dao.db)
+	if err != nil {
+		logs.Error("load gas limits err: %v", err)
+	}
 	var curSrcTransaction *models.SrcTransaction
 	var feePayFloat64, feeMinFloat64, PaidGasFloat64 float64
 	for _, v := range wrapperTransactions {
@@ -670,18 +921,12 @@ func (dao *BridgeDao) WrapperTransactionCheckFee(wrapperTransactions []*models.W
 			logs.Info("check fee Wrapper_hash %s NOT_PAID,chainFee hasn't DstChainId's fee", v.Hash)
 			continue
 		}
+		chainFee = gasLimits.ChainFee(chainFee, fee.ProxyType(curSrcTransaction.ChainId, curSrcTransaction.Contract, curSrcTransaction.Standard))
 		//money paid in wrapper
 		feePay, feeMin, gasPay := fee.CheckFeeCal(chainFee, token, v.FeeAmount)
-		// get optimistic L1 fee on ethereum
-		if chainFee.ChainId == basedef.OPTIMISTIC_CROSSCHAIN_ID {
-			ethChainFee, ok := chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID]
-			if !ok {
-				v.IsPaid = false
-				logs.Info("check fee wrapper_hash %s NOT_PAID,chainFee hasn't ethereum fee", v.Hash)
-				continue
-			}
-
-			L1MinFee, _, _, err := fee.GetL1Fee(ethChainFee, chainFee.ChainId)
+		// get L1 fee of layer 2 chains
+		if fee.HasL1Fee(chainFee.ChainId) {
+			L1MinFee, _, _, err := fee.GetL1Fee(chainFee, chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID])
 			if err != nil {
 				v.IsPaid = false
 				logs.Info("check fee wrapper_hash %s NOT_PAID, get L1 fee failed. err=%v", v.Hash, err)
@@ -713,7 +958,13 @@ func (dao *BridgeDao) WrapperTransactionCheckFee(wrapperTransactions []*models.W
 		if feePay.Cmp(feeMin) >= 0 {
 			v.IsPaid = true
 			logs.Info("check fee wrapper_hash %s PAID,feePay %v >= feeMin %v", v.Hash, feePayFloat64, feeMinFloat64)
+		} else if quote, err := fee.QuotedFee(dao.db, v); err == nil && quote != nil {
+			v.IsPaid = true
+			logs.Info("check fee wrapper_hash %s PAID,feePay %v < feeMin %v, paid as quote %s", v.Hash, feePayFloat64, feeMinFloat64, quote.Id)
 		} else {
+			if err != nil {
+				logs.Error("check fee wrapper_hash %s get fee quote err: %v", v.Hash, err)
+			}
 			v.IsPaid = false
 			logs.Info("check fee wrapper_hash %s NOT_PAID,feePay %v < feeMin %v", v.Hash, feePayFloat64, feeMinFloat64)
 		}
@@ -762,7 +1013,7 @@ func (dao *BridgeDao) GetSrcTxsWithIdAndChains(startId int64, endId int64, chain
 
 func (dao *BridgeDao) GetWrapperTxsWithHashes(hashes []string) ([]*models.WrapperTransaction, error) {
 	wrapperTxs := make([]*models.WrapperTransaction, 0)
-	err := dao.db.Where("`hash` in  ?", hashes).
+	err := dao.db.Where("hash in  ?", hashes).
 		Find(&wrapperTxs).
 		Error
 	return wrapperTxs, err
@@ -770,7 +1021,7 @@ func (dao *BridgeDao) GetWrapperTxsWithHashes(hashes []string) ([]*models.Wrappe
 
 func (dao *BridgeDao) GetToken(chain uint64, hash string) (*models.Token, error) {
 	token := new(models.Token)
-	err := dao.db.Where("chain_id = ? and `hash` = ?", chain, hash).Preload("TokenBasic").
+	err := dao.db.Where("chain_id = ? and hash = ?", chain, hash).Preload("TokenBasic").
 		First(token).
 		Error
 	return token, err
@@ -814,7 +1065,7 @@ func (dao *BridgeDao) SaveAirDrop(info *models.AirDropInfo) (err error) {
 
 func (dao *BridgeDao) GetAirDropByUser(user string) (*models.AirDropInfo, error) {
 	airDrop := new(models.AirDropInfo)
-	err := dao.db.Where("user = ?", user).First(airDrop).
+	err := dao.db.Where("air_drop_infos.user = ?", user).First(airDrop).
 		Error
 	return airDrop, err
 }
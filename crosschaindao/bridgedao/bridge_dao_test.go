@@ -8,6 +8,8 @@ import (
 	"poly-bridge/models"
 	"poly-bridge/utils/decimal"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestBridgeDao_WrapperTransactionCheckFee(t *testing.T) {
@@ -27,7 +29,7 @@ func TestBridgeDao_WrapperTransactionCheckFee(t *testing.T) {
 	fmt.Println(dao.WrapperTransactionCheckFee(wrapperTransactions, srcTransactions))
 	jsona, _ = json.MarshalIndent(wrapperTransactions, "", "	")
 	fmt.Println(string(jsona))
-	err := dao.UpdateEvents(wrapperTransactions, srcTransactions, nil, nil)
+	err := dao.UpdateEvents(wrapperTransactions, srcTransactions, nil, nil, nil, nil)
 	fmt.Println("err", err)
 }
 
@@ -55,3 +57,90 @@ func TestPointer(t *testing.T) {
 		fmt.Println("aaa")
 	}
 }
+
+func TestBridgeDao_UpdateEventsSqlite(t *testing.T) {
+	dao := NewBridgeDao(&conf.DBConfig{Dialect: "sqlite", Scheme: "bridge_dao_update_events"}, false)
+	newSrcTransaction := func(state uint64, amount int64) *models.SrcTransaction {
+		return &models.SrcTransaction{
+			Hash:    "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			ChainId: 2,
+			State:   state,
+			Fee:     models.NewBigIntFromInt(1),
+			Key:     "0000000000000000000000000000000000000000000000000000000000000abe",
+			SrcTransfer: &models.SrcTransfer{
+				ChainId: 2,
+				Asset:   "0000000000000000000000000000000000000000",
+				Amount:  models.NewBigIntFromInt(amount),
+			},
+		}
+	}
+	assert.NoError(t, dao.UpdateEvents(nil, []*models.SrcTransaction{newSrcTransaction(0, 100)}, nil, nil, nil, nil))
+	// the block is listened again
+	assert.NoError(t, dao.UpdateEvents(nil, []*models.SrcTransaction{newSrcTransaction(1, 200)}, nil, nil, nil, nil))
+
+	srcTransactions := make([]*models.SrcTransaction, 0)
+	assert.NoError(t, dao.db.Preload("SrcTransfer").Find(&srcTransactions).Error)
+	if assert.Len(t, srcTransactions, 1) && assert.NotNil(t, srcTransactions[0].SrcTransfer) {
+		assert.Equal(t, uint64(1), srcTransactions[0].State)
+		assert.Equal(t, "200", srcTransactions[0].SrcTransfer.Amount.String())
+	}
+}
+
+func TestBridgeDao_UpdateEventFieldsSqlite(t *testing.T) {
+	dao := NewBridgeDao(&conf.DBConfig{Dialect: "sqlite", Scheme: "bridge_dao_update_event_fields"}, false)
+	hash := "6a0f3c1d4e5b8a7f9c2d1e0b3a4f5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b"
+	indexed := &models.SrcTransaction{
+		Hash: hash, ChainId: 2, State: 1, Time: 100, Fee: models.NewBigIntFromInt(1),
+		SrcTransfer: &models.SrcTransfer{ChainId: 2, Asset: "0000000000000000000000000000000000000000", Amount: models.NewBigIntFromInt(100)},
+	}
+	assert.NoError(t, dao.UpdateEvents(nil, []*models.SrcTransaction{indexed}, nil, nil, nil, nil))
+
+	// the block scanned again has the time and the amount changed, the state is the default of the listener
+	scanned := &models.SrcTransaction{
+		Hash: hash, ChainId: 2, Time: 200, Fee: models.NewBigIntFromInt(1),
+		SrcTransfer: &models.SrcTransfer{ChainId: 2, Asset: "0000000000000000000000000000000000000000", Amount: models.NewBigIntFromInt(200)},
+	}
+	assert.NoError(t, dao.UpdateEventFields(scanned, hash, []string{"time", "src_transfer.amount"}))
+
+	srcTransactions := make([]*models.SrcTransaction, 0)
+	assert.NoError(t, dao.db.Preload("SrcTransfer").Find(&srcTransactions).Error)
+	if assert.Len(t, srcTransactions, 1) && assert.NotNil(t, srcTransactions[0].SrcTransfer) {
+		assert.Equal(t, uint64(1), srcTransactions[0].State)
+		assert.Equal(t, uint64(200), srcTransactions[0].Time)
+		assert.Equal(t, "200", srcTransactions[0].SrcTransfer.Amount.String())
+	}
+}
+
+func TestBridgeDao_GetDailyClosePricesSqlite(t *testing.T) {
+	dao := NewBridgeDao(&conf.DBConfig{Dialect: "sqlite", Scheme: "bridge_dao_daily_close_prices"}, false)
+	day := int64(1700006400)
+	hour := &models.TokenPriceHistory{Name: "Ethereum", Period: 3600, Time: day, Open: 100, High: 120, Low: 90, Close: 110, Samples: 60}
+	histories := []*models.TokenPriceHistory{
+		hour,
+		models.NewTokenPriceHistory("Ethereum", "", day+7200, 130),
+		models.NewTokenPriceHistory("Ethereum", "Binance", day+7300, 140),
+		models.NewTokenPriceHistory("Ethereum", "", day+86400+60, 150),
+	}
+	assert.NoError(t, dao.db.Create(histories).Error)
+
+	prices, err := dao.GetDailyClosePrices("Ethereum", day+3600, day+86400*3)
+	assert.NoError(t, err)
+	// the prices of the markets are not merged
+	assert.Equal(t, map[int64]int64{day: 130, day + 86400: 150}, prices)
+
+	asset := "0000000000000000000000000000000000000000"
+	assert.NoError(t, dao.db.Create(&models.Token{Hash: asset, ChainId: 2, Name: "ETH", Precision: 18, TokenBasicName: "Ethereum"}).Error)
+	transfers := []*models.SrcTransfer{
+		{TxHash: "01", ChainId: 2, Time: uint64(day + 10), Asset: asset, Amount: models.NewBigIntFromInt(100)},
+		{TxHash: "02", ChainId: 2, Time: uint64(day + 20), Asset: asset, Amount: models.NewBigIntFromInt(200)},
+		{TxHash: "03", ChainId: 2, Time: uint64(day + 86400), Asset: asset, Amount: models.NewBigIntFromInt(400)},
+	}
+	assert.NoError(t, dao.db.Create(transfers).Error)
+	assetInfos, err := dao.CalculateAssets("Ethereum", 0, transfers[2].Id)
+	assert.NoError(t, err)
+	days := make(map[int64]string)
+	for _, assetInfo := range assetInfos {
+		days[assetInfo.Day] = assetInfo.Amount.String()
+	}
+	assert.Equal(t, map[int64]string{day: "300", day + 86400: "400"}, days)
+}
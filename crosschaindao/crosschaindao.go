@@ -45,6 +45,29 @@ type CrossChainDao interface {
 	GetLatestTx(chainId uint64) (string,string)
 }
 
+// ChainBlockDao is implemented by the daos which keep recent block hashes for reorg detection
+type ChainBlockDao interface {
+	GetChainBlock(chainId uint64, height uint64) (*models.ChainBlock, error)
+	SaveChainBlocks(blocks []*models.ChainBlock) error
+	RemoveChainBlocks(chainId uint64, start, end uint64) error
+	GetEventHashesByHeight(chainId uint64, start, end uint64) ([]string, []string, []string, error)
+}
+
+// DeadLetterDao is implemented by the daos which keep the block ranges failed to be handled
+type DeadLetterDao interface {
+	SaveDeadLetterBlocks(blocks []*models.DeadLetterBlock) error
+	UpdateDeadLetterBlock(block *models.DeadLetterBlock) error
+	GetDeadLetterBlock(id int64) (*models.DeadLetterBlock, error)
+	GetDeadLetterBlocks(status int) ([]*models.DeadLetterBlock, error)
+	GetDueDeadLetterBlocks(chainId uint64, now int64, limit int) ([]*models.DeadLetterBlock, error)
+}
+
+// EventDao is implemented by the daos which can load the indexed events of a chain back, for the backfill diff
+type EventDao interface {
+	GetEvents(chainId uint64, start, end uint64, hashes []string) ([]*models.WrapperTransaction, []*models.SrcTransaction, []*models.PolyTransaction, []*models.DstTransaction, error)
+	UpdateEventFields(event interface{}, hash string, fields []string) error
+}
+
 func NewCrossChainDao(server string, backup bool, dbCfg *conf.DBConfig) CrossChainDao {
 	if server == basedef.SERVER_POLY_SWAP {
 		return swapdao.NewSwapDao(dbCfg, backup)
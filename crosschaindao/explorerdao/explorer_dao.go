@@ -20,12 +20,12 @@ package explorerdao
 import (
 	"encoding/json"
 	"fmt"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	serverconf "poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 )
 
@@ -170,8 +170,7 @@ func NewExplorerDao(dbCfg *conf.DBConfig, backup bool) *ExplorerDao {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -249,13 +248,13 @@ func (dao *ExplorerDao) UpdateEvents(wrapperTransactions []*models.WrapperTransa
 }
 
 func (dao *ExplorerDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
-	dao.db.Where("`txhash` in ?", srcHashes).Delete(&SrcTransfer{})
-	dao.db.Where("`txhash` in ?", srcHashes).Delete(&SrcTransaction{})
+	dao.db.Where("txhash in ?", srcHashes).Delete(&SrcTransfer{})
+	dao.db.Where("txhash in ?", srcHashes).Delete(&SrcTransaction{})
 
-	dao.db.Where("`txhash` in ?", polyHashes).Delete(&PolyTransaction{})
+	dao.db.Where("txhash in ?", polyHashes).Delete(&PolyTransaction{})
 
-	dao.db.Where("`txhash` in ?", dstHashes).Delete(&DstTransfer{})
-	dao.db.Where("`txhash` in ?", dstHashes).Delete(&DstTransaction{})
+	dao.db.Where("txhash in ?", dstHashes).Delete(&DstTransfer{})
+	dao.db.Where("txhash in ?", dstHashes).Delete(&DstTransaction{})
 	return nil
 }
 
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shadowdao
+
+import (
+	"poly-bridge/crosschaindao"
+	"poly-bridge/models"
+	"sync"
+)
+
+// ShadowDao keeps the events in memory instead of saving them, reads are served by the underlying dao.
+// It lets a chain be scanned again without touching the database.
+type ShadowDao struct {
+	crosschaindao.CrossChainDao
+	mutex               sync.Mutex
+	wrapperTransactions map[string]*models.WrapperTransaction
+	srcTransactions     map[string]*models.SrcTransaction
+	polyTransactions    map[string]*models.PolyTransaction
+	dstTransactions     map[string]*models.DstTransaction
+}
+
+func NewShadowDao(dao crosschaindao.CrossChainDao) *ShadowDao {
+	return &ShadowDao{
+		CrossChainDao:       dao,
+		wrapperTransactions: make(map[string]*models.WrapperTransaction),
+		srcTransactions:     make(map[string]*models.SrcTransaction),
+		polyTransactions:    make(map[string]*models.PolyTransaction),
+		dstTransactions:     make(map[string]*models.DstTransaction),
+	}
+}
+
+func (dao *ShadowDao) UpdateEvents(wrapperTransactions []*models.WrapperTransaction, srcTransactions []*models.SrcTransaction, polyTransactions []*models.PolyTransaction, dstTransactions []*models.DstTransaction, wrapperDetails []*models.WrapperDetail, polyDetails []*models.PolyDetail) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	for _, tx := range wrapperTransactions {
+		dao.wrapperTransactions[tx.Hash] = tx
+	}
+	for _, tx := range srcTransactions {
+		dao.srcTransactions[tx.Hash] = tx
+	}
+	for _, tx := range polyTransactions {
+		dao.polyTransactions[tx.Hash] = tx
+	}
+	for _, tx := range dstTransactions {
+		dao.dstTransactions[tx.Hash] = tx
+	}
+	return nil
+}
+
+func (dao *ShadowDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	for _, hash := range srcHashes {
+		delete(dao.wrapperTransactions, hash)
+		delete(dao.srcTransactions, hash)
+	}
+	for _, hash := range polyHashes {
+		delete(dao.polyTransactions, hash)
+	}
+	for _, hash := range dstHashes {
+		delete(dao.dstTransactions, hash)
+	}
+	return nil
+}
+
+func (dao *ShadowDao) UpdateChain(chain *models.Chain) error {
+	return nil
+}
+
+func (dao *ShadowDao) Name() string {
+	return "shadow " + dao.CrossChainDao.Name()
+}
+
+// Events returns the events kept so far
+func (dao *ShadowDao) Events() ([]*models.WrapperTransaction, []*models.SrcTransaction, []*models.PolyTransaction, []*models.DstTransaction) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	wrapperTransactions := make([]*models.WrapperTransaction, 0, len(dao.wrapperTransactions))
+	for _, tx := range dao.wrapperTransactions {
+		wrapperTransactions = append(wrapperTransactions, tx)
+	}
+	srcTransactions := make([]*models.SrcTransaction, 0, len(dao.srcTransactions))
+	for _, tx := range dao.srcTransactions {
+		srcTransactions = append(srcTransactions, tx)
+	}
+	polyTransactions := make([]*models.PolyTransaction, 0, len(dao.polyTransactions))
+	for _, tx := range dao.polyTransactions {
+		polyTransactions = append(polyTransactions, tx)
+	}
+	dstTransactions := make([]*models.DstTransaction, 0, len(dao.dstTransactions))
+	for _, tx := range dao.dstTransactions {
+		dstTransactions = append(dstTransactions, tx)
+	}
+	return wrapperTransactions, srcTransactions, polyTransactions, dstTransactions
+}
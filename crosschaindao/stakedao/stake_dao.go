@@ -18,100 +18,129 @@
 package stakedao
 
 import (
-	"encoding/json"
 	"fmt"
 	"poly-bridge/basedef"
 	serverconf "poly-bridge/conf"
 	"poly-bridge/models"
+	"sync"
 )
 
+// StakeDao keeps the chains, tokens and events in memory, for the servers without a database and the tests
 type StakeDao struct {
-	chains map[uint64]*models.Chain
+	mutex               sync.Mutex
+	chains              map[uint64]*models.Chain
+	tokens              map[string]*models.Token
+	wrapperTransactions map[string]*models.WrapperTransaction
+	srcTransactions     map[string]*models.SrcTransaction
+	polyTransactions    map[string]*models.PolyTransaction
+	dstTransactions     map[string]*models.DstTransaction
 }
 
 func NewStakeDao() *StakeDao {
-	stakeDao := &StakeDao{}
-	chains := make(map[uint64]*models.Chain)
-	chains[2] = &models.Chain{
-		Height: 10017238,
+	return &StakeDao{
+		chains:              make(map[uint64]*models.Chain),
+		tokens:              make(map[string]*models.Token),
+		wrapperTransactions: make(map[string]*models.WrapperTransaction),
+		srcTransactions:     make(map[string]*models.SrcTransaction),
+		polyTransactions:    make(map[string]*models.PolyTransaction),
+		dstTransactions:     make(map[string]*models.DstTransaction),
 	}
-	chains[0] = &models.Chain{
-		Height: 1641496,
-	}
-	chains[6] = &models.Chain{
-		Height: 5698327,
-	}
-	chains[5] = &models.Chain{
-		Height: 5525704,
-	}
-	chains[4] = &models.Chain{
-		Height: 0,
-	}
-	chains[7] = &models.Chain{
-		Height: 2479753,
-	}
-	chains[3] = &models.Chain{
-		Height: 14017221,
-	}
-	chains[79] = &models.Chain{
-		Height: 7889483,
-	}
-	chains[11] = &models.Chain{
-		Height: 0,
-	}
-	for k, v := range chains {
-		v.ChainId = k
-	}
-	stakeDao.chains = chains
-	return stakeDao
+}
+
+func tokenKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, hash)
 }
 
 func (dao *StakeDao) UpdateEvents(wrapperTransactions []*models.WrapperTransaction, srcTransactions []*models.SrcTransaction, polyTransactions []*models.PolyTransaction, dstTransactions []*models.DstTransaction, wrapperDetails []*models.WrapperDetail, polySignDetails []*models.PolyDetail) error {
-	{
-		json, _ := json.Marshal(wrapperTransactions)
-		fmt.Printf("wrapperTransactions: %s\n", json)
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	for _, transaction := range wrapperTransactions {
+		dao.wrapperTransactions[transaction.Hash] = transaction
 	}
-	{
-		json, _ := json.Marshal(srcTransactions)
-		fmt.Printf("srcTransactions: %s\n", json)
+	for _, transaction := range srcTransactions {
+		dao.srcTransactions[transaction.Hash] = transaction
 	}
-	{
-		json, _ := json.Marshal(polyTransactions)
-		fmt.Printf("polyTransactions: %s\n", json)
+	for _, transaction := range polyTransactions {
+		dao.polyTransactions[transaction.Hash] = transaction
 	}
-	{
-		json, _ := json.Marshal(dstTransactions)
-		fmt.Printf("dstTransactions: %s\n", json)
+	for _, transaction := range dstTransactions {
+		dao.dstTransactions[transaction.Hash] = transaction
 	}
 	return nil
 }
 
 func (dao *StakeDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	for _, hash := range srcHashes {
+		delete(dao.wrapperTransactions, hash)
+		delete(dao.srcTransactions, hash)
+	}
+	for _, hash := range polyHashes {
+		delete(dao.polyTransactions, hash)
+	}
+	for _, hash := range dstHashes {
+		delete(dao.dstTransactions, hash)
+	}
 	return nil
 }
 
 func (dao *StakeDao) GetTokenBasicByHash(chainId uint64, hash string) (*models.Token, error) {
-	return nil, nil
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	token, ok := dao.tokens[tokenKey(chainId, hash)]
+	if !ok {
+		return nil, fmt.Errorf("token %s of chain %d is not found", hash, chainId)
+	}
+	return token, nil
 }
 
 func (dao *StakeDao) GetDstTransactionByHash(hash string) (*models.DstTransaction, error) {
-	return nil, nil
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	dstTransaction, ok := dao.dstTransactions[hash]
+	if !ok {
+		return nil, fmt.Errorf("no record!")
+	}
+	return dstTransaction, nil
 }
 
+// GetChain returns the chain kept, or a chain of height 0 which is listened from its latest height
 func (dao *StakeDao) GetChain(chainId uint64) (*models.Chain, error) {
-	return dao.chains[chainId], nil
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	chain, ok := dao.chains[chainId]
+	if !ok {
+		return &models.Chain{ChainId: chainId}, nil
+	}
+	return chain, nil
 }
 
 func (dao *StakeDao) UpdateChain(chain *models.Chain) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
 	dao.chains[chain.ChainId] = chain
 	return nil
 }
 
 func (dao *StakeDao) AddTokens(tokens []*models.TokenBasic, tokenMaps []*models.TokenMap, servercfg *serverconf.Config) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	for _, tokenBasic := range tokens {
+		for _, token := range tokenBasic.Tokens {
+			token.TokenBasic = tokenBasic
+			dao.tokens[tokenKey(token.ChainId, token.Hash)] = token
+		}
+	}
 	return nil
 }
 
-func (dao *StakeDao) AddChains(chain []*models.Chain, chainFees []*models.ChainFee) error {
+func (dao *StakeDao) AddChains(chains []*models.Chain, chainFees []*models.ChainFee) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	for _, chain := range chains {
+		dao.chains[chain.ChainId] = chain
+	}
 	return nil
 }
 
@@ -120,6 +149,17 @@ func (dao *StakeDao) RemoveTokenMaps(tokenMaps []*models.TokenMap) error {
 }
 
 func (dao *StakeDao) RemoveTokens(tokens []string) error {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	names := make(map[string]bool, len(tokens))
+	for _, name := range tokens {
+		names[name] = true
+	}
+	for key, token := range dao.tokens {
+		if names[token.TokenBasicName] {
+			delete(dao.tokens, key)
+		}
+	}
 	return nil
 }
 
@@ -135,6 +175,28 @@ func (dao *StakeDao) FillTxSpecialChain(wrapperTransactions []*models.WrapperTra
 	return
 }
 
+// GetLatestTx returns the hashes of the latest src and dst transactions kept of the chain
 func (dao *StakeDao) GetLatestTx(chainId uint64) (string, string) {
-	return "", ""
-}
\ No newline at end of file
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	var src *models.SrcTransaction
+	for _, transaction := range dao.srcTransactions {
+		if transaction.ChainId == chainId && (src == nil || transaction.Height > src.Height) {
+			src = transaction
+		}
+	}
+	var dst *models.DstTransaction
+	for _, transaction := range dao.dstTransactions {
+		if transaction.ChainId == chainId && (dst == nil || transaction.Height > dst.Height) {
+			dst = transaction
+		}
+	}
+	srcHash, dstHash := "", ""
+	if src != nil {
+		srcHash = src.Hash
+	}
+	if dst != nil {
+		dstHash = dst.Hash
+	}
+	return srcHash, dstHash
+}
@@ -0,0 +1,45 @@
+package stakedao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/models"
+)
+
+func TestStakeDao(t *testing.T) {
+	dao := NewStakeDao()
+	chain, err := dao.GetChain(2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), chain.Height)
+	chain.Height = 100
+	assert.NoError(t, dao.UpdateChain(chain))
+	chain, _ = dao.GetChain(2)
+	assert.Equal(t, uint64(100), chain.Height)
+
+	srcTransactions := []*models.SrcTransaction{{Hash: "01", ChainId: 2, Height: 99}, {Hash: "02", ChainId: 2, Height: 100}}
+	dstTransactions := []*models.DstTransaction{{Hash: "03", ChainId: 2, Height: 98}}
+	assert.NoError(t, dao.UpdateEvents(nil, srcTransactions, nil, dstTransactions, nil, nil))
+	srcHash, dstHash := dao.GetLatestTx(2)
+	assert.Equal(t, "02", srcHash)
+	assert.Equal(t, "03", dstHash)
+	dstTransaction, err := dao.GetDstTransactionByHash("03")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(98), dstTransaction.Height)
+
+	assert.NoError(t, dao.RemoveEvents([]string{"02"}, nil, []string{"03"}))
+	srcHash, dstHash = dao.GetLatestTx(2)
+	assert.Equal(t, "01", srcHash)
+	assert.Equal(t, "", dstHash)
+	_, err = dao.GetDstTransactionByHash("03")
+	assert.Error(t, err)
+
+	tokenBasic := &models.TokenBasic{Name: "USDT", Tokens: []*models.Token{{ChainId: 2, Hash: "dac17f958d2ee523a2206206994597c13d831ec7", TokenBasicName: "USDT"}}}
+	assert.NoError(t, dao.AddTokens([]*models.TokenBasic{tokenBasic}, nil, nil))
+	token, err := dao.GetTokenBasicByHash(2, "dac17f958d2ee523a2206206994597c13d831ec7")
+	assert.NoError(t, err)
+	assert.Equal(t, "USDT", token.TokenBasic.Name)
+	assert.NoError(t, dao.RemoveTokens([]string{"USDT"}))
+	_, err = dao.GetTokenBasicByHash(2, "dac17f958d2ee523a2206206994597c13d831ec7")
+	assert.Error(t, err)
+}
@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/beego/beego/v2/core/logs"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"math/big"
@@ -29,6 +28,7 @@ import (
 	"poly-bridge/coinpricelisten/coinmarketcap"
 	"poly-bridge/conf"
 	serverconf "poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"strings"
 	"time"
@@ -49,8 +49,7 @@ func NewSwapDao(dbCfg *conf.DBConfig, backup bool) *SwapDao {
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -118,14 +117,14 @@ func (dao *SwapDao) UpdateEvents(wrapperTransactions []*models.WrapperTransactio
 }
 
 func (dao *SwapDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
-	dao.db.Where("`tx_hash` in ?", srcHashes).Delete(&models.SrcTransfer{})
-	dao.db.Where("`hash` in ?", srcHashes).Delete(&models.SrcTransaction{})
-	dao.db.Where("`hash` in ?", srcHashes).Delete(&models.WrapperTransaction{})
+	dao.db.Where("tx_hash in ?", srcHashes).Delete(&models.SrcTransfer{})
+	dao.db.Where("hash in ?", srcHashes).Delete(&models.SrcTransaction{})
+	dao.db.Where("hash in ?", srcHashes).Delete(&models.WrapperTransaction{})
 
-	dao.db.Where("`hash` in ?", polyHashes).Delete(&models.PolyTransaction{})
+	dao.db.Where("hash in ?", polyHashes).Delete(&models.PolyTransaction{})
 
-	dao.db.Where("`tx_hash` in ?", dstHashes).Delete(&models.DstTransfer{})
-	dao.db.Where("`hash` in ?", dstHashes).Delete(&models.DstTransaction{})
+	dao.db.Where("tx_hash in ?", dstHashes).Delete(&models.DstTransfer{})
+	dao.db.Where("hash in ?", dstHashes).Delete(&models.DstTransaction{})
 	return nil
 }
 
@@ -23,11 +23,14 @@ import (
 	"poly-bridge/basedef"
 	"poly-bridge/cacheRedis"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
+	"poly-bridge/utils/fee"
+	"poly-bridge/utils/webhook"
+	"sync/atomic"
 	"time"
 
 	"github.com/beego/beego/v2/core/logs"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -43,6 +46,10 @@ type BridgeEffect struct {
 	redisCfg *conf.RedisConfig
 	chains   []*models.Chain
 	time     int64
+	// last time the unlocks are checked against the locks
+	invariantTime int64
+	deliverer     *webhook.Deliverer
+	delivering    int32
 }
 
 func NewBridgeEffect(cfg *conf.EventEffectConfig, dbCfg *conf.DBConfig, redisCfg *conf.RedisConfig) *BridgeEffect {
@@ -57,12 +64,16 @@ func NewBridgeEffect(cfg *conf.EventEffectConfig, dbCfg *conf.DBConfig, redisCfg
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
 	swapEffect.db = db
+	webhookCfg := conf.GlobalConfig.WebhookConfig
+	if webhookCfg == nil {
+		webhookCfg = &conf.WebhookConfig{}
+	}
+	swapEffect.deliverer = webhook.NewDeliverer(db, webhookCfg.MaxAttempts, webhookCfg.Timeout)
 	redis, err := cacheRedis.GetRedisClient(redisCfg)
 	if err != nil {
 		panic(err)
@@ -105,6 +116,11 @@ func (eff *BridgeEffect) Effect() error {
 	if err != nil {
 		logs.Error("check chain listening- err: %s", err)
 	}
+	err = eff.checkInvariants()
+	if err != nil {
+		logs.Error("check invariants- err: %s", err)
+	}
+	eff.deliverWebhooks()
 	counterTime++
 	if counterTime > 180 {
 		counterTime = 0
@@ -112,6 +128,14 @@ func (eff *BridgeEffect) Effect() error {
 		if err != nil {
 			logs.Error("UpdateCrossCount err: %s", err)
 		}
+		_, err = fee.PruneQuotes(eff.db, time.Now().Unix())
+		if err != nil {
+			logs.Error("prune fee quotes err: %s", err)
+		}
+		_, err = fee.CalibrateGasLimits(eff.db, time.Now().Unix())
+		if err != nil {
+			logs.Error("calibrate gas limits err: %s", err)
+		}
 	}
 	return nil
 }
@@ -239,10 +263,12 @@ func (eff *BridgeEffect) updateStatus() error {
 	for {
 		wrapperPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
 		wrapperTransactions := make([]*models.WrapperTransaction, 0)
+		changedTransactions := make([]*models.WrapperTransaction, 0)
 		eff.db.Table("wrapper_transactions").Where("wrapper_transactions.status != ? and wrapper_transactions.time > 1622476800", basedef.STATE_FINISHED).Select("wrapper_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash").Joins("left join poly_transactions on wrapper_transactions.hash = poly_transactions.src_hash").Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").Preload("WrapperTransaction").Preload("DstTransaction").Limit(batch).Offset(batch * index).Order("wrapper_transactions.time desc").Find(&wrapperPolyDstRelations)
 		for _, wrapperPolyDstRelation := range wrapperPolyDstRelations {
 			wrapperTransaction := wrapperPolyDstRelation.WrapperTransaction
 			pending := wrapperTransaction.Status == basedef.STATE_SKIP || wrapperTransaction.Status == basedef.STATE_WAIT
+			status := wrapperTransaction.Status
 			if wrapperPolyDstRelation.PolyHash == "" {
 				chain, ok := id2Chains[wrapperPolyDstRelation.WrapperTransaction.SrcChainId]
 				if ok {
@@ -270,10 +296,18 @@ func (eff *BridgeEffect) updateStatus() error {
 			}
 			if !pending || wrapperTransaction.Status == basedef.STATE_FINISHED {
 				wrapperTransactions = append(wrapperTransactions, wrapperTransaction)
+				if wrapperTransaction.Status != status {
+					changedTransactions = append(changedTransactions, wrapperTransaction)
+				}
 			}
 		}
 		if len(wrapperTransactions) > 0 {
-			eff.db.Save(wrapperTransactions)
+			if err := eff.db.Save(wrapperTransactions).Error; err == nil {
+				eff.publishStatus(changedTransactions)
+				if err = webhook.EmitFinished(eff.db, changedTransactions); err != nil {
+					logs.Error("emit webhook finished events err: %v", err)
+				}
+			}
 		}
 		if len(wrapperPolyDstRelations) == 0 {
 			break
@@ -284,6 +318,34 @@ func (eff *BridgeEffect) updateStatus() error {
 	return nil
 }
 
+// deliverWebhooks posts the due webhook deliveries in the background, the slow webhooks do not hold the effect
+func (eff *BridgeEffect) deliverWebhooks() {
+	if !atomic.CompareAndSwapInt32(&eff.delivering, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&eff.delivering, 0)
+		for {
+			delivered, err := eff.deliverer.Deliver(time.Now().Unix())
+			if err != nil {
+				logs.Error("deliver webhooks err: %v", err)
+			}
+			if err != nil || delivered == 0 {
+				return
+			}
+		}
+	}()
+}
+
+// publishStatus pushes the status changes to the transaction status streams of the http servers
+func (eff *BridgeEffect) publishStatus(wrapperTransactions []*models.WrapperTransaction) {
+	for _, wrapperTransaction := range wrapperTransactions {
+		if err := eff.redis.PublishTransactionStatus(models.MakeWrapperTransactionRsp(wrapperTransaction)); err != nil {
+			logs.Error("publish status %d of %s err: %v", wrapperTransaction.Status, wrapperTransaction.Hash, err)
+		}
+	}
+}
+
 func (eff *BridgeEffect) checkChainListening() error {
 	slot := eff.cfg.ChainListening
 	if slot == 0 {
@@ -324,7 +386,7 @@ type TimeStatistic struct {
 func (eff *BridgeEffect) doStatistic() error {
 	timeStatistics := make([]*TimeStatistic, 0)
 	start := time.Now().Unix() - eff.cfg.TimeStatisticSlot
-	res := eff.db.Raw("select avg(c.time - a.time) * 100000000 as time, a.chain_id as src_chain_id, c.chain_id as dst_chain_id from src_transactions a inner join poly_transactions b on a.hash = b.src_hash inner join dst_transactions c on b.hash = c.poly_hash inner join wrapper_transactions d on a.hash = d.hash where c.time > ? group by a.chain_id,c.chain_id;", start).Scan(&timeStatistics)
+	res := eff.db.Raw("select avg(c.time - a.time) * 100000000 as time, a.chain_id as src_chain_id, c.chain_id as dst_chain_id from src_transactions a inner join poly_transactions b on a.hash = b.src_hash inner join dst_transactions c on b.hash = c.poly_hash inner join wrapper_transactions d on a.hash = d.hash where c.time > ? group by a.chain_id,c.chain_id", start).Scan(&timeStatistics)
 	if res.Error != nil {
 		logs.Error("do avg time statistic err: %v", res.Error.Error())
 	}
@@ -0,0 +1,294 @@
+package bridgeeffect
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/common"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	invariantCheckSlot  = 300
+	invariantHowOld     = 600
+	invariantAlarmKeep  = time.Hour * 24 * 7
+	invariantBatchLimit = 2000
+)
+
+// unlockTokens are the tokens and token maps of the checked unlocks
+type unlockTokens struct {
+	tokens    map[string]*models.Token
+	tokenMaps map[string]bool
+}
+
+func tokenKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, strings.ToLower(hash))
+}
+
+func tokenMapKey(srcChainId uint64, srcHash string, dstChainId uint64, dstHash string) string {
+	return tokenKey(srcChainId, srcHash) + "-" + tokenKey(dstChainId, dstHash)
+}
+
+func (t *unlockTokens) token(chainId uint64, hash string) *models.Token {
+	return t.tokens[tokenKey(chainId, hash)]
+}
+
+func (t *unlockTokens) mapped(srcChainId uint64, srcHash string, dstChainId uint64, dstHash string) bool {
+	return t.tokenMaps[tokenMapKey(srcChainId, srcHash, dstChainId, dstHash)]
+}
+
+// checkUnlock tells why the unlock is not backed by its lock, it is empty when the unlock is fine.
+// The asset and the amount are only checked for the registered tokens.
+func checkUnlock(dst *models.DstTransaction, poly *models.PolyTransaction, src *models.SrcTransaction, tokens *unlockTokens) string {
+	if poly == nil {
+		return fmt.Sprintf("poly hash '%s' is unknown", dst.PolyHash)
+	}
+	if src == nil {
+		return fmt.Sprintf("source transaction %s of poly %s is missing", poly.SrcHash, poly.Hash)
+	}
+	if src.SrcTransfer == nil {
+		if src.SrcSwap != nil {
+			return ""
+		}
+		return fmt.Sprintf("source transfer of %s is missing", src.Hash)
+	}
+	srcTransfer, dstTransfer := src.SrcTransfer, dst.DstTransfer
+	srcToken := tokens.token(srcTransfer.ChainId, srcTransfer.Asset)
+	if srcToken == nil {
+		return ""
+	}
+	if !tokens.mapped(srcTransfer.ChainId, srcTransfer.Asset, dstTransfer.ChainId, dstTransfer.Asset) {
+		return fmt.Sprintf("asset %s on chain %d is not mapped from %s on chain %d",
+			dstTransfer.Asset, dstTransfer.ChainId, srcTransfer.Asset, srcTransfer.ChainId)
+	}
+	dstToken := tokens.token(dstTransfer.ChainId, dstTransfer.Asset)
+	if dstToken == nil || dst.Standard != 0 || srcTransfer.Amount == nil || dstTransfer.Amount == nil {
+		return ""
+	}
+	// dst / 10^dstPrecision > src / 10^srcPrecision
+	dstAmount := new(big.Int).Mul(&dstTransfer.Amount.Int, new(big.Int).Exp(big.NewInt(10), new(big.Int).SetUint64(srcToken.Precision), nil))
+	srcAmount := new(big.Int).Mul(&srcTransfer.Amount.Int, new(big.Int).Exp(big.NewInt(10), new(big.Int).SetUint64(dstToken.Precision), nil))
+	if dstAmount.Cmp(srcAmount) > 0 {
+		return fmt.Sprintf("amount %s (precision %d) exceeds source amount %s (precision %d)",
+			dstTransfer.Amount.String(), dstToken.Precision, srcTransfer.Amount.String(), srcToken.Precision)
+	}
+	return ""
+}
+
+// checkInvariants checks that every unlock of the last InvariantCheckFrom seconds has its poly and source
+// transactions, with a mapped asset and no more amount than locked. Each violation is alarmed once.
+func (eff *BridgeEffect) checkInvariants() error {
+	if eff.cfg.InvariantCheckFrom <= 0 {
+		return nil
+	}
+	now := time.Now().Unix()
+	if now-eff.invariantTime < invariantCheckSlot {
+		return nil
+	}
+	eff.invariantTime = now
+	howOld := eff.cfg.InvariantHowOld
+	if howOld == 0 {
+		howOld = invariantHowOld
+	}
+
+	// the unlocks of the window are paged by time and id, so none of them is skipped however many they are
+	from, to := now-howOld-eff.cfg.InvariantCheckFrom, now-howOld
+	lastTime, lastId := from, int64(math.MaxInt64)
+	for {
+		dstTransfers := make([]*models.DstTransfer, 0)
+		err := eff.db.Where("time <= ? and (time > ? or (time = ? and id > ?))", to, lastTime, lastTime, lastId).
+			Order("time, id").Limit(invariantBatchLimit).Find(&dstTransfers).Error
+		if err != nil {
+			return err
+		}
+		if len(dstTransfers) == 0 {
+			return nil
+		}
+		if err = eff.checkUnlocks(dstTransfers); err != nil {
+			return err
+		}
+		if len(dstTransfers) < invariantBatchLimit {
+			return nil
+		}
+		last := dstTransfers[len(dstTransfers)-1]
+		lastTime, lastId = int64(last.Time), last.Id
+	}
+}
+
+// checkUnlocks checks the unlocks of the transfers against their poly and source transactions
+func (eff *BridgeEffect) checkUnlocks(dstTransfers []*models.DstTransfer) error {
+	dstHashes := make([]string, 0, len(dstTransfers))
+	for _, transfer := range dstTransfers {
+		dstHashes = append(dstHashes, transfer.TxHash)
+	}
+	dstTransactions := make([]*models.DstTransaction, 0)
+	if err := eff.db.Preload("DstTransfer").Where("hash in ?", dstHashes).Find(&dstTransactions).Error; err != nil {
+		return err
+	}
+
+	polyHashes := make([]string, 0, len(dstTransactions))
+	for _, dst := range dstTransactions {
+		if dst.PolyHash != "" {
+			polyHashes = append(polyHashes, dst.PolyHash)
+		}
+	}
+	polyTransactions := make([]*models.PolyTransaction, 0)
+	if err := eff.db.Where("hash in ?", polyHashes).Find(&polyTransactions).Error; err != nil {
+		return err
+	}
+	polys := make(map[string]*models.PolyTransaction, len(polyTransactions))
+	srcHashes := make([]string, 0, len(polyTransactions))
+	for _, poly := range polyTransactions {
+		polys[poly.Hash] = poly
+		srcHashes = append(srcHashes, poly.SrcHash)
+	}
+	// the source hash of some poly transactions is the key of the source transaction
+	srcTransactions := make([]*models.SrcTransaction, 0)
+	if err := eff.db.Preload("SrcTransfer").Preload("SrcSwap").
+		Where("hash in ? or src_transactions.key in ?", srcHashes, srcHashes).Find(&srcTransactions).Error; err != nil {
+		return err
+	}
+	srcs := make(map[string]*models.SrcTransaction, len(srcTransactions)*2)
+	for _, src := range srcTransactions {
+		srcs[src.Hash] = src
+		srcs[tokenKey(src.ChainId, src.Key)] = src
+	}
+
+	tokens, err := eff.unlockTokens(srcTransactions, dstTransactions)
+	if err != nil {
+		return err
+	}
+	for _, dst := range dstTransactions {
+		if dst.DstTransfer == nil {
+			continue
+		}
+		poly := polys[dst.PolyHash]
+		var src *models.SrcTransaction
+		if poly != nil {
+			src = srcs[poly.SrcHash]
+			if src == nil {
+				src = srcs[tokenKey(poly.SrcChainId, poly.SrcHash)]
+			}
+		}
+		if reason := checkUnlock(dst, poly, src, tokens); reason != "" {
+			eff.alarmUnlock(dst, reason)
+		}
+	}
+	return nil
+}
+
+func (eff *BridgeEffect) unlockTokens(srcTransactions []*models.SrcTransaction, dstTransactions []*models.DstTransaction) (*unlockTokens, error) {
+	srcAssets := make([]string, 0)
+	dstAssets := make([]string, 0)
+	for _, src := range srcTransactions {
+		if src.SrcTransfer != nil {
+			srcAssets = append(srcAssets, src.SrcTransfer.Asset)
+		}
+	}
+	for _, dst := range dstTransactions {
+		if dst.DstTransfer != nil {
+			dstAssets = append(dstAssets, dst.DstTransfer.Asset)
+		}
+	}
+	tokens := &unlockTokens{
+		tokens:    make(map[string]*models.Token),
+		tokenMaps: make(map[string]bool),
+	}
+	if len(srcAssets) == 0 {
+		return tokens, nil
+	}
+	tokenList := make([]*models.Token, 0)
+	err := eff.db.Where("hash in ?", append(srcAssets, dstAssets...)).Find(&tokenList).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokenList {
+		tokens.tokens[tokenKey(token.ChainId, token.Hash)] = token
+	}
+	tokenMaps := make([]*models.TokenMap, 0)
+	err = eff.db.Where("src_token_hash in ? and dst_token_hash in ?", srcAssets, dstAssets).Find(&tokenMaps).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, tokenMap := range tokenMaps {
+		tokens.tokenMaps[tokenMapKey(tokenMap.SrcChainId, tokenMap.SrcTokenHash, tokenMap.DstChainId, tokenMap.DstTokenHash)] = true
+	}
+	return tokens, nil
+}
+
+func (eff *BridgeEffect) alarmUnlock(dst *models.DstTransaction, reason string) {
+	key := cacheRedis.UnlockInvariantAlarmPrefix + strings.ToLower(dst.Hash)
+	if existed, err := eff.redis.Exists(key); err == nil && existed {
+		return
+	}
+	logs.Error("unlock without lock, chain: %d, hash: %s, %s", dst.ChainId, dst.Hash, reason)
+	// the chain is paused once for the unlock, the alarm is resent until it succeeds without undoing a manual resume
+	pauseKey := cacheRedis.UnlockInvariantPausePrefix + strings.ToLower(dst.Hash)
+	if cfg := conf.GlobalConfig.CircuitBreakerConfig; cfg != nil && cfg.AutoPause {
+		if locked, err := eff.redis.Lock(pauseKey, "paused", invariantAlarmKeep); err != nil || !locked {
+			logs.Info("chain %d is paused for unlock %s before", dst.ChainId, dst.Hash)
+		} else {
+			eff.pauseChain(dst, reason)
+		}
+	}
+	if err := sendUnlockInvariantAlarm(dst, reason); err != nil {
+		logs.Error("send unlock invariant alarm failed. hash=%s, err:%s", dst.Hash, err)
+		return
+	}
+	if _, err := eff.redis.Set(key, "done", invariantAlarmKeep); err != nil {
+		logs.Error("mark unlock hash: %s alarm done err: %s", dst.Hash, err)
+	}
+}
+
+func (eff *BridgeEffect) pauseChain(dst *models.DstTransaction, reason string) {
+	if _, err := eff.redis.PauseCircuit(&basedef.CircuitBreak{
+		Scope:    basedef.CIRCUIT_CHAIN,
+		Target:   fmt.Sprint(dst.ChainId),
+		Reason:   fmt.Sprintf("unlock %s without lock: %s", dst.Hash, reason),
+		Operator: "invariant",
+		Auto:     true,
+		Time:     time.Now().Unix(),
+	}); err != nil {
+		logs.Error("pause chain %d for unlock %s err: %v", dst.ChainId, dst.Hash, err)
+	}
+}
+
+func sendUnlockInvariantAlarm(dst *models.DstTransaction, reason string) error {
+	botConfig := conf.GlobalConfig.BotConfig
+	if botConfig == nil {
+		return fmt.Errorf("bot is not configured")
+	}
+	chatId := botConfig.ExploitChatId
+	if chatId == 0 {
+		chatId = botConfig.LargeTxChatId
+	}
+	amount := ""
+	if dst.DstTransfer.Amount != nil {
+		amount = dst.DstTransfer.Amount.String()
+	}
+	title := fmt.Sprintf("*Unlock Without Lock Alarm!!! (chain %d)*", dst.ChainId)
+	text := fmt.Sprintf("%s\n*Reason*: %s\n*Asset*: %s\n*Amount*: %s\n*To*: %s\n*Hash*: %s\n*Poly Hash*: %s\n*Time*: %s\n%s",
+		title,
+		reason,
+		dst.DstTransfer.Asset,
+		amount,
+		dst.DstTransfer.To,
+		dst.Hash,
+		dst.PolyHash,
+		time.Unix(int64(dst.Time), 0).Format("2006-01-02 15:04:05"),
+		"-----------------------------------------",
+	)
+	msg := tgbotapi.NewMessage(chatId, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	_, err := common.SendTgBotMessage(msg)
+	return err
+}
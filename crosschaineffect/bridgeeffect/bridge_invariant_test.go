@@ -0,0 +1,43 @@
+package bridgeeffect
+
+import (
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/models"
+	"testing"
+)
+
+func TestCheckUnlock(t *testing.T) {
+	tokens := &unlockTokens{
+		tokens: map[string]*models.Token{
+			tokenKey(2, "usdt"):     {Hash: "usdt", ChainId: 2, Precision: 6},
+			tokenKey(6, "bep_usdt"): {Hash: "bep_usdt", ChainId: 6, Precision: 18},
+		},
+		tokenMaps: map[string]bool{
+			tokenMapKey(2, "usdt", 6, "bep_usdt"): true,
+		},
+	}
+	poly := &models.PolyTransaction{Hash: "poly", SrcHash: "src"}
+	src := &models.SrcTransaction{Hash: "src", SrcTransfer: &models.SrcTransfer{ChainId: 2, Asset: "usdt", Amount: models.NewBigIntFromInt(1000000)}}
+	newDst := func(asset string, amount int64) *models.DstTransaction {
+		return &models.DstTransaction{Hash: "dst", PolyHash: "poly", DstTransfer: &models.DstTransfer{ChainId: 6, Asset: asset, Amount: models.NewBigIntFromInt(amount)}}
+	}
+
+	// 1 usdt locked, 1 usdt unlocked with 18 decimals
+	dst := newDst("bep_usdt", 1000000000000000000)
+	assert.Equal(t, "", checkUnlock(dst, poly, src, tokens))
+
+	dst = newDst("bep_usdt", 1000000000000000001)
+	assert.Contains(t, checkUnlock(dst, poly, src, tokens), "exceeds source amount")
+
+	dst = newDst("weth", 1)
+	assert.Contains(t, checkUnlock(dst, poly, src, tokens), "is not mapped")
+
+	assert.Contains(t, checkUnlock(dst, nil, nil, tokens), "is unknown")
+	assert.Contains(t, checkUnlock(dst, poly, nil, tokens), "is missing")
+	assert.Contains(t, checkUnlock(dst, poly, &models.SrcTransaction{Hash: "src"}, tokens), "source transfer")
+	assert.Equal(t, "", checkUnlock(dst, poly, &models.SrcTransaction{Hash: "src", SrcSwap: &models.SrcSwap{}}, tokens))
+
+	// unregistered tokens are not checked
+	src.SrcTransfer.Asset = "unknown"
+	assert.Equal(t, "", checkUnlock(dst, poly, src, tokens))
+}
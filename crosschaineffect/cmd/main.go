@@ -27,6 +27,7 @@ import (
 	"poly-bridge/common"
 	"poly-bridge/conf"
 	"poly-bridge/crosschaineffect"
+	"poly-bridge/dbconn"
 	"runtime"
 	"strings"
 	"syscall"
@@ -101,11 +102,17 @@ func startServer(ctx *cli.Context) {
 		logs.Error("startServer - read config failed!")
 		return
 	}
+	if err := dbconn.CheckSchema(config); err != nil {
+		panic(err)
+	}
 	{
 		conf, _ := json.Marshal(config)
 		logs.Info("%s\n", string(conf))
 	}
 	common.SetupChainsSDK(config)
+	if config.EventEffectConfig.InvariantCheckFrom > 0 {
+		common.TgBotInit()
+	}
 	crosschaineffect.StartCrossChainEffect(config.Server, config.EventEffectConfig, config.DBConfig, config.RedisConfig)
 }
 
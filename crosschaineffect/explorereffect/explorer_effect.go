@@ -19,12 +19,12 @@ package explorereffect
 
 import (
 	"github.com/beego/beego/v2/core/logs"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	"poly-bridge/crosschaindao/explorerdao"
+	"poly-bridge/dbconn"
 	"time"
 )
 
@@ -47,8 +47,7 @@ func NewExplorerEffect(effCfg *conf.EventEffectConfig, dbCfg *conf.DBConfig) *Ex
 	if dbCfg.Debug == true {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(dbCfg, Logger)
 	if err != nil {
 		panic(err)
 	}
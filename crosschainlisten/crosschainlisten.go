@@ -93,45 +93,35 @@ type ChainHandle interface {
 	GetBatchLength() (uint64, uint64)
 }
 
+type ChainHandleFactory func(chainListenConfig *conf.ChainListenConfig) ChainHandle
+
+var chainHandleFactories = map[string]ChainHandleFactory{
+	basedef.FAMILY_POLY:     func(cfg *conf.ChainListenConfig) ChainHandle { return polylisten.NewPolyChainListen(cfg) },
+	basedef.FAMILY_EVM:      func(cfg *conf.ChainListenConfig) ChainHandle { return ethereumlisten.NewEthereumChainListen(cfg) },
+	basedef.FAMILY_NEO:      func(cfg *conf.ChainListenConfig) ChainHandle { return neolisten.NewNeoChainListen(cfg) },
+	basedef.FAMILY_ONTOLOGY: func(cfg *conf.ChainListenConfig) ChainHandle { return ontologylisten.NewOntologyChainListen(cfg) },
+	basedef.FAMILY_ONTEVM:   func(cfg *conf.ChainListenConfig) ChainHandle { return ontevmlisten.NewOntevmChainListen(cfg) },
+	basedef.FAMILY_O3:       func(cfg *conf.ChainListenConfig) ChainHandle { return o3listen.NewO3ChainListen(cfg) },
+	basedef.FAMILY_SWITCHEO: func(cfg *conf.ChainListenConfig) ChainHandle { return switcheolisten.NewSwitcheoChainListen(cfg) },
+	basedef.FAMILY_NEO3:     func(cfg *conf.ChainListenConfig) ChainHandle { return neo3listen.NewNeo3ChainListen(cfg) },
+	basedef.FAMILY_ZILLIQA:  func(cfg *conf.ChainListenConfig) ChainHandle { return zilliqalisten.NewZilliqaChainListen(cfg) },
+	basedef.FAMILY_STARCOIN: func(cfg *conf.ChainListenConfig) ChainHandle { return starcoinlisten.NewStarcoinChainListen(cfg) },
+	basedef.FAMILY_RIPPLE:   func(cfg *conf.ChainListenConfig) ChainHandle { return ripplelisten.NewRippleChainListen(cfg) },
+	basedef.FAMILY_APTOS:    func(cfg *conf.ChainListenConfig) ChainHandle { return aptoslisten.NewAptosChainListen(cfg) },
+	basedef.FAMILY_BFC:      func(cfg *conf.ChainListenConfig) ChainHandle { return bfclisten.NewBfcChainListen(cfg) },
+}
+
+// RegisterChainHandle sets the listener factory of a chain family
+func RegisterChainHandle(family string, factory ChainHandleFactory) {
+	chainHandleFactories[family] = factory
+}
+
 func NewChainHandle(chainListenConfig *conf.ChainListenConfig) ChainHandle {
-	switch chainListenConfig.ChainId {
-	case basedef.POLY_CROSSCHAIN_ID:
-		return polylisten.NewPolyChainListen(chainListenConfig)
-	case basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.PLT_CROSSCHAIN_ID, basedef.OK_CROSSCHAIN_ID,
-		basedef.HECO_CROSSCHAIN_ID, basedef.MATIC_CROSSCHAIN_ID, basedef.ARBITRUM_CROSSCHAIN_ID, basedef.XDAI_CROSSCHAIN_ID,
-		basedef.FANTOM_CROSSCHAIN_ID, basedef.AVAX_CROSSCHAIN_ID, basedef.OPTIMISTIC_CROSSCHAIN_ID, basedef.METIS_CROSSCHAIN_ID,
-		basedef.BOBA_CROSSCHAIN_ID, basedef.RINKEBY_CROSSCHAIN_ID, basedef.OASIS_CROSSCHAIN_ID, basedef.HARMONY_CROSSCHAIN_ID,
-		basedef.KCC_CROSSCHAIN_ID, basedef.BYTOM_CROSSCHAIN_ID, basedef.HSC_CROSSCHAIN_ID, basedef.KAVA_CROSSCHAIN_ID,
-		basedef.CUBE_CROSSCHAIN_ID, basedef.ZKSYNC_CROSSCHAIN_ID, basedef.CELO_CROSSCHAIN_ID, basedef.CLOVER_CROSSCHAIN_ID,
-		basedef.CONFLUX_CROSSCHAIN_ID, basedef.ASTAR_CROSSCHAIN_ID, basedef.BRISE_CROSSCHAIN_ID, basedef.DEXIT_CROSSCHAIN_ID,
-		basedef.CLOUDTX_CROSSCHAIN_ID, basedef.XINFIN_CROSSCHAIN_ID:
-		return ethereumlisten.NewEthereumChainListen(chainListenConfig)
-	case basedef.NEO_CROSSCHAIN_ID:
-		return neolisten.NewNeoChainListen(chainListenConfig)
-	case basedef.ONT_CROSSCHAIN_ID:
-		return ontologylisten.NewOntologyChainListen(chainListenConfig)
-	case basedef.ONTEVM_CROSSCHAIN_ID:
-		return ontevmlisten.NewOntevmChainListen(chainListenConfig)
-	case basedef.O3_CROSSCHAIN_ID:
-		return o3listen.NewO3ChainListen(chainListenConfig)
-	case basedef.SWITCHEO_CROSSCHAIN_ID:
-		return switcheolisten.NewSwitcheoChainListen(chainListenConfig)
-	case basedef.NEO3_CROSSCHAIN_ID:
-		return neo3listen.NewNeo3ChainListen(chainListenConfig)
-	case basedef.ZILLIQA_CROSSCHAIN_ID:
-		return zilliqalisten.NewZilliqaChainListen(chainListenConfig)
-	case basedef.STARCOIN_CROSSCHAIN_ID:
-		return starcoinlisten.NewStarcoinChainListen(chainListenConfig)
-	case basedef.RIPPLE_CROSSCHAIN_ID:
-		return ripplelisten.NewRippleChainListen(chainListenConfig)
-	case basedef.APTOS_CROSSCHAIN_ID:
-		return aptoslisten.NewAptosChainListen(chainListenConfig)
-	case basedef.BFC_CROSSCHAIN_ID:
-		return bfclisten.NewBfcChainListen(chainListenConfig)
-
-	default:
+	factory, ok := chainHandleFactories[basedef.GetChainFamily(chainListenConfig.ChainId)]
+	if !ok {
 		return nil
 	}
+	return factory(chainListenConfig)
 }
 
 type CrossChainListen struct {
@@ -141,6 +131,10 @@ type CrossChainListen struct {
 	height  uint64
 	config  *conf.Config
 	dingMux sync.Mutex
+	// consecutive failed rounds of the block ranges, by start height
+	failures map[uint64]uint64
+	// set when the chain is scanned again by Backfill, no alert is sent then
+	backfill bool
 }
 
 func NewCrossChainListen(handle ChainHandle, db crosschaindao.CrossChainDao, config *conf.Config) *CrossChainListen {
@@ -163,6 +157,9 @@ func (ccl *CrossChainListen) Start() {
 	}
 	logs.Info("start cross chain listen: %s", ccl.handle.GetChainName())
 	go ccl.ListenChain()
+	if _, _, ok := ccl.deadLetterHandles(); ok {
+		go ccl.RetryDeadLetters()
+	}
 }
 
 func (ccl *CrossChainListen) Stop() {
@@ -203,6 +200,75 @@ func (ccl *CrossChainListen) HandleNewBatchBlock(start, end uint64) (w []*models
 	return
 }
 
+// handleBatchBlock handles the blocks in [start, end] and saves their events
+func (ccl *CrossChainListen) handleBatchBlock(start, end uint64) error {
+	wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, err := ccl.HandleNewBatchBlock(start, end)
+	if err != nil {
+		logs.Error("HandleNewBlock chain：%s, start: %d, end: %d err: %v", ccl.handle.GetChainName(), start, end, err)
+		return err
+	}
+	logs.Info("HandleNewBlock [chainName: %s, start: %d, end: %d ]. "+
+		"len(wrapperTransactions)=%d, len(srcTransactions)=%d, len(polyTransactions)=%d, len(dstTransactions)=%d",
+		ccl.handle.GetChainName(), start, end, len(wrapperTransactions), len(srcTransactions), len(polyTransactions), len(dstTransactions))
+	checkErr := ccl.db.WrapperTransactionCheckFee(wrapperTransactions, srcTransactions)
+	if checkErr != nil {
+		logs.Error("check fee on block %d-%d err: %v", start, end, checkErr)
+	}
+	err = ccl.db.UpdateEvents(wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, nil, nil)
+	if err != nil {
+		logs.Error("UpdateEvents on block %d-%d err: %v", start, end, err)
+		return err
+	}
+	if !ccl.config.Backup && !ccl.backfill {
+		go ccl.checkLargeTransaction(srcTransactions)
+		go ccl.checkOutflow(srcTransactions)
+		go ccl.publishStatus(wrapperTransactions)
+	}
+	return checkErr
+}
+
+// handleBlock handles the block at height and saves its events
+func (ccl *CrossChainListen) handleBlock(height uint64) error {
+	wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, wrapperDetails, polyDetails, err := ccl.HandleNewBlock(height)
+	if err != nil {
+		logs.Error("HandleNewBlock chain：%s, height: %d err: %v", ccl.handle.GetChainName(), height, err)
+		return err
+	}
+	logs.Info("HandleNewBlock [chainName: %s, height: %d]. "+
+		"len(wrapperTransactions)=%d, len(srcTransactions)=%d, len(polyTransactions)=%d, len(dstTransactions)=%d, len(wrapperDetails)=%d, len(polyDetails)=%d",
+		ccl.handle.GetChainName(), height, len(wrapperTransactions), len(srcTransactions), len(polyTransactions), len(dstTransactions), len(wrapperDetails), len(polyDetails))
+	detailWrapperTxs, fillErr := ccl.db.FillTxSpecialChain(wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, wrapperDetails, polyDetails)
+	if fillErr != nil {
+		logs.Error("FillTxSpecialChain on block %d err: %v", height, fillErr)
+	}
+	wrapperTransactions = append(wrapperTransactions, detailWrapperTxs...)
+
+	checkErr := ccl.db.WrapperTransactionCheckFee(wrapperTransactions, srcTransactions)
+	if checkErr != nil {
+		logs.Error("check fee on block %d err: %v", height, checkErr)
+	}
+	err = ccl.db.UpdateEvents(wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, wrapperDetails, polyDetails)
+	if err != nil {
+		logs.Error("UpdateEvents on block %d err: %v", height, err)
+		return err
+	}
+	if !ccl.config.Backup && !ccl.backfill {
+		go ccl.checkLargeTransaction(srcTransactions)
+		go ccl.checkOutflow(srcTransactions)
+		go ccl.publishStatus(wrapperTransactions)
+	}
+	if fillErr != nil {
+		return fillErr
+	}
+	return checkErr
+}
+
+// isBatchChain tells whether the chain is listened by block ranges with HandleNewBatchBlock
+func (ccl *CrossChainListen) isBatchChain() bool {
+	chainId := ccl.handle.GetChainId()
+	return basedef.IsETHChain(chainId) && chainId != basedef.O3_CROSSCHAIN_ID && chainId != basedef.ONTEVM_CROSSCHAIN_ID
+}
+
 func (ccl *CrossChainListen) listenChain() (exit bool) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -229,10 +295,11 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 		chain.Height -= ccl.handle.GetDefer()
 	}
 	logs.Info("cross chain listen, chain: %s, dao: %s......", ccl.handle.GetChainName(), ccl.db.Name())
-	ticker := time.NewTicker(time.Second * time.Duration(ccl.handle.GetChainListenSlot()))
+	ticker, stopTicker := ccl.newListenTicker()
+	defer stopTicker()
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker:
 			if ccl.config.Backup {
 				dbchain, err := ccl.db.GetChain(chain.ChainId)
 				if err != nil {
@@ -318,8 +385,12 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 					logs.Info("ListenChain - chain %s latest height is %d, listen height: %d", ccl.handle.GetChainName(), height, chain.Height)
 				}
 			}
-			if basedef.IsETHChain(ccl.handle.GetChainId()) && ccl.handle.GetChainId() != basedef.O3_CROSSCHAIN_ID && ccl.handle.GetChainId() != basedef.ONTEVM_CROSSCHAIN_ID {
+			if ccl.isBatchChain() {
 				for chain.Height < height-ccl.handle.GetDefer() {
+					if err := ccl.checkReorg(chain); err != nil {
+						logs.Error("checkReorg chain: %s, height: %d err: %v", ccl.handle.GetChainName(), chain.Height, err)
+						break
+					}
 					batchSize := ccl.handle.GetBatchSize() //concurrency size
 					if batchSize == 0 {
 						batchSize = 1
@@ -343,7 +414,17 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 						batchSize = (height-chain.Height-ccl.handle.GetDefer()-1)/batchLength + 1
 					}
 
-					ch := make(chan bool, batchSize)
+					batchEnd := chain.Height + batchSize*batchLength
+					if batchEnd > height-ccl.handle.GetDefer() {
+						batchEnd = height - ccl.handle.GetDefer()
+					}
+					chainBlocks, err := ccl.fetchChainBlocks(chain.Height+1, batchEnd)
+					if err != nil {
+						logs.Error("fetchChainBlocks chain: %s, start: %d, end: %d err: %v", ccl.handle.GetChainName(), chain.Height+1, batchEnd, err)
+						break
+					}
+
+					ch := make(chan *blockRangeResult, batchSize)
 					for i := uint64(1); i <= batchSize; i++ {
 						start := chain.Height + (i-1)*batchLength + 1
 						end := chain.Height + i*batchLength
@@ -354,42 +435,17 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 							continue
 						}
 						go func(start uint64, end uint64) {
-							wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, err := ccl.HandleNewBatchBlock(start, end)
-							if err != nil {
-								logs.Error("HandleNewBlock chain：%s, height: %d err: %v", ccl.handle.GetChainName(), height, err)
-								ch <- false
-								return
-							}
-							logs.Info("HandleNewBlock [chainName: %s, height: %d, start: %d, end: %d ]. "+
-								"len(wrapperTransactions)=%d, len(srcTransactions)=%d, len(polyTransactions)=%d, len(dstTransactions)=%d",
-								chain.Name, height, start, end, len(wrapperTransactions), len(srcTransactions), len(polyTransactions), len(dstTransactions))
-							err = ccl.db.WrapperTransactionCheckFee(wrapperTransactions, srcTransactions)
-							if err != nil {
-								logs.Error("check fee on block %d err: %v", height, err)
-								ch <- false
-							}
-							err = ccl.db.UpdateEvents(wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, nil, nil)
-							if err != nil {
-								logs.Error("UpdateEvents on block %d err: %v", height, err)
-								ch <- false
-							} else {
-								if !ccl.config.Backup {
-									go ccl.checkLargeTransaction(srcTransactions)
-								}
-								ch <- true
-							}
-
+							ch <- &blockRangeResult{start: start, end: end, err: ccl.handleBatchBlock(start, end)}
 						}(start, end)
 					}
-					allTaskSuccess := true
+					failed := make([]*blockRangeResult, 0)
 					for j := 0; j < int(batchSize); j++ {
-						ok := <-ch
-						if !ok {
-							allTaskSuccess = false
+						if result := <-ch; result.err != nil {
+							failed = append(failed, result)
 						}
 					}
 					close(ch)
-					if !allTaskSuccess {
+					if len(failed) > 0 && !ccl.deadLetter(failed) {
 						break
 					}
 
@@ -402,6 +458,9 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 					if err := ccl.db.UpdateChain(chain); err != nil {
 						logs.Error("UpdateChain [chainId:%d, height:%d] err %v", chain.ChainId, chain.Height, err)
 						chain.Height = flagChainHeight
+					} else {
+						ccl.saveChainBlocks(chainBlocks, endheight)
+						ccl.resetDeadLetterFailures()
 					}
 				}
 			} else {
@@ -435,6 +494,10 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 				case basedef.BFC_CROSSCHAIN_ID:
 				default:
 					for chain.Height < height-ccl.handle.GetDefer() {
+						if err := ccl.checkReorg(chain); err != nil {
+							logs.Error("checkReorg chain: %s, height: %d err: %v", ccl.handle.GetChainName(), chain.Height, err)
+							break
+						}
 						batchSize := ccl.handle.GetBatchSize()
 						if batchSize == 0 {
 							batchSize = 1
@@ -442,53 +505,26 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 						if batchSize > height-chain.Height-ccl.handle.GetDefer() {
 							batchSize = height - chain.Height - ccl.handle.GetDefer()
 						}
+						chainBlocks, err := ccl.fetchChainBlocks(chain.Height+1, chain.Height+batchSize)
+						if err != nil {
+							logs.Error("fetchChainBlocks chain: %s, start: %d, end: %d err: %v", ccl.handle.GetChainName(), chain.Height+1, chain.Height+batchSize, err)
+							break
+						}
 
-						ch := make(chan bool, batchSize)
+						ch := make(chan *blockRangeResult, batchSize)
 						for i := uint64(1); i <= batchSize; i++ {
 							go func(height uint64) {
-								wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, wrapperDetails, polyDetails, err := ccl.HandleNewBlock(height)
-								if err != nil {
-									logs.Error("HandleNewBlock chain：%s, height: %d err: %v", ccl.handle.GetChainName(), height, err)
-									ch <- false
-									return
-								}
-								logs.Info("HandleNewBlock [chainName: %s, height: %d]. "+
-									"len(wrapperTransactions)=%d, len(srcTransactions)=%d, len(polyTransactions)=%d, len(dstTransactions)=%d, len(wrapperDetails)=%d, len(polyDetails)=%d",
-									chain.Name, height, len(wrapperTransactions), len(srcTransactions), len(polyTransactions), len(dstTransactions), len(wrapperDetails), len(polyDetails))
-								detailWrapperTxs, err := ccl.db.FillTxSpecialChain(wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, wrapperDetails, polyDetails)
-								if err != nil {
-									logs.Error("FillTxSpecialChain on block %d err: %v", height, err)
-									ch <- false
-								}
-								wrapperTransactions = append(wrapperTransactions, detailWrapperTxs...)
-
-								err = ccl.db.WrapperTransactionCheckFee(wrapperTransactions, srcTransactions)
-								if err != nil {
-									logs.Error("check fee on block %d err: %v", height, err)
-									ch <- false
-								}
-								err = ccl.db.UpdateEvents(wrapperTransactions, srcTransactions, polyTransactions, dstTransactions, wrapperDetails, polyDetails)
-								if err != nil {
-									logs.Error("UpdateEvents on block %d err: %v", height, err)
-									ch <- false
-								} else {
-									if !ccl.config.Backup {
-										go ccl.checkLargeTransaction(srcTransactions)
-									}
-									ch <- true
-								}
-
+								ch <- &blockRangeResult{start: height, end: height, err: ccl.handleBlock(height)}
 							}(chain.Height + i)
 						}
-						allTaskSuccess := true
+						failed := make([]*blockRangeResult, 0)
 						for j := 0; j < int(batchSize); j++ {
-							ok := <-ch
-							if !ok {
-								allTaskSuccess = false
+							if result := <-ch; result.err != nil {
+								failed = append(failed, result)
 							}
 						}
 						close(ch)
-						if !allTaskSuccess {
+						if len(failed) > 0 && !ccl.deadLetter(failed) {
 							break
 						}
 
@@ -496,6 +532,9 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 						if err := ccl.db.UpdateChain(chain); err != nil {
 							logs.Error("UpdateChain [chainId:%d, height:%d] err %v", chain.ChainId, chain.Height, err)
 							chain.Height -= batchSize
+						} else {
+							ccl.saveChainBlocks(chainBlocks, chain.Height)
+							ccl.resetDeadLetterFailures()
 						}
 					}
 				}
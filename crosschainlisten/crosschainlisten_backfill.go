@@ -0,0 +1,360 @@
+package crosschainlisten
+
+import (
+	"fmt"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/crosschaindao"
+	"poly-bridge/crosschaindao/shadowdao"
+	"poly-bridge/models"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+const (
+	backfillRetries   = 3
+	backfillRetrySlot = 5 * time.Second
+)
+
+// BackfillEvents are the events of a block range, by table
+type BackfillEvents struct {
+	WrapperTransactions []*models.WrapperTransaction
+	SrcTransactions     []*models.SrcTransaction
+	PolyTransactions    []*models.PolyTransaction
+	DstTransactions     []*models.DstTransaction
+}
+
+func (events *BackfillEvents) Len() int {
+	return len(events.WrapperTransactions) + len(events.SrcTransactions) + len(events.PolyTransactions) + len(events.DstTransactions)
+}
+
+// BackfillChange is an event indexed with fields different from the ones scanned again
+type BackfillChange struct {
+	Table  string
+	Hash   string
+	Fields []string
+}
+
+// BackfillDiff is the difference between the events scanned again and the indexed ones
+type BackfillDiff struct {
+	// scanned but not indexed
+	Missing BackfillEvents
+	// scanned and indexed with different fields, the scanned events are kept
+	Changed BackfillEvents
+	Changes []*BackfillChange
+	// indexed in the block range but not scanned
+	Extra BackfillEvents
+}
+
+func (diff *BackfillDiff) Empty() bool {
+	return diff.Missing.Len() == 0 && diff.Changed.Len() == 0 && diff.Extra.Len() == 0
+}
+
+// Backfill scans the blocks in [start, end] of the chain again into a shadow dao, the same way the
+// listener handles them, and diffs the events against the indexed ones. With apply, the missing events are
+// saved and only the changed fields of the changed events are updated, the extra ones are only reported.
+func Backfill(handle ChainHandle, dao crosschaindao.CrossChainDao, config *conf.Config, start, end uint64, apply bool) (*BackfillDiff, error) {
+	chainId := handle.GetChainId()
+	if chainId == basedef.APTOS_CROSSCHAIN_ID || chainId == basedef.BFC_CROSSCHAIN_ID {
+		return nil, fmt.Errorf("chain %s is not listened by height", handle.GetChainName())
+	}
+	if start == 0 || end < start {
+		return nil, fmt.Errorf("invalid block range [%d, %d]", start, end)
+	}
+	eventDao, ok := dao.(crosschaindao.EventDao)
+	if !ok {
+		return nil, fmt.Errorf("dao %s can not load events", dao.Name())
+	}
+	shadow := shadowdao.NewShadowDao(dao)
+	ccl := NewCrossChainListen(handle, shadow, config)
+	ccl.backfill = true
+	if err := ccl.scanBlocks(start, end); err != nil {
+		return nil, err
+	}
+
+	scanned := new(BackfillEvents)
+	scanned.WrapperTransactions, scanned.SrcTransactions, scanned.PolyTransactions, scanned.DstTransactions = shadow.Events()
+	indexed := new(BackfillEvents)
+	var err error
+	indexed.WrapperTransactions, indexed.SrcTransactions, indexed.PolyTransactions, indexed.DstTransactions, err =
+		eventDao.GetEvents(chainId, start, end, scanned.hashes())
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffEvents(scanned, indexed)
+	logs.Info("backfill chain %s block [%d, %d] scanned %d indexed %d missing %d changed %d extra %d",
+		handle.GetChainName(), start, end, scanned.Len(), indexed.Len(), diff.Missing.Len(), diff.Changed.Len(), diff.Extra.Len())
+	if !apply {
+		return diff, nil
+	}
+	err = dao.UpdateEvents(diff.Missing.WrapperTransactions, diff.Missing.SrcTransactions,
+		diff.Missing.PolyTransactions, diff.Missing.DstTransactions, nil, nil)
+	if err != nil {
+		return diff, err
+	}
+	return diff, diff.applyChanges(eventDao)
+}
+
+// applyChanges updates only the fields reported by the diff of the changed events, so the fields updated
+// later on such as the status are kept
+func (diff *BackfillDiff) applyChanges(eventDao crosschaindao.EventDao) error {
+	events := make(map[string]interface{}, diff.Changed.Len())
+	for _, tx := range diff.Changed.WrapperTransactions {
+		events["wrapper_transactions"+tx.Hash] = tx
+	}
+	for _, tx := range diff.Changed.SrcTransactions {
+		events["src_transactions"+tx.Hash] = tx
+	}
+	for _, tx := range diff.Changed.PolyTransactions {
+		events["poly_transactions"+tx.Hash] = tx
+	}
+	for _, tx := range diff.Changed.DstTransactions {
+		events["dst_transactions"+tx.Hash] = tx
+	}
+	for _, change := range diff.Changes {
+		if err := eventDao.UpdateEventFields(events[change.Table+change.Hash], change.Hash, change.Fields); err != nil {
+			return fmt.Errorf("update %s %s: %v", change.Table, change.Hash, err)
+		}
+	}
+	return nil
+}
+
+// scanBlocks handles the blocks in [start, end] in the batches or heights the listener uses for the chain
+func (ccl *CrossChainListen) scanBlocks(start, end uint64) error {
+	if ccl.isBatchChain() {
+		_, batchLength := ccl.handle.GetBatchLength()
+		if batchLength == 0 {
+			batchLength = 1
+		}
+		for batchStart := start; batchStart <= end; batchStart += batchLength {
+			batchEnd := batchStart + batchLength - 1
+			if batchEnd > end {
+				batchEnd = end
+			}
+			if err := backfillRetry(func() error { return ccl.handleBatchBlock(batchStart, batchEnd) }); err != nil {
+				return fmt.Errorf("chain %s block [%d, %d]: %v", ccl.handle.GetChainName(), batchStart, batchEnd, err)
+			}
+		}
+		return nil
+	}
+	for height := start; height <= end; height++ {
+		if err := backfillRetry(func() error { return ccl.handleBlock(height) }); err != nil {
+			return fmt.Errorf("chain %s block %d: %v", ccl.handle.GetChainName(), height, err)
+		}
+	}
+	return nil
+}
+
+func backfillRetry(f func() error) (err error) {
+	for i := 0; i < backfillRetries; i++ {
+		if err = f(); err == nil {
+			return
+		}
+		time.Sleep(backfillRetrySlot)
+	}
+	return
+}
+
+func (events *BackfillEvents) hashes() []string {
+	hashes := make([]string, 0, events.Len())
+	for _, tx := range events.WrapperTransactions {
+		hashes = append(hashes, tx.Hash)
+	}
+	for _, tx := range events.SrcTransactions {
+		hashes = append(hashes, tx.Hash)
+	}
+	for _, tx := range events.PolyTransactions {
+		hashes = append(hashes, tx.Hash)
+	}
+	for _, tx := range events.DstTransactions {
+		hashes = append(hashes, tx.Hash)
+	}
+	return hashes
+}
+
+// DiffEvents compares the scanned events with the indexed ones by hash. Only the fields taken from the
+// chain are compared, the ones updated later on such as the status are not.
+func DiffEvents(scanned, indexed *BackfillEvents) *BackfillDiff {
+	diff := &BackfillDiff{Changes: make([]*BackfillChange, 0)}
+
+	indexedWrappers := make(map[string]*models.WrapperTransaction, len(indexed.WrapperTransactions))
+	for _, tx := range indexed.WrapperTransactions {
+		indexedWrappers[tx.Hash] = tx
+	}
+	for _, tx := range scanned.WrapperTransactions {
+		old, ok := indexedWrappers[tx.Hash]
+		if !ok {
+			diff.Missing.WrapperTransactions = append(diff.Missing.WrapperTransactions, tx)
+			continue
+		}
+		delete(indexedWrappers, tx.Hash)
+		if fields := diffWrapperTransaction(tx, old); len(fields) > 0 {
+			diff.Changed.WrapperTransactions = append(diff.Changed.WrapperTransactions, tx)
+			diff.Changes = append(diff.Changes, &BackfillChange{Table: "wrapper_transactions", Hash: tx.Hash, Fields: fields})
+		}
+	}
+	for _, tx := range indexed.WrapperTransactions {
+		if _, ok := indexedWrappers[tx.Hash]; ok {
+			diff.Extra.WrapperTransactions = append(diff.Extra.WrapperTransactions, tx)
+		}
+	}
+
+	indexedSrcs := make(map[string]*models.SrcTransaction, len(indexed.SrcTransactions))
+	for _, tx := range indexed.SrcTransactions {
+		indexedSrcs[tx.Hash] = tx
+	}
+	for _, tx := range scanned.SrcTransactions {
+		old, ok := indexedSrcs[tx.Hash]
+		if !ok {
+			diff.Missing.SrcTransactions = append(diff.Missing.SrcTransactions, tx)
+			continue
+		}
+		delete(indexedSrcs, tx.Hash)
+		if fields := diffSrcTransaction(tx, old); len(fields) > 0 {
+			diff.Changed.SrcTransactions = append(diff.Changed.SrcTransactions, tx)
+			diff.Changes = append(diff.Changes, &BackfillChange{Table: "src_transactions", Hash: tx.Hash, Fields: fields})
+		}
+	}
+	for _, tx := range indexed.SrcTransactions {
+		if _, ok := indexedSrcs[tx.Hash]; ok {
+			diff.Extra.SrcTransactions = append(diff.Extra.SrcTransactions, tx)
+		}
+	}
+
+	indexedPolys := make(map[string]*models.PolyTransaction, len(indexed.PolyTransactions))
+	for _, tx := range indexed.PolyTransactions {
+		indexedPolys[tx.Hash] = tx
+	}
+	for _, tx := range scanned.PolyTransactions {
+		old, ok := indexedPolys[tx.Hash]
+		if !ok {
+			diff.Missing.PolyTransactions = append(diff.Missing.PolyTransactions, tx)
+			continue
+		}
+		delete(indexedPolys, tx.Hash)
+		if fields := diffPolyTransaction(tx, old); len(fields) > 0 {
+			diff.Changed.PolyTransactions = append(diff.Changed.PolyTransactions, tx)
+			diff.Changes = append(diff.Changes, &BackfillChange{Table: "poly_transactions", Hash: tx.Hash, Fields: fields})
+		}
+	}
+	for _, tx := range indexed.PolyTransactions {
+		if _, ok := indexedPolys[tx.Hash]; ok {
+			diff.Extra.PolyTransactions = append(diff.Extra.PolyTransactions, tx)
+		}
+	}
+
+	indexedDsts := make(map[string]*models.DstTransaction, len(indexed.DstTransactions))
+	for _, tx := range indexed.DstTransactions {
+		indexedDsts[tx.Hash] = tx
+	}
+	for _, tx := range scanned.DstTransactions {
+		old, ok := indexedDsts[tx.Hash]
+		if !ok {
+			diff.Missing.DstTransactions = append(diff.Missing.DstTransactions, tx)
+			continue
+		}
+		delete(indexedDsts, tx.Hash)
+		if fields := diffDstTransaction(tx, old); len(fields) > 0 {
+			diff.Changed.DstTransactions = append(diff.Changed.DstTransactions, tx)
+			diff.Changes = append(diff.Changes, &BackfillChange{Table: "dst_transactions", Hash: tx.Hash, Fields: fields})
+		}
+	}
+	for _, tx := range indexed.DstTransactions {
+		if _, ok := indexedDsts[tx.Hash]; ok {
+			diff.Extra.DstTransactions = append(diff.Extra.DstTransactions, tx)
+		}
+	}
+	return diff
+}
+
+type fieldDiff []string
+
+func (fields *fieldDiff) check(name string, equal bool) {
+	if !equal {
+		*fields = append(*fields, name)
+	}
+}
+
+func equalBigInt(a, b *models.BigInt) bool {
+	if a == nil || b == nil {
+		return (a == nil || a.Sign() == 0) && (b == nil || b.Sign() == 0)
+	}
+	return a.Cmp(&b.Int) == 0
+}
+
+func diffWrapperTransaction(tx, old *models.WrapperTransaction) []string {
+	fields := fieldDiff{}
+	fields.check("user", tx.User == old.User)
+	fields.check("src_chain_id", tx.SrcChainId == old.SrcChainId)
+	fields.check("standard", tx.Standard == old.Standard)
+	fields.check("block_height", tx.BlockHeight == old.BlockHeight)
+	fields.check("time", tx.Time == old.Time)
+	fields.check("dst_chain_id", tx.DstChainId == old.DstChainId)
+	fields.check("dst_user", tx.DstUser == old.DstUser)
+	fields.check("fee_token_hash", tx.FeeTokenHash == old.FeeTokenHash)
+	fields.check("fee_amount", equalBigInt(tx.FeeAmount, old.FeeAmount))
+	return fields
+}
+
+func diffSrcTransaction(tx, old *models.SrcTransaction) []string {
+	fields := fieldDiff{}
+	fields.check("chain_id", tx.ChainId == old.ChainId)
+	fields.check("standard", tx.Standard == old.Standard)
+	fields.check("time", tx.Time == old.Time)
+	fields.check("fee", equalBigInt(tx.Fee, old.Fee))
+	fields.check("height", tx.Height == old.Height)
+	fields.check("user", tx.User == old.User)
+	fields.check("dst_chain_id", tx.DstChainId == old.DstChainId)
+	fields.check("contract", tx.Contract == old.Contract)
+	fields.check("key", tx.Key == old.Key)
+	fields.check("param", tx.Param == old.Param)
+	if tx.SrcTransfer == nil || old.SrcTransfer == nil {
+		fields.check("src_transfer", tx.SrcTransfer == nil && old.SrcTransfer == nil)
+		return fields
+	}
+	fields.check("src_transfer.asset", tx.SrcTransfer.Asset == old.SrcTransfer.Asset)
+	fields.check("src_transfer.from", tx.SrcTransfer.From == old.SrcTransfer.From)
+	fields.check("src_transfer.to", tx.SrcTransfer.To == old.SrcTransfer.To)
+	fields.check("src_transfer.amount", equalBigInt(tx.SrcTransfer.Amount, old.SrcTransfer.Amount))
+	fields.check("src_transfer.dst_chain_id", tx.SrcTransfer.DstChainId == old.SrcTransfer.DstChainId)
+	fields.check("src_transfer.dst_asset", tx.SrcTransfer.DstAsset == old.SrcTransfer.DstAsset)
+	fields.check("src_transfer.dst_user", tx.SrcTransfer.DstUser == old.SrcTransfer.DstUser)
+	return fields
+}
+
+func diffPolyTransaction(tx, old *models.PolyTransaction) []string {
+	fields := fieldDiff{}
+	fields.check("chain_id", tx.ChainId == old.ChainId)
+	fields.check("time", tx.Time == old.Time)
+	fields.check("fee", equalBigInt(tx.Fee, old.Fee))
+	fields.check("height", tx.Height == old.Height)
+	fields.check("src_chain_id", tx.SrcChainId == old.SrcChainId)
+	fields.check("src_hash", tx.SrcHash == old.SrcHash)
+	fields.check("dst_chain_id", tx.DstChainId == old.DstChainId)
+	fields.check("key", tx.Key == old.Key)
+	fields.check("dst_sequence", tx.DstSequence == old.DstSequence)
+	return fields
+}
+
+func diffDstTransaction(tx, old *models.DstTransaction) []string {
+	fields := fieldDiff{}
+	fields.check("chain_id", tx.ChainId == old.ChainId)
+	fields.check("standard", tx.Standard == old.Standard)
+	fields.check("time", tx.Time == old.Time)
+	fields.check("fee", equalBigInt(tx.Fee, old.Fee))
+	fields.check("height", tx.Height == old.Height)
+	fields.check("src_chain_id", tx.SrcChainId == old.SrcChainId)
+	fields.check("contract", tx.Contract == old.Contract)
+	fields.check("poly_hash", tx.PolyHash == old.PolyHash)
+	fields.check("sequence", tx.Sequence == old.Sequence)
+	if tx.DstTransfer == nil || old.DstTransfer == nil {
+		fields.check("dst_transfer", tx.DstTransfer == nil && old.DstTransfer == nil)
+		return fields
+	}
+	fields.check("dst_transfer.asset", tx.DstTransfer.Asset == old.DstTransfer.Asset)
+	fields.check("dst_transfer.from", tx.DstTransfer.From == old.DstTransfer.From)
+	fields.check("dst_transfer.to", tx.DstTransfer.To == old.DstTransfer.To)
+	fields.check("dst_transfer.amount", equalBigInt(tx.DstTransfer.Amount, old.DstTransfer.Amount))
+	return fields
+}
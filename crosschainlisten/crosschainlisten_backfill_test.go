@@ -0,0 +1,50 @@
+package crosschainlisten
+
+import (
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/models"
+	"testing"
+)
+
+func TestDiffEvents(t *testing.T) {
+	scanned := &BackfillEvents{
+		WrapperTransactions: []*models.WrapperTransaction{
+			{Hash: "a", BlockHeight: 10, FeeAmount: models.NewBigIntFromInt(5)},
+			{Hash: "b", BlockHeight: 11, FeeAmount: models.NewBigIntFromInt(5)},
+		},
+		SrcTransactions: []*models.SrcTransaction{
+			{Hash: "a", Height: 10, Fee: models.NewBigIntFromInt(1), SrcTransfer: &models.SrcTransfer{TxHash: "a", Amount: models.NewBigIntFromInt(100)}},
+		},
+		DstTransactions: []*models.DstTransaction{
+			{Hash: "d", Height: 12, PolyHash: "p"},
+		},
+	}
+	indexed := &BackfillEvents{
+		WrapperTransactions: []*models.WrapperTransaction{
+			// status and paid gas are updated after indexing, they are not compared
+			{Id: 1, Hash: "a", BlockHeight: 10, FeeAmount: models.NewBigIntFromInt(5), Status: 3, IsPaid: true},
+			{Id: 2, Hash: "c", BlockHeight: 12},
+		},
+		SrcTransactions: []*models.SrcTransaction{
+			{Id: 1, Hash: "a", Height: 10, Fee: models.NewBigIntFromInt(1), State: 1, SrcTransfer: &models.SrcTransfer{TxHash: "a", Amount: models.NewBigIntFromInt(99)}},
+		},
+		DstTransactions: []*models.DstTransaction{
+			{Id: 1, Hash: "d", Height: 13, PolyHash: "q"},
+		},
+	}
+	diff := DiffEvents(scanned, indexed)
+	assert.False(t, diff.Empty())
+	assert.Len(t, diff.Missing.WrapperTransactions, 1)
+	assert.Equal(t, "b", diff.Missing.WrapperTransactions[0].Hash)
+	assert.Len(t, diff.Extra.WrapperTransactions, 1)
+	assert.Equal(t, "c", diff.Extra.WrapperTransactions[0].Hash)
+	assert.Len(t, diff.Changed.WrapperTransactions, 0)
+	assert.Len(t, diff.Changed.SrcTransactions, 1)
+	assert.Len(t, diff.Changed.DstTransactions, 1)
+	assert.Equal(t, []*BackfillChange{
+		{Table: "src_transactions", Hash: "a", Fields: []string{"src_transfer.amount"}},
+		{Table: "dst_transactions", Hash: "d", Fields: []string{"height", "poly_hash"}},
+	}, diff.Changes)
+
+	assert.True(t, DiffEvents(scanned, scanned).Empty())
+}
@@ -0,0 +1,176 @@
+package crosschainlisten
+
+import (
+	"poly-bridge/basedef"
+	"poly-bridge/crosschaindao"
+	"poly-bridge/models"
+	"runtime/debug"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+const (
+	deadLetterRetrySlot     = time.Minute
+	deadLetterRetryBase     = time.Minute
+	deadLetterRetryMax      = 6 * time.Hour
+	deadLetterRetryLimit    = 10
+	deadLetterErrorMaxBytes = 1024
+)
+
+type blockRangeResult struct {
+	start uint64
+	end   uint64
+	err   error
+}
+
+func (ccl *CrossChainListen) deadLetterHandles() (crosschaindao.DeadLetterDao, uint64, bool) {
+	if ccl.config.Backup {
+		return nil, 0, false
+	}
+	cfg := ccl.config.GetChainListenConfig(ccl.handle.GetChainId())
+	if cfg == nil || cfg.DeadLetterAttempts == 0 {
+		return nil, 0, false
+	}
+	dao, ok := ccl.db.(crosschaindao.DeadLetterDao)
+	if !ok {
+		return nil, 0, false
+	}
+	return dao, cfg.DeadLetterAttempts, true
+}
+
+// deadLetter counts the failed rounds of the block ranges. Once all of them failed DeadLetterAttempts
+// rounds in a row, they are saved to be retried later and true is returned, so that the cursor can
+// advance past them instead of stalling the chain.
+func (ccl *CrossChainListen) deadLetter(failed []*blockRangeResult) bool {
+	dao, attempts, ok := ccl.deadLetterHandles()
+	if !ok {
+		return false
+	}
+	if ccl.failures == nil {
+		ccl.failures = make(map[uint64]uint64)
+	}
+	poisoned := true
+	for _, result := range failed {
+		ccl.failures[result.start]++
+		if ccl.failures[result.start] < attempts {
+			poisoned = false
+		}
+	}
+	if !poisoned {
+		return false
+	}
+	now := time.Now().Unix()
+	blocks := make([]*models.DeadLetterBlock, 0, len(failed))
+	for _, result := range failed {
+		blocks = append(blocks, &models.DeadLetterBlock{
+			ChainId:       ccl.handle.GetChainId(),
+			StartHeight:   result.start,
+			EndHeight:     result.end,
+			Error:         truncateError(result.err),
+			NextRetryTime: now + int64(deadLetterBackoff(0)/time.Second),
+			Status:        basedef.DEAD_LETTER_PENDING,
+			CreateTime:    now,
+			UpdateTime:    now,
+		})
+	}
+	if err := dao.SaveDeadLetterBlocks(blocks); err != nil {
+		logs.Error("chain %s save dead letter blocks err: %v", ccl.handle.GetChainName(), err)
+		return false
+	}
+	for _, result := range failed {
+		logs.Error("chain %s block [%d, %d] failed %d rounds, dead-lettered, err: %v",
+			ccl.handle.GetChainName(), result.start, result.end, ccl.failures[result.start], result.err)
+		delete(ccl.failures, result.start)
+	}
+	return true
+}
+
+func (ccl *CrossChainListen) resetDeadLetterFailures() {
+	if len(ccl.failures) > 0 {
+		ccl.failures = make(map[uint64]uint64)
+	}
+}
+
+// RetryDeadLetters handles the dead-lettered block ranges of the chain again, with exponential backoff
+// between the attempts, until they succeed or are acknowledged.
+func (ccl *CrossChainListen) RetryDeadLetters() {
+	for {
+		ccl.retryDeadLetters()
+		time.Sleep(time.Second * 5)
+	}
+}
+
+func (ccl *CrossChainListen) retryDeadLetters() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("%s retryDeadLetters restart, recover info: %s", ccl.handle.GetChainName(), string(debug.Stack()))
+		}
+	}()
+	ticker := time.NewTicker(deadLetterRetrySlot)
+	defer ticker.Stop()
+	for range ticker.C {
+		dao, _, ok := ccl.deadLetterHandles()
+		if !ok {
+			return
+		}
+		blocks, err := dao.GetDueDeadLetterBlocks(ccl.handle.GetChainId(), time.Now().Unix(), deadLetterRetryLimit)
+		if err != nil {
+			logs.Error("chain %s get dead letter blocks err: %v", ccl.handle.GetChainName(), err)
+			continue
+		}
+		for _, block := range blocks {
+			ccl.retryDeadLetter(dao, block)
+		}
+	}
+}
+
+func (ccl *CrossChainListen) retryDeadLetter(dao crosschaindao.DeadLetterDao, block *models.DeadLetterBlock) {
+	var err error
+	if ccl.isBatchChain() {
+		err = ccl.handleBatchBlock(block.StartHeight, block.EndHeight)
+	} else {
+		for height := block.StartHeight; height <= block.EndHeight && err == nil; height++ {
+			err = ccl.handleBlock(height)
+		}
+	}
+	now := time.Now().Unix()
+	block.Attempts++
+	block.UpdateTime = now
+	if err == nil {
+		block.Status = basedef.DEAD_LETTER_RESOLVED
+		logs.Info("chain %s dead letter block [%d, %d] resolved after %d attempts",
+			ccl.handle.GetChainName(), block.StartHeight, block.EndHeight, block.Attempts)
+	} else {
+		block.Error = truncateError(err)
+		block.NextRetryTime = now + int64(deadLetterBackoff(block.Attempts)/time.Second)
+		logs.Error("chain %s dead letter block [%d, %d] attempt %d err: %v",
+			ccl.handle.GetChainName(), block.StartHeight, block.EndHeight, block.Attempts, err)
+	}
+	if err := dao.UpdateDeadLetterBlock(block); err != nil {
+		logs.Error("chain %s update dead letter block %d err: %v", ccl.handle.GetChainName(), block.Id, err)
+	}
+}
+
+// deadLetterBackoff doubles the retry delay on every attempt, up to deadLetterRetryMax
+func deadLetterBackoff(attempts uint64) time.Duration {
+	if attempts >= 16 {
+		return deadLetterRetryMax
+	}
+	backoff := deadLetterRetryBase << attempts
+	if backoff > deadLetterRetryMax {
+		return deadLetterRetryMax
+	}
+	return backoff
+}
+
+func truncateError(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	if len(msg) > deadLetterErrorMaxBytes {
+		msg = msg[:deadLetterErrorMaxBytes]
+	}
+	return msg
+}
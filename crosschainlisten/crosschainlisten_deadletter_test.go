@@ -0,0 +1,84 @@
+package crosschainlisten
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/conf"
+	"poly-bridge/crosschaindao"
+	"poly-bridge/models"
+	"testing"
+	"time"
+)
+
+type testDeadLetterHandle struct {
+	ChainHandle
+}
+
+func (h *testDeadLetterHandle) GetChainId() uint64 {
+	return 2
+}
+
+func (h *testDeadLetterHandle) GetChainName() string {
+	return "test"
+}
+
+type testDeadLetterDao struct {
+	crosschaindao.CrossChainDao
+	blocks map[uint64]*models.DeadLetterBlock
+}
+
+func (dao *testDeadLetterDao) SaveDeadLetterBlocks(blocks []*models.DeadLetterBlock) error {
+	for _, block := range blocks {
+		dao.blocks[block.StartHeight] = block
+	}
+	return nil
+}
+
+func (dao *testDeadLetterDao) UpdateDeadLetterBlock(block *models.DeadLetterBlock) error {
+	dao.blocks[block.StartHeight] = block
+	return nil
+}
+
+func (dao *testDeadLetterDao) GetDeadLetterBlock(id int64) (*models.DeadLetterBlock, error) {
+	return nil, fmt.Errorf("not found")
+}
+
+func (dao *testDeadLetterDao) GetDeadLetterBlocks(status int) ([]*models.DeadLetterBlock, error) {
+	return nil, nil
+}
+
+func (dao *testDeadLetterDao) GetDueDeadLetterBlocks(chainId uint64, now int64, limit int) ([]*models.DeadLetterBlock, error) {
+	return nil, nil
+}
+
+func TestDeadLetter(t *testing.T) {
+	dao := &testDeadLetterDao{blocks: make(map[uint64]*models.DeadLetterBlock)}
+	config := &conf.Config{ChainListenConfig: []*conf.ChainListenConfig{{ChainId: 2, DeadLetterAttempts: 3}}}
+	ccl := NewCrossChainListen(&testDeadLetterHandle{}, dao, config)
+
+	failed := []*blockRangeResult{{start: 101, end: 110, err: fmt.Errorf("poison block")}}
+	assert.False(t, ccl.deadLetter(failed))
+	assert.False(t, ccl.deadLetter(failed))
+	assert.True(t, ccl.deadLetter(failed))
+	assert.Len(t, dao.blocks, 1)
+	assert.Equal(t, uint64(110), dao.blocks[101].EndHeight)
+	assert.Equal(t, "poison block", dao.blocks[101].Error)
+	assert.Len(t, ccl.failures, 0)
+
+	// a successful round resets the count
+	assert.False(t, ccl.deadLetter(failed))
+	ccl.resetDeadLetterFailures()
+	assert.False(t, ccl.deadLetter(failed))
+	assert.False(t, ccl.deadLetter(failed))
+
+	// disabled
+	config.ChainListenConfig[0].DeadLetterAttempts = 0
+	assert.False(t, ccl.deadLetter(failed))
+}
+
+func TestDeadLetterBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, deadLetterBackoff(0))
+	assert.Equal(t, 4*time.Minute, deadLetterBackoff(2))
+	assert.Equal(t, deadLetterRetryMax, deadLetterBackoff(10))
+	assert.Equal(t, deadLetterRetryMax, deadLetterBackoff(100))
+}
@@ -0,0 +1,127 @@
+package crosschainlisten
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beego/beego/v2/server/web/context"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/crosschaindao/bridgedao"
+	"poly-bridge/crosschaineffect/bridgeeffect"
+	"poly-bridge/dbconn"
+	bridgehttp "poly-bridge/http"
+	"poly-bridge/models"
+)
+
+// testEventHandle returns the events of a block of the chain
+type testEventHandle struct {
+	ChainHandle
+	chainId             uint64
+	wrapperTransactions []*models.WrapperTransaction
+	srcTransactions     []*models.SrcTransaction
+	polyTransactions    []*models.PolyTransaction
+	dstTransactions     []*models.DstTransaction
+}
+
+func (h *testEventHandle) GetChainId() uint64 {
+	return h.chainId
+}
+
+func (h *testEventHandle) GetChainName() string {
+	return "test"
+}
+
+func (h *testEventHandle) HandleNewBlock(height uint64) ([]*models.WrapperTransaction, []*models.SrcTransaction, []*models.PolyTransaction, []*models.DstTransaction, []*models.WrapperDetail, []*models.PolyDetail, int, int, error) {
+	return h.wrapperTransactions, h.srcTransactions, h.polyTransactions, h.dstTransactions, nil, nil, 0, 0, nil
+}
+
+// the events of a transfer from ethereum to bsc are listened into sqlite, the effect finishes it and the http
+// server returns it
+func TestCrossChainListenEffectSqlite(t *testing.T) {
+	dbCfg := &conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "crosschainlisten_e2e"}
+	// the listeners run as backups without the alarms which need redis
+	config := &conf.Config{Backup: true, DBConfig: dbCfg, RelayUrl: "http://localhost:30330"}
+	conf.GlobalConfig = config
+	dao := bridgedao.NewBridgeDao(dbCfg, false)
+	chains := []*models.Chain{
+		{ChainId: basedef.POLY_CROSSCHAIN_ID, Name: "Poly", Height: 1641500},
+		{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "Ethereum", Height: 9329400, BackwardBlockNumber: 12},
+		{ChainId: basedef.BSC_CROSSCHAIN_ID, Name: "BSC", Height: 6000001},
+	}
+	assert.NoError(t, dao.AddChains(chains, nil))
+
+	srcHash := "336cd94f1ec80280c684606b8c9358f1ad0e9e7e7ce69f0da35c21a66fa0c729"
+	polyHash := "d2e8e325265ed314d9f538c2cb3f8e0a71ca2adad8b31db98278a4af6aecc1df"
+	dstHash := "6a8b8c4e2f1d3a5b7c9e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e"
+	user := "8bc7e7304120b88d111431f6a4853589d10e8132"
+	handles := []*testEventHandle{
+		{
+			chainId: basedef.ETHEREUM_CROSSCHAIN_ID,
+			wrapperTransactions: []*models.WrapperTransaction{{
+				Hash: srcHash, User: user, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, BlockHeight: 9329385, Time: 1708885420,
+				DstChainId: basedef.BSC_CROSSCHAIN_ID, DstUser: user, FeeTokenHash: "0000000000000000000000000000000000000000",
+				FeeAmount: models.NewBigIntFromInt(0), Status: basedef.STATE_SOURCE_DONE, PaidGas: models.NewBigIntFromInt(0),
+			}},
+			srcTransactions: []*models.SrcTransaction{{
+				Hash: srcHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, State: 1, Time: 1708885420, Fee: models.NewBigIntFromInt(0),
+				Height: 9329385, User: user, DstChainId: basedef.BSC_CROSSCHAIN_ID, Key: "0000000000000000000000000000000000000000000000000000000000000abe",
+				SrcTransfer: &models.SrcTransfer{
+					ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: 1708885420, Asset: "0000000000000000000000000000000000000000",
+					From: user, Amount: models.NewBigIntFromInt(9000000000000), DstChainId: basedef.BSC_CROSSCHAIN_ID, DstUser: user,
+				},
+			}},
+		},
+		{
+			chainId: basedef.POLY_CROSSCHAIN_ID,
+			polyTransactions: []*models.PolyTransaction{{
+				Hash: polyHash, State: 1, Time: 1708885450, Fee: models.NewBigIntFromInt(0), Height: 1641497,
+				SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcHash: srcHash, DstChainId: basedef.BSC_CROSSCHAIN_ID,
+			}},
+		},
+		{
+			chainId: basedef.BSC_CROSSCHAIN_ID,
+			dstTransactions: []*models.DstTransaction{{
+				Hash: dstHash, ChainId: basedef.BSC_CROSSCHAIN_ID, State: 1, Time: 1708885480, Fee: models.NewBigIntFromInt(0),
+				Height: 6000000, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, PolyHash: polyHash,
+				DstTransfer: &models.DstTransfer{
+					ChainId: basedef.BSC_CROSSCHAIN_ID, Time: 1708885480, Asset: "0000000000000000000000000000000000000000",
+					To: user, Amount: models.NewBigIntFromInt(9000000000000),
+				},
+			}},
+		},
+	}
+	for _, handle := range handles {
+		ccl := NewCrossChainListen(handle, dao, config)
+		ccl.handleBlock(1)
+	}
+
+	bridgehttp.Init()
+	transactionsOfState := func(state uint64) *models.WrapperTransactionsRsp {
+		request, _ := json.Marshal(&models.TransactionsOfStateReq{State: state, PageSize: 10})
+		w := httptest.NewRecorder()
+		ctx := context.NewContext()
+		ctx.Reset(w, httptest.NewRequest(http.MethodPost, "/transactionsofstate/", nil))
+		ctx.Input.RequestBody = request
+		c := &bridgehttp.TransactionController{}
+		c.Init(ctx, "TransactionController", "TransactionsOfState", c)
+		c.TransactionsOfState()
+		assert.Equal(t, http.StatusOK, w.Code)
+		rsp := new(models.WrapperTransactionsRsp)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rsp))
+		return rsp
+	}
+	assert.Len(t, transactionsOfState(basedef.STATE_FINISHED).Transactions, 0)
+
+	effect := bridgeeffect.NewBridgeEffect(&conf.EventEffectConfig{}, dbCfg, &conf.RedisConfig{DialTimeout: 1, ReadTimeout: 1, WriteTimeout: 1})
+	assert.NoError(t, effect.Effect())
+
+	rsp := transactionsOfState(basedef.STATE_FINISHED)
+	if assert.Len(t, rsp.Transactions, 1) {
+		assert.Equal(t, srcHash, rsp.Transactions[0].Hash)
+		assert.Equal(t, uint64(basedef.STATE_FINISHED), rsp.Transactions[0].State)
+	}
+}
@@ -0,0 +1,46 @@
+package crosschainlisten
+
+import (
+	"time"
+)
+
+// NewHeadHandle is implemented by the chain handles which can be notified of new blocks,
+// the listener then handles them without waiting for the next ListenSlot tick.
+type NewHeadHandle interface {
+	NewHeads() <-chan uint64
+}
+
+// newListenTicker fires every ListenSlot, and also on new heads when the handle subscribes them.
+// Ticks are coalesced while the listener is busy, so a fast chain does not queue them up.
+func (ccl *CrossChainListen) newListenTicker() (<-chan time.Time, func()) {
+	ticker := time.NewTicker(time.Second * time.Duration(ccl.handle.GetChainListenSlot()))
+	var heads <-chan uint64
+	if handle, ok := ccl.handle.(NewHeadHandle); ok && !ccl.config.Backup {
+		heads = handle.NewHeads()
+	}
+	if heads == nil {
+		return ticker.C, ticker.Stop
+	}
+	ch := make(chan time.Time, 1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			var t time.Time
+			select {
+			case t = <-ticker.C:
+			case <-heads:
+				t = time.Now()
+			case <-done:
+				return
+			}
+			select {
+			case ch <- t:
+			default:
+			}
+		}
+	}()
+	return ch, func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,51 @@
+package crosschainlisten
+
+import (
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/conf"
+	"testing"
+	"time"
+)
+
+type testNewHeadHandle struct {
+	ChainHandle
+	heads chan uint64
+}
+
+func (h *testNewHeadHandle) GetChainListenSlot() uint64 {
+	return 3600
+}
+
+func (h *testNewHeadHandle) NewHeads() <-chan uint64 {
+	return h.heads
+}
+
+func TestNewListenTicker(t *testing.T) {
+	handle := &testNewHeadHandle{heads: make(chan uint64)}
+	ccl := NewCrossChainListen(handle, nil, &conf.Config{})
+	ticker, stop := ccl.newListenTicker()
+	defer stop()
+
+	handle.heads <- 100
+	handle.heads <- 101
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-ticker:
+	case <-time.After(time.Second):
+		t.Fatal("ticker is not fired by new heads")
+	}
+	// the second head is coalesced into the first tick
+	select {
+	case <-ticker:
+		t.Fatal("ticks are not coalesced")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	handle.heads <- 102
+	select {
+	case <-ticker:
+	case <-time.After(time.Second):
+		t.Fatal("ticker is not fired by new heads")
+	}
+	assert.Len(t, ticker, 0)
+}
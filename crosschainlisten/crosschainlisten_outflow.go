@@ -0,0 +1,199 @@
+package crosschainlisten
+
+import (
+	"fmt"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/common"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/utils/decimal"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	outflowWindow         = 3600
+	outflowBucket         = 60
+	outflowBaselineBucket = 3600
+	outflowCountedKeep    = 24 * time.Hour
+)
+
+// outflowDimension is a sliding window of USD outflow, of a token, a route of a token or a destination chain.
+// The windows of a token and of its routes pause the token, so the other tokens of the route keep going.
+type outflowDimension struct {
+	key    string
+	title  string
+	limit  int64
+	scope  string
+	target string
+}
+
+func outflowDimensions(cfg *conf.OutflowLimitConfig, token string, srcChainId, dstChainId uint64) []*outflowDimension {
+	tokenLimit, ok := cfg.TokenLimits[token]
+	if !ok {
+		tokenLimit = cfg.DefaultTokenLimit
+	}
+	return []*outflowDimension{
+		{key: "token_" + token, title: token, limit: tokenLimit,
+			scope: basedef.CIRCUIT_TOKEN, target: token},
+		{key: fmt.Sprintf("route_%s_%d_%d", token, srcChainId, dstChainId), title: fmt.Sprintf("%s (%d->%d)", token, srcChainId, dstChainId), limit: tokenLimit,
+			scope: basedef.CIRCUIT_TOKEN, target: token},
+		{key: fmt.Sprintf("chain_%d", dstChainId), title: fmt.Sprintf("to chain %d", dstChainId), limit: cfg.ChainLimits[dstChainId],
+			scope: basedef.CIRCUIT_CHAIN, target: fmt.Sprint(dstChainId)},
+	}
+}
+
+func outflowBucketKey(dimension string, bucket, index int64) string {
+	return fmt.Sprintf("%s%s_%d_%d", cacheRedis.OutflowBucketPrefix, dimension, bucket, index)
+}
+
+func outflowBucketKeys(dimension string, bucket, start, end int64) []string {
+	keys := make([]string, 0, end-start+1)
+	for index := start; index <= end; index++ {
+		keys = append(keys, outflowBucketKey(dimension, bucket, index))
+	}
+	return keys
+}
+
+// outflowViolation tells why the outflow of a window is anomalous, it is empty when the outflow is fine.
+// overLimit is set when the limit is reached, not only the baseline.
+func outflowViolation(cfg *conf.OutflowLimitConfig, amount float64, limit int64, baseline float64) (reason string, overLimit bool) {
+	if limit > 0 && amount >= float64(limit) {
+		return fmt.Sprintf("%.2f USD reaches the limit of %d USD", amount, limit), true
+	}
+	if cfg.BaselineDays <= 0 || cfg.DeviationRate <= 0 || amount < float64(cfg.DeviationMinAmount) {
+		return "", false
+	}
+	if baseline <= 0 {
+		return fmt.Sprintf("%.2f USD without any outflow in the last %d days", amount, cfg.BaselineDays), false
+	}
+	if amount >= baseline*cfg.DeviationRate {
+		return fmt.Sprintf("%.2f USD is %.1f times the baseline of %.2f USD", amount, amount/baseline, baseline), false
+	}
+	return "", false
+}
+
+// checkOutflow adds the USD amounts of the transfers to the sliding windows of their tokens, routes and
+// destination chains in redis, which is shared by all the servers, and alarms the anomalous windows once a window.
+func (ccl *CrossChainListen) checkOutflow(srcTransactions []*models.SrcTransaction) {
+	cfg := ccl.config.OutflowLimitConfig
+	if cfg == nil || len(srcTransactions) == 0 {
+		return
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = outflowWindow
+	}
+	dimensions := make(map[string]*outflowDimension)
+	for _, v := range srcTransactions {
+		if v.SrcTransfer == nil || v.SrcTransfer.Amount == nil {
+			continue
+		}
+		token, err := ccl.db.GetTokenBasicByHash(v.SrcTransfer.ChainId, v.SrcTransfer.Asset)
+		if err != nil || token.TokenBasic == nil {
+			continue
+		}
+		counted, err := cacheRedis.Redis.Lock(cacheRedis.OutflowCountedPrefix+strings.ToLower(v.Hash), "done", outflowCountedKeep)
+		if err != nil || !counted {
+			continue
+		}
+		amount, _ := decimal.NewFromBigInt(&v.SrcTransfer.Amount.Int, 0).
+			Div(decimal.NewFromInt(basedef.Int64FromFigure(int(token.Precision)))).
+			Mul(decimal.NewFromInt(token.TokenBasic.Price)).
+			Div(decimal.NewFromInt(100000000)).Float64()
+		dstChainId := v.SrcTransfer.DstChainId
+		if dstChainId == 0 {
+			dstChainId = v.DstChainId
+		}
+		for _, dimension := range outflowDimensions(cfg, token.TokenBasicName, v.ChainId, dstChainId) {
+			ccl.addOutflow(cfg, dimension.key, window, int64(v.Time), amount)
+			dimensions[dimension.key] = dimension
+		}
+	}
+	for _, dimension := range dimensions {
+		ccl.checkOutflowWindow(cfg, dimension, window)
+	}
+}
+
+func (ccl *CrossChainListen) addOutflow(cfg *conf.OutflowLimitConfig, dimension string, window, txTime int64, amount float64) {
+	err := cacheRedis.Redis.IncrByFloat(outflowBucketKey(dimension, outflowBucket, txTime/outflowBucket), amount,
+		time.Duration(window+outflowBucket)*time.Second)
+	if err != nil {
+		logs.Error("add outflow of %s err: %v", dimension, err)
+	}
+	if cfg.BaselineDays <= 0 {
+		return
+	}
+	err = cacheRedis.Redis.IncrByFloat(outflowBucketKey(dimension, outflowBaselineBucket, txTime/outflowBaselineBucket), amount,
+		time.Duration(cfg.BaselineDays*86400+outflowBaselineBucket)*time.Second)
+	if err != nil {
+		logs.Error("add outflow baseline of %s err: %v", dimension, err)
+	}
+}
+
+func (ccl *CrossChainListen) checkOutflowWindow(cfg *conf.OutflowLimitConfig, dimension *outflowDimension, window int64) {
+	now := time.Now().Unix()
+	amount, err := cacheRedis.Redis.SumFloat(outflowBucketKeys(dimension.key, outflowBucket, (now-window)/outflowBucket+1, now/outflowBucket)...)
+	if err != nil {
+		return
+	}
+	baseline := float64(0)
+	if cfg.BaselineDays > 0 {
+		current := now / outflowBaselineBucket
+		sum, err := cacheRedis.Redis.SumFloat(outflowBucketKeys(dimension.key, outflowBaselineBucket, current-cfg.BaselineDays*24, current-1)...)
+		if err != nil {
+			return
+		}
+		baseline = sum * float64(window) / float64(cfg.BaselineDays*86400)
+	}
+	reason, overLimit := outflowViolation(cfg, amount, dimension.limit, baseline)
+	if reason == "" {
+		return
+	}
+	// the servers sharing the window pause and alarm it once
+	alarmed, err := cacheRedis.Redis.Lock(cacheRedis.OutflowAlarmPrefix+dimension.key, "done", time.Duration(window)*time.Second)
+	if err != nil || !alarmed {
+		return
+	}
+	if overLimit && ccl.config.CircuitBreakerConfig != nil && ccl.config.CircuitBreakerConfig.AutoPause {
+		_, err = cacheRedis.Redis.PauseCircuit(&basedef.CircuitBreak{
+			Scope:    dimension.scope,
+			Target:   dimension.target,
+			Reason:   fmt.Sprintf("outflow of %s in %d seconds: %s", dimension.title, window, reason),
+			Operator: "outflow",
+			Auto:     true,
+			Time:     now,
+		})
+		if err != nil {
+			logs.Error("pause %s on outflow of %s err: %v", basedef.CircuitKey(dimension.scope, dimension.target), dimension.title, err)
+		}
+	}
+	logs.Error("anomalous outflow of %s in %d seconds: %s", dimension.title, window, reason)
+	if err := sendOutflowAlarm(dimension, window, reason); err != nil {
+		logs.Error("send outflow alarm of %s failed, err: %v", dimension.title, err)
+	}
+}
+
+func sendOutflowAlarm(dimension *outflowDimension, window int64, reason string) error {
+	title := fmt.Sprintf("*Outflow Alarm!!! %s*\n", dimension.title)
+	text := fmt.Sprintf("%s\n*Window*: %s\n*Outflow*: %s\n*Time*: %s\n%s",
+		title,
+		(time.Duration(window) * time.Second).String(),
+		reason,
+		time.Now().Format("2006-01-02 15:04:05"),
+		"-----------------------------------------",
+	)
+	botConfig := conf.GlobalConfig.BotConfig
+	if botConfig == nil {
+		return fmt.Errorf("bot is not configured")
+	}
+	msg := tgbotapi.NewMessage(botConfig.LargeTxChatId, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	_, err := common.SendTgBotMessage(msg)
+	return err
+}
@@ -0,0 +1,55 @@
+package crosschainlisten
+
+import (
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"testing"
+)
+
+func TestOutflowViolation(t *testing.T) {
+	cfg := &conf.OutflowLimitConfig{BaselineDays: 7, DeviationRate: 5, DeviationMinAmount: 10000}
+	reason, overLimit := outflowViolation(cfg, 1000000, 1000000, 0)
+	assert.Contains(t, reason, "reaches the limit")
+	assert.True(t, overLimit)
+	reason, _ = outflowViolation(cfg, 999999, 1000000, 500000)
+	assert.Equal(t, "", reason)
+	// small windows are not compared with their baseline
+	reason, _ = outflowViolation(cfg, 9999, 0, 100)
+	assert.Equal(t, "", reason)
+	reason, overLimit = outflowViolation(cfg, 50000, 0, 10000)
+	assert.Contains(t, reason, "5.0 times the baseline")
+	assert.False(t, overLimit)
+	reason, _ = outflowViolation(cfg, 49999, 0, 10000)
+	assert.Equal(t, "", reason)
+	reason, _ = outflowViolation(cfg, 50000, 0, 0)
+	assert.Contains(t, reason, "without any outflow")
+
+	cfg.BaselineDays = 0
+	reason, _ = outflowViolation(cfg, 50000, 0, 0)
+	assert.Equal(t, "", reason)
+}
+
+func TestOutflowDimensions(t *testing.T) {
+	cfg := &conf.OutflowLimitConfig{
+		TokenLimits:       map[string]int64{"USDT": 5000000},
+		DefaultTokenLimit: 1000000,
+		ChainLimits:       map[uint64]int64{6: 8000000},
+	}
+	dimensions := outflowDimensions(cfg, "USDT", 2, 6)
+	assert.Len(t, dimensions, 3)
+	assert.Equal(t, "token_USDT", dimensions[0].key)
+	assert.Equal(t, int64(5000000), dimensions[0].limit)
+	assert.Equal(t, "route_USDT_2_6", dimensions[1].key)
+	assert.Equal(t, int64(5000000), dimensions[1].limit)
+	assert.Equal(t, "chain_6", dimensions[2].key)
+	// the outflow of a route pauses the token only
+	assert.Equal(t, "token_USDT", basedef.CircuitKey(dimensions[1].scope, dimensions[1].target))
+	assert.Equal(t, "chain_6", basedef.CircuitKey(dimensions[2].scope, dimensions[2].target))
+	assert.Equal(t, int64(8000000), dimensions[2].limit)
+
+	dimensions = outflowDimensions(cfg, "ETH", 2, 7)
+	assert.Equal(t, int64(1000000), dimensions[0].limit)
+	assert.Equal(t, int64(0), dimensions[2].limit)
+	assert.Equal(t, []string{"OutflowBucket_chain_7_60_10", "OutflowBucket_chain_7_60_11"}, outflowBucketKeys("chain_7", 60, 10, 11))
+}
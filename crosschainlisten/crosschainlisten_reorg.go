@@ -0,0 +1,154 @@
+package crosschainlisten
+
+import (
+	"fmt"
+	"github.com/beego/beego/v2/core/logs"
+	"poly-bridge/crosschaindao"
+	"poly-bridge/models"
+)
+
+// BlockHashHandle is implemented by the chain handles which can report block hashes,
+// it is required by the reorg detection of the listener.
+type BlockHashHandle interface {
+	GetBlockHash(height uint64) (hash string, parentHash string, err error)
+}
+
+func (ccl *CrossChainListen) reorgHandles() (BlockHashHandle, crosschaindao.ChainBlockDao, uint64, bool) {
+	if ccl.config.Backup {
+		return nil, nil, 0, false
+	}
+	cfg := ccl.config.GetChainListenConfig(ccl.handle.GetChainId())
+	if cfg == nil || cfg.ReorgDepth == 0 {
+		return nil, nil, 0, false
+	}
+	handle, ok := ccl.handle.(BlockHashHandle)
+	if !ok {
+		return nil, nil, 0, false
+	}
+	dao, ok := ccl.db.(crosschaindao.ChainBlockDao)
+	if !ok {
+		return nil, nil, 0, false
+	}
+	return handle, dao, cfg.ReorgDepth, true
+}
+
+// checkReorg compares the parent hash of the next block with the recorded hash of chain.Height.
+// When they differ, it walks back to the common ancestor, removes the events indexed from the
+// orphaned blocks and rewinds chain.Height, so that the range is indexed again from the canonical chain.
+func (ccl *CrossChainListen) checkReorg(chain *models.Chain) error {
+	handle, dao, depth, ok := ccl.reorgHandles()
+	if !ok || chain.Height == 0 {
+		return nil
+	}
+	recorded, err := dao.GetChainBlock(chain.ChainId, chain.Height)
+	if err != nil {
+		return err
+	}
+	if recorded == nil {
+		return nil
+	}
+	_, parentHash, err := handle.GetBlockHash(chain.Height + 1)
+	if err != nil {
+		return err
+	}
+	if parentHash == recorded.Hash {
+		return nil
+	}
+	logs.Warn("chain %s reorg detected at height %d, recorded hash: %s, parent hash of next block: %s",
+		ccl.handle.GetChainName(), chain.Height, recorded.Hash, parentHash)
+
+	ancestor, err := findCommonAncestor(handle, dao, chain.ChainId, chain.Height, depth)
+	if err != nil {
+		return err
+	}
+	srcHashes, polyHashes, dstHashes, err := dao.GetEventHashesByHeight(chain.ChainId, ancestor+1, chain.Height)
+	if err != nil {
+		return err
+	}
+	logs.Warn("chain %s rollback to height %d, orphaned blocks: [%d, %d], src: %d, poly: %d, dst: %d",
+		ccl.handle.GetChainName(), ancestor, ancestor+1, chain.Height, len(srcHashes), len(polyHashes), len(dstHashes))
+	if len(srcHashes) > 0 || len(polyHashes) > 0 || len(dstHashes) > 0 {
+		if err = ccl.db.RemoveEvents(srcHashes, polyHashes, dstHashes); err != nil {
+			return err
+		}
+	}
+	if err = dao.RemoveChainBlocks(chain.ChainId, ancestor+1, chain.Height); err != nil {
+		return err
+	}
+	orphanedHeight := chain.Height
+	chain.Height = ancestor
+	if err = ccl.db.UpdateChain(chain); err != nil {
+		chain.Height = orphanedHeight
+		return err
+	}
+	return nil
+}
+
+// findCommonAncestor walks back from height until the recorded hash matches the chain.
+// Heights without a record are trusted, as nothing was recorded for them to compare with.
+func findCommonAncestor(handle BlockHashHandle, dao crosschaindao.ChainBlockDao, chainId uint64, height uint64, depth uint64) (uint64, error) {
+	for h := height; h > 0 && height-h < depth; h-- {
+		recorded, err := dao.GetChainBlock(chainId, h)
+		if err != nil {
+			return 0, err
+		}
+		if recorded == nil {
+			return h, nil
+		}
+		hash, _, err := handle.GetBlockHash(h)
+		if err != nil {
+			return 0, err
+		}
+		if hash == recorded.Hash {
+			return h, nil
+		}
+	}
+	if height < depth {
+		return 0, fmt.Errorf("chain %d no common ancestor found below height %d", chainId, height)
+	}
+	logs.Error("chain %d no common ancestor found within %d blocks of height %d", chainId, depth, height)
+	return height - depth, nil
+}
+
+// fetchChainBlocks gets the hashes of the last ReorgDepth blocks in [start, end].
+// They are fetched before the blocks are handled, so a reorg happening meanwhile shows up as a mismatch later.
+func (ccl *CrossChainListen) fetchChainBlocks(start, end uint64) ([]*models.ChainBlock, error) {
+	handle, _, depth, ok := ccl.reorgHandles()
+	if !ok || end < start {
+		return nil, nil
+	}
+	if end-start+1 > depth {
+		start = end - depth + 1
+	}
+	blocks := make([]*models.ChainBlock, 0, end-start+1)
+	for h := start; h <= end; h++ {
+		hash, parentHash, err := handle.GetBlockHash(h)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, &models.ChainBlock{
+			ChainId:    ccl.handle.GetChainId(),
+			Height:     h,
+			Hash:       hash,
+			ParentHash: parentHash,
+		})
+	}
+	return blocks, nil
+}
+
+// saveChainBlocks records the fetched block hashes and drops the ones deeper than ReorgDepth
+func (ccl *CrossChainListen) saveChainBlocks(blocks []*models.ChainBlock, end uint64) {
+	_, dao, depth, ok := ccl.reorgHandles()
+	if !ok || len(blocks) == 0 {
+		return
+	}
+	if err := dao.SaveChainBlocks(blocks); err != nil {
+		logs.Error("chain %s save block hashes err: %v", ccl.handle.GetChainName(), err)
+		return
+	}
+	if end > depth {
+		if err := dao.RemoveChainBlocks(ccl.handle.GetChainId(), 0, end-depth); err != nil {
+			logs.Error("chain %s remove block hashes err: %v", ccl.handle.GetChainName(), err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package crosschainlisten
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/models"
+	"testing"
+)
+
+type testBlockHashHandle map[uint64]string
+
+func (h testBlockHashHandle) GetBlockHash(height uint64) (string, string, error) {
+	hash, ok := h[height]
+	if !ok {
+		return "", "", fmt.Errorf("block %d not found", height)
+	}
+	return hash, h[height-1], nil
+}
+
+type testChainBlockDao map[uint64]*models.ChainBlock
+
+func (dao testChainBlockDao) GetChainBlock(chainId uint64, height uint64) (*models.ChainBlock, error) {
+	return dao[height], nil
+}
+
+func (dao testChainBlockDao) SaveChainBlocks(blocks []*models.ChainBlock) error {
+	for _, block := range blocks {
+		dao[block.Height] = block
+	}
+	return nil
+}
+
+func (dao testChainBlockDao) RemoveChainBlocks(chainId uint64, start, end uint64) error {
+	for h := start; h <= end; h++ {
+		delete(dao, h)
+	}
+	return nil
+}
+
+func (dao testChainBlockDao) GetEventHashesByHeight(chainId uint64, start, end uint64) ([]string, []string, []string, error) {
+	return nil, nil, nil, nil
+}
+
+func TestFindCommonAncestor(t *testing.T) {
+	handle := testBlockHashHandle{98: "a98", 99: "a99", 100: "b100", 101: "b101", 102: "b102"}
+	dao := testChainBlockDao{}
+	dao.SaveChainBlocks([]*models.ChainBlock{
+		{Height: 99, Hash: "a99"},
+		{Height: 100, Hash: "a100"},
+		{Height: 101, Hash: "a101"},
+	})
+
+	ancestor, err := findCommonAncestor(handle, dao, 2, 101, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(99), ancestor)
+
+	// blocks without a record are trusted
+	delete(dao, 99)
+	ancestor, err = findCommonAncestor(handle, dao, 2, 101, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(99), ancestor)
+
+	// rewind the whole depth when no ancestor is found within it
+	ancestor, err = findCommonAncestor(handle, dao, 2, 101, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(99), ancestor)
+}
@@ -0,0 +1,25 @@
+package crosschainlisten
+
+import (
+	"poly-bridge/cacheRedis"
+	"poly-bridge/models"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// publishStatus pushes the new wrapper transactions to the transaction status streams of the http servers,
+// their later status changes are published by the bridge effect.
+func (ccl *CrossChainListen) publishStatus(wrapperTransactions []*models.WrapperTransaction) {
+	if cacheRedis.Redis == nil {
+		return
+	}
+	for _, v := range wrapperTransactions {
+		wrapperTransaction := *v
+		if wrapperTransaction.FeeAmount == nil {
+			wrapperTransaction.FeeAmount = models.NewBigIntFromInt(0)
+		}
+		if err := cacheRedis.Redis.PublishTransactionStatus(models.MakeWrapperTransactionRsp(&wrapperTransaction)); err != nil {
+			logs.Error("publish status %d of %s err: %v", wrapperTransaction.Status, wrapperTransaction.Hash, err)
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"poly-bridge/conf"
 	"poly-bridge/crosschaindao"
 	"poly-bridge/crosschainlisten"
+	"poly-bridge/dbconn"
 	"runtime"
 	"strings"
 	"syscall"
@@ -117,6 +118,9 @@ func startServer(ctx *cli.Context) {
 		logs.Error("startServer - read config failed!")
 		return
 	}
+	if err := dbconn.CheckSchema(config); err != nil {
+		panic(err)
+	}
 	{
 		conf, _ := json.Marshal(config)
 		logs.Info("%s\n", string(conf))
@@ -63,6 +63,7 @@ type EthereumChainListen struct {
 	eventRemoveLiquidityEventId          common.Hash
 	eventSwapEventId                     common.Hash
 	eventSwapperLockEventId              common.Hash
+	newHeads                             <-chan uint64
 }
 
 func NewEthereumChainListen(cfg *conf.ChainListenConfig) *EthereumChainListen {
@@ -72,6 +73,12 @@ func NewEthereumChainListen(cfg *conf.ChainListenConfig) *EthereumChainListen {
 	urls := cfg.GetNodesUrl()
 	sdk := chainsdk.NewEthereumSdkPro(urls, cfg.ListenSlot, cfg.ChainId)
 	ethListen.ethSdk = sdk
+	if cfg.SubscribeNewHeads {
+		ethListen.newHeads = sdk.SubscribeNewHeads()
+		if ethListen.newHeads == nil {
+			logs.Warn("chain %s has no websocket node, new heads are not subscribed", cfg.ChainName)
+		}
+	}
 	ethListen.eventPolyWrapperLockId = common.HexToHash("0x2b0591052cc6602e870d3994f0a1b173fdac98c215cb3b0baf84eaca5a0aa81e")
 	ethListen.eventNftPolyWrapperLockId = common.HexToHash("0x3a15d8cf4b167dd8963989f8038f2333a4889f74033bb53bfb767a5cced072e2")
 	ethListen.eventCrossChainEventId = common.HexToHash("0x6ad3bf15c1988bc04bc153490cab16db8efb9a3990215bf1c64ea6e28be88483")
@@ -92,6 +99,11 @@ func (this *EthereumChainListen) GetLatestHeight() (uint64, error) {
 	return this.ethSdk.GetLatestHeight()
 }
 
+// NewHeads is notified of new blocks when SubscribeNewHeads is configured, otherwise it is nil
+func (this *EthereumChainListen) NewHeads() <-chan uint64 {
+	return this.newHeads
+}
+
 func (this *EthereumChainListen) GetChainListenSlot() uint64 {
 	return this.ethCfg.ListenSlot
 }
@@ -290,6 +302,7 @@ func (this *EthereumChainListen) HandleNewBlock(height uint64) ([]*models.Wrappe
 			dstTransaction.Hash = unLockEvent.TxHash
 			dstTransaction.State = 1
 			dstTransaction.Fee = models.NewBigIntFromInt(int64(unLockEvent.Fee))
+			dstTransaction.GasUsed = unLockEvent.GasUsed
 			dstTransaction.Time = blockTimer[unLockEvent.Height]
 			dstTransaction.Height = unLockEvent.Height
 			dstTransaction.SrcChainId = uint64(unLockEvent.FChainId)
@@ -464,7 +477,7 @@ func (this *EthereumChainListen) getECCMEventByBlockNumber(contractAddr string,
 
 	for executeTxEvent.Next() {
 		evt := executeTxEvent.Event
-		Fee := this.GetConsumeGas(evt.Raw.TxHash)
+		Fee, GasUsed := this.getConsumeGas(evt.Raw.TxHash)
 		eccmUnlockEvents = append(eccmUnlockEvents, &models.ECCMUnlockEvent{
 			Method:   _eth_crosschainunlock,
 			TxHash:   evt.Raw.TxHash.String()[2:],
@@ -473,6 +486,7 @@ func (this *EthereumChainListen) getECCMEventByBlockNumber(contractAddr string,
 			FChainId: uint32(evt.FromChainID),
 			Height:   evt.Raw.BlockNumber,
 			Fee:      Fee,
+			GasUsed:  GasUsed,
 		})
 	}
 	return eccmLockEvents, eccmUnlockEvents, nil
@@ -578,15 +592,32 @@ func (this *EthereumChainListen) getProxyEventByBlockNumber1(contractAddr string
 	return proxyLockEvents, proxyUnlockEvents, nil
 }
 func (this *EthereumChainListen) GetConsumeGas(hash common.Hash) uint64 {
+	fee, _ := this.getConsumeGas(hash)
+	return fee
+}
+
+// getConsumeGas returns the fee paid by the transaction and the gas it used
+func (this *EthereumChainListen) getConsumeGas(hash common.Hash) (uint64, uint64) {
 	tx, err := this.ethSdk.GetTransactionByHash(hash)
 	if err != nil {
-		return 0
+		return 0, 0
 	}
 	receipt, err := this.ethSdk.GetTransactionReceipt(hash)
 	if err != nil {
-		return 0
+		return 0, 0
+	}
+	return tx.GasPrice().Uint64() * receipt.GasUsed, receipt.GasUsed
+}
+
+func (this *EthereumChainListen) GetBlockHash(height uint64) (string, string, error) {
+	header, err := this.ethSdk.GetHeaderByNumber(height)
+	if err != nil {
+		return "", "", err
+	}
+	if header == nil {
+		return "", "", fmt.Errorf("chain %d block %d header is nil", this.GetChainId(), height)
 	}
-	return tx.GasPrice().Uint64() * receipt.GasUsed
+	return strings.ToLower(header.Hash().Hex()[2:]), strings.ToLower(header.ParentHash.Hex()[2:]), nil
 }
 
 type ExtendHeightRsp struct {
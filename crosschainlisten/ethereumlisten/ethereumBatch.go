@@ -231,6 +231,7 @@ func (this *EthereumChainListen) HandleNewBatchBlock(start, end uint64) ([]*mode
 			dstTransaction.Hash = unLockEvent.TxHash
 			dstTransaction.State = 1
 			dstTransaction.Fee = models.NewBigIntFromInt(int64(unLockEvent.Fee))
+			dstTransaction.GasUsed = unLockEvent.GasUsed
 			dstTransaction.Time = blockTimer[unLockEvent.Height]
 			dstTransaction.Height = unLockEvent.Height
 			dstTransaction.SrcChainId = uint64(unLockEvent.FChainId)
@@ -411,7 +412,7 @@ func (this *EthereumChainListen) getECCMEvents(contractlogs []types.Log, ccmCont
 		case this.eventVerifyHeaderAndExecuteTxEventId:
 			evt, err := ccmContractAbi.ParseVerifyHeaderAndExecuteTxEvent(v)
 			if err == nil {
-				Fee := this.GetConsumeGas(evt.Raw.TxHash)
+				Fee, GasUsed := this.getConsumeGas(evt.Raw.TxHash)
 				eccmUnlockEvents = append(eccmUnlockEvents, &models.ECCMUnlockEvent{
 					Method:   _eth_crosschainunlock,
 					TxHash:   evt.Raw.TxHash.String()[2:],
@@ -420,6 +421,7 @@ func (this *EthereumChainListen) getECCMEvents(contractlogs []types.Log, ccmCont
 					FChainId: uint32(evt.FromChainID),
 					Height:   evt.Raw.BlockNumber,
 					Fee:      Fee,
+					GasUsed:  GasUsed,
 				})
 			} else {
 				logs.Error("fail to ParseVerifyHeaderAndExecuteTxEvent, chain: %s, contractAddr: %s, height: %d,  err: %v", basedef.GetChainName(this.ethCfg.ChainId), v.Address, v.BlockNumber, err)
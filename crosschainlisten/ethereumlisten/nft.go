@@ -269,8 +269,8 @@ func (e *EthereumChainListen) getNFTECCMEventByBlockNumber(
 
 	for executeTxEvent.Next() {
 		evt := executeTxEvent.Event
-		Fee := e.GetConsumeGas(evt.Raw.TxHash)
-		eccmUnlockEvent := verifyAndExecuteEvent2ProxyUnlockEvent(evt, Fee)
+		Fee, GasUsed := e.getConsumeGas(evt.Raw.TxHash)
+		eccmUnlockEvent := verifyAndExecuteEvent2ProxyUnlockEvent(evt, Fee, GasUsed)
 		eccmUnlockEvents = append(eccmUnlockEvents, eccmUnlockEvent)
 	}
 	return eccmLockEvents, eccmUnlockEvents, nil
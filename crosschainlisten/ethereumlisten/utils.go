@@ -66,6 +66,7 @@ func assembleDstTransaction(
 	dstTransaction.Hash = eccmUnlockEvent.TxHash
 	dstTransaction.State = 1
 	dstTransaction.Fee = models.NewBigIntFromInt(int64(eccmUnlockEvent.Fee))
+	dstTransaction.GasUsed = eccmUnlockEvent.GasUsed
 	if tt > 0 {
 		dstTransaction.Time = tt
 	}
@@ -152,6 +153,7 @@ func convertLockProxyEvent(evt *nftlp.PolyNFTLockProxyLockEvent) *models.ProxyLo
 func verifyAndExecuteEvent2ProxyUnlockEvent(
 	evt *eccm_abi.EthCrossChainManagerVerifyHeaderAndExecuteTxEvent,
 	fee uint64,
+	gasUsed uint64,
 ) *models.ECCMUnlockEvent {
 
 	return &models.ECCMUnlockEvent{
@@ -162,6 +164,7 @@ func verifyAndExecuteEvent2ProxyUnlockEvent(
 		FChainId: uint32(evt.FromChainID),
 		Height:   evt.Raw.BlockNumber,
 		Fee:      fee,
+		GasUsed:  gasUsed,
 	}
 }
 
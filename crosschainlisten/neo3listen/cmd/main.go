@@ -31,6 +31,7 @@ import (
 	"poly-bridge/conf"
 	"poly-bridge/crosschaindao"
 	"poly-bridge/crosschainlisten"
+	"poly-bridge/dbconn"
 
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/urfave/cli"
@@ -113,6 +114,9 @@ func startServer(ctx *cli.Context) {
 		logs.Error("startServer - read config failed!")
 		return
 	}
+	if err := dbconn.CheckSchema(config); err != nil {
+		panic(err)
+	}
 	{
 		conf, _ := json.Marshal(config)
 		logs.Info("%s\n", string(conf))
@@ -489,13 +489,22 @@ func (this *Stats) computeAssetStatistics() (err error) {
 		if err != nil {
 			logs.Error("Failed to CalculateAssets %w", err)
 		}
+		prices, btcPrices := this.dailyClosePrices(old.TokenBasicName, assetInfos)
 		for _, assetInfo := range assetInfos {
 			amount_new := decimal.NewFromBigInt(&assetInfo.Amount.Int, 0)
 			precision_new := decimal.New(int64(1), int32(assetInfo.Precision))
 			real_amount := amount_new.Div(precision_new)
-			price_new := decimal.NewFromInt(assetInfo.Price).Div(decimal.NewFromInt(basedef.PRICE_PRECISION))
+			// the transfers are valued at the close of their day, or at the latest price without the price history
+			price := assetInfo.Price
+			if close, ok := prices[assetInfo.Day]; ok {
+				price = close
+			}
+			price_new := decimal.NewFromInt(price).Div(decimal.NewFromInt(basedef.PRICE_PRECISION))
 			amount_usd := real_amount.Mul(price_new)
 			amount_btc := amount_usd.Div(BTCPrice)
+			if close, ok := btcPrices[assetInfo.Day]; ok && close > 0 {
+				amount_btc = amount_usd.Div(decimal.NewFromInt(close).Div(decimal.NewFromInt(basedef.PRICE_PRECISION)))
+			}
 
 			old.Amount = models.NewBigInt((real_amount.Mul(decimal.New(int64(100), 0)).Add(decimal.NewFromBigInt(&old.Amount.Int, 0))).BigInt())
 			old.AmountUsd = models.NewBigInt((amount_usd.Mul(decimal.New(int64(10000), 0)).Add(decimal.NewFromBigInt(&old.AmountUsd.Int, 0))).BigInt())
@@ -514,6 +523,31 @@ func (this *Stats) computeAssetStatistics() (err error) {
 	return nil
 }
 
+// dailyClosePrices returns the daily closing prices of the token basic and of WBTC over the days of the asset infos
+func (this *Stats) dailyClosePrices(tokenBasicName string, assetInfos []*models.AssetInfo) (map[int64]int64, map[int64]int64) {
+	if len(assetInfos) == 0 {
+		return nil, nil
+	}
+	start, end := assetInfos[0].Day, assetInfos[0].Day
+	for _, assetInfo := range assetInfos {
+		if assetInfo.Day < start {
+			start = assetInfo.Day
+		}
+		if assetInfo.Day > end {
+			end = assetInfo.Day
+		}
+	}
+	prices, err := this.dao.GetDailyClosePrices(tokenBasicName, start, end+86400)
+	if err != nil {
+		logs.Error("Failed to GetDailyClosePrices of %s %v", tokenBasicName, err)
+	}
+	btcPrices, err := this.dao.GetDailyClosePrices("WBTC", start, end+86400)
+	if err != nil {
+		logs.Error("Failed to GetDailyClosePrices of WBTC %v", err)
+	}
+	return prices, btcPrices
+}
+
 func (this *Stats) computeAssetStatisticAdress() (err error) {
 	logs.Info("start computeAssetStatisticAdress")
 	newAssetAdresses, err := this.dao.CalculateAssetAdress()
@@ -0,0 +1,220 @@
+package dbconn
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/migrations"
+)
+
+const (
+	DialectMysql    = "mysql"
+	DialectPostgres = "postgres"
+	DialectSqlite   = "sqlite"
+)
+
+// Open connects to the database of the config with the dialect of the config, the default logger is used when Logger is nil.
+// An in-process sqlite database is migrated to the schema of the binary.
+func Open(cfg *conf.DBConfig, Logger logger.Interface) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if Logger == nil {
+		Logger = logger.Default
+	}
+	sqlite := dialector.Name() == DialectSqlite
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: Logger, DisableForeignKeyConstraintWhenMigrating: sqlite})
+	if err != nil || !sqlite {
+		return db, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// sqlite locks the whole database for writing
+	sqlDB.SetMaxOpenConns(1)
+	db.ClauseBuilders["WHERE"] = buildSqliteWhere
+	if cfg.URL != "" {
+		return db, nil
+	}
+	if _, err = migrations.Up(db, 0); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// CheckSchema refuses to start a server on a database which is behind the migrations of the binary.
+// The explorer has its own schema and the stake server has no database.
+func CheckSchema(cfg *conf.Config) error {
+	if cfg.DBConfig == nil || cfg.Server == basedef.SERVER_EXPLORER || cfg.Server == basedef.SERVER_STAKE {
+		return nil
+	}
+	db, err := Open(cfg.DBConfig, nil)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	return migrations.Check(db)
+}
+
+func Dialector(cfg *conf.DBConfig) (gorm.Dialector, error) {
+	switch strings.ToLower(cfg.Dialect) {
+	case "", DialectMysql:
+		return mysql.Open(fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", cfg.User, cfg.Password, cfg.URL, cfg.Scheme)), nil
+	case DialectPostgres, "postgresql":
+		return &postgresDialector{postgres.Open(PostgresDSN(cfg))}, nil
+	case DialectSqlite, "sqlite3":
+		return sqlite.Open(SqliteDSN(cfg)), nil
+	default:
+		return nil, fmt.Errorf("unsupported db dialect: %s", cfg.Dialect)
+	}
+}
+
+func PostgresDSN(cfg *conf.DBConfig) string {
+	host := cfg.URL
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "5432")
+	}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     host,
+		Path:     "/" + cfg.Scheme,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return dsn.String()
+}
+
+// SqliteDSN is the database file of the config, or an in-process database named by the scheme when there is no file
+func SqliteDSN(cfg *conf.DBConfig) string {
+	if cfg.URL != "" {
+		return cfg.URL
+	}
+	return fmt.Sprintf("file:%s?mode=memory&cache=shared", cfg.Scheme)
+}
+
+// UpsertOn makes Save update the rows conflicting on the unique columns. Mysql updates the row of any
+// conflicting unique key, but postgres and sqlite only check the conflict target, which is the primary key by default.
+func UpsertOn(columns ...string) clause.OnConflict {
+	onConflict := clause.OnConflict{UpdateAll: true}
+	for _, column := range columns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	return onConflict
+}
+
+// InsertOn makes Create keep the rows conflicting on the unique columns, as gorm does when saving associations
+func InsertOn(columns ...string) clause.OnConflict {
+	onConflict := clause.OnConflict{}
+	for _, column := range columns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+	onConflict.DoUpdates = clause.AssignmentColumns(columns)
+	return onConflict
+}
+
+func IsMysql(db *gorm.DB) bool {
+	return db.Dialector.Name() == DialectMysql
+}
+
+func IsPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == DialectPostgres
+}
+
+var mysqlIntType = regexp.MustCompile(`^(?i)(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)$`)
+
+// postgresDialector creates the columns of the models, which are typed as mysql integers with display widths, as postgres types
+type postgresDialector struct {
+	gorm.Dialector
+}
+
+func (dialector *postgresDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	m := dialector.Dialector.Migrator(db).(postgres.Migrator)
+	m.Dialector = dialector
+	return m
+}
+
+func (dialector *postgresDialector) DataTypeOf(field *schema.Field) string {
+	if !mysqlIntType.MatchString(string(field.DataType)) {
+		return dialector.Dialector.DataTypeOf(field)
+	}
+	fieldType := field.FieldType
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	intField := *field
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		intField.DataType = schema.Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intField.DataType = schema.Int
+		intField.Size = fieldType.Bits()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		intField.DataType = schema.Uint
+		intField.Size = fieldType.Bits()
+	default:
+		return strings.ToLower(mysqlIntType.FindStringSubmatch(string(field.DataType))[1])
+	}
+	return dialector.Dialector.DataTypeOf(&intField)
+}
+
+// buildSqliteWhere builds the conditions of multiple columns in row values, which sqlite does not take in a list,
+// as the disjunction of the equalities of the rows. Gorm preloads the associations of composite keys with them.
+func buildSqliteWhere(c clause.Clause, builder clause.Builder) {
+	if where, ok := c.Expression.(clause.Where); ok {
+		exprs := make([]clause.Expression, 0, len(where.Exprs))
+		for _, expr := range where.Exprs {
+			exprs = append(exprs, sqliteRowValues(expr))
+		}
+		where.Exprs = exprs
+		c.Expression = where
+	}
+	c.Build(builder)
+}
+
+func sqliteRowValues(expr clause.Expression) clause.Expression {
+	in, ok := expr.(clause.IN)
+	if !ok {
+		return expr
+	}
+	columns, ok := in.Column.([]clause.Column)
+	if !ok || len(in.Values) == 0 {
+		return expr
+	}
+	rows := make([]clause.Expression, 0, len(in.Values))
+	for _, value := range in.Values {
+		values, ok := value.([]interface{})
+		if !ok || len(values) != len(columns) {
+			return expr
+		}
+		eqs := make([]clause.Expression, 0, len(columns))
+		for i, column := range columns {
+			eqs = append(eqs, clause.Eq{Column: column, Value: values[i]})
+		}
+		rows = append(rows, clause.AndConditions{Exprs: eqs})
+	}
+	if len(rows) == 1 {
+		return rows[0]
+	}
+	return clause.OrConditions{Exprs: rows}
+}
@@ -28,6 +28,7 @@ import (
 	"poly-bridge/common"
 	"poly-bridge/conf"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"poly-bridge/utils/decimal"
 	"poly-bridge/utils/fee"
 	"poly-bridge/utils/net"
@@ -153,9 +154,7 @@ func (c *BotController) BotPage() {
 			return
 		}
 	}
-	c.Data["json"] = err.Error()
-	c.Ctx.ResponseWriter.WriteHeader(400)
-	c.ServeJSON()
+	apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, err.Error())
 
 }
 
@@ -168,8 +167,8 @@ func (c *BotController) FinishTx() {
 	if token == conf.GlobalConfig.BotConfig.ApiToken {
 		switch status {
 		case "skip":
-			_, e := cacheRedis.Redis.Set(cacheRedis.MarkTxAsSkipPrefix+tx, "markAsSkipByBot", time.Hour*24*7)
-			if e == nil {
+			_, err = cacheRedis.Redis.Set(cacheRedis.MarkTxAsSkipPrefix+tx, "markAsSkipByBot", time.Hour*24*7)
+			if err == nil {
 				resp = fmt.Sprintf("Success mark %s as skip", tx)
 			}
 		case "wait":
@@ -178,14 +177,18 @@ func (c *BotController) FinishTx() {
 				resp = fmt.Sprintf("Success mark %s as wait", tx)
 			}
 		default:
-			err = fmt.Errorf("Invalid status")
+			apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("Tx %s Error Invalid status", tx))
+			return
 		}
 	} else {
-		err = fmt.Errorf("Access denied")
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "Access denied")
+		return
 	}
 
 	if err != nil {
-		resp = fmt.Sprintf("Tx %s Error %s", tx, err.Error())
+		logs.Error("Tx %s Error %s", tx, err.Error())
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("Tx %s Error %s", tx, err.Error()))
+		return
 	}
 	logs.Info(resp)
 	c.Data["json"] = models.MakeErrorRsp(resp)
@@ -205,16 +208,19 @@ func (c *BotController) MarkUnMarkTxAsPaid() {
 				resp = fmt.Sprintf("Success unmark %s as paid", tx)
 			}
 		} else {
-			_, e := cacheRedis.Redis.Set(cacheRedis.MarkTxAsPaidPrefix+tx, "markAsPaidByBot", time.Hour*12)
-			if e == nil {
+			_, err = cacheRedis.Redis.Set(cacheRedis.MarkTxAsPaidPrefix+tx, "markAsPaidByBot", time.Hour*12)
+			if err == nil {
 				resp = fmt.Sprintf("Success mark %s as paid", tx)
 			}
 		}
 	} else {
-		err = fmt.Errorf("Access denied")
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "Access denied")
+		return
 	}
 	if err != nil {
-		resp = fmt.Sprintf("Tx %s Error %s", tx, err.Error())
+		logs.Error("Tx %s Error %s", tx, err.Error())
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("Tx %s Error %s", tx, err.Error()))
+		return
 	}
 	logs.Info(resp)
 	c.Data["json"] = models.MakeErrorRsp(resp)
@@ -225,9 +231,7 @@ func (c *BotController) CheckFees() {
 	hashes := []string{}
 	err := json.Unmarshal(c.Ctx.Input.RequestBody, &hashes)
 	if err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 
@@ -237,9 +241,7 @@ func (c *BotController) CheckFees() {
 		c.ServeJSON()
 		return
 	}
-	c.Data["json"] = err.Error()
-	c.Ctx.ResponseWriter.WriteHeader(400)
-	c.ServeJSON()
+	apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, err.Error())
 }
 
 func (c *BotController) checkFees(hashes []string) (fees map[string]models.CheckFeeResult, err error) {
@@ -285,6 +287,10 @@ func (c *BotController) checkFees(hashes []string) (fees map[string]models.Check
 	for _, chainFee := range chainFees {
 		chain2Fees[chainFee.ChainId] = chainFee
 	}
+	gasLimits, e := fee.LoadGasLimits(db)
+	if e != nil {
+		logs.Error("load gas limits err: %v", e)
+	}
 
 	fees = make(map[string]models.CheckFeeResult, 0)
 	for _, tx := range wrapperTransactionWithTokens {
@@ -302,6 +308,12 @@ func (c *BotController) checkFees(hashes []string) (fees map[string]models.Check
 		if tx.FeeAmount == nil || tx.FeeToken == nil || tx.FeeToken.TokenBasic == nil {
 			continue
 		}
+		srcTx := hash2Tx[tx.Hash]
+		if srcTx != nil {
+			chainFee = gasLimits.ChainFee(chainFee, fee.ProxyType(srcTx.ChainId, srcTx.Contract, srcTx.Standard))
+		} else {
+			chainFee = gasLimits.ChainFee(chainFee, models.ProxyTypeLock)
+		}
 
 		x := new(big.Int).Mul(&tx.FeeAmount.Int, big.NewInt(tx.FeeToken.TokenBasic.Price))
 		payFee := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.Int64FromFigure(int(tx.FeeToken.Precision))))
@@ -311,7 +323,6 @@ func (c *BotController) checkFees(hashes []string) (fees map[string]models.Check
 		minFee = new(big.Float).Quo(minFee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
 		minFee = new(big.Float).Quo(minFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
 
-		srcTx := hash2Tx[tx.Hash]
 		if srcTx != nil && srcTx.Standard == models.TokenTypeErc721 {
 			for _, cfg := range conf.GlobalConfig.FeeListenConfig {
 				if cfg.ChainId == tx.DstChainId {
@@ -324,14 +335,11 @@ func (c *BotController) checkFees(hashes []string) (fees map[string]models.Check
 			}
 		}
 
-		// get optimistic L1 fee on ethereum
-		if chainId == basedef.OPTIMISTIC_CROSSCHAIN_ID {
-			ethChainFee, ok2 := chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID]
-			if ok2 {
-				l1MinFee, _, _, e := fee.GetL1Fee(ethChainFee, chainId)
-				if e == nil {
-					minFee = new(big.Float).Add(minFee, l1MinFee)
-				}
+		// get L1 fee of layer 2 chains
+		if fee.HasL1Fee(chainId) {
+			l1MinFee, _, _, e := fee.GetL1Fee(chainFee, chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID])
+			if e == nil {
+				minFee = new(big.Float).Add(minFee, l1MinFee)
 			}
 		}
 
@@ -403,9 +411,7 @@ func (c *BotController) GetTxs() {
 		}
 	}
 
-	c.Data["json"] = err.Error()
-	c.Ctx.ResponseWriter.WriteHeader(400)
-	c.ServeJSON()
+	apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, err.Error())
 }
 
 func (c *BotController) makeBottxsRsp(pageSize int, pageNo int, totalPage int, totalCount int, txs []*models.TxHashChainIdPair, fees map[string]models.CheckFeeResult) map[string]interface{} {
@@ -685,9 +691,7 @@ func (c *BotController) ListLargeTxPage() {
 		c.Ctx.Output.Body(rb)
 		return
 	} else {
-		c.Data["json"] = "access denied"
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "access denied")
 	}
 }
 
@@ -748,10 +752,7 @@ func (c *BotController) ListNodeStatusPage() {
 		c.Ctx.Output.Body(htmlBytes)
 		return
 	} else {
-		err := fmt.Errorf("access denied")
-		c.Data["json"] = err.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "access denied")
 	}
 }
 
@@ -763,26 +764,29 @@ func (c *BotController) IgnoreNodeStatusAlarm() {
 	resp := ""
 	if token == conf.GlobalConfig.BotConfig.ApiToken {
 		dayNum, e := strconv.Atoi(day)
-		if e == nil && dayNum >= 0 {
-			if dayNum == 0 {
-				_, e = cacheRedis.Redis.Del(cacheRedis.IgnoreNodeStatusAlarmPrefix + node)
-				if e == nil {
-					resp = fmt.Sprintf("success cancel ignore alarm")
-				}
-			} else {
-				_, e2 := cacheRedis.Redis.Set(cacheRedis.IgnoreNodeStatusAlarmPrefix+node, "ignore", time.Hour*time.Duration(24*dayNum))
-				if e2 == nil {
-					resp = fmt.Sprintf("success ignore alarm for %d days", dayNum)
-				}
+		if e != nil || dayNum < 0 {
+			apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("invalid parameter day：%s, err: %v", day, e))
+			return
+		}
+		if dayNum == 0 {
+			_, err = cacheRedis.Redis.Del(cacheRedis.IgnoreNodeStatusAlarmPrefix + node)
+			if err == nil {
+				resp = fmt.Sprintf("success cancel ignore alarm")
 			}
 		} else {
-			e = fmt.Errorf("invalid parameter day：%s, err: %s", day, e)
+			_, err = cacheRedis.Redis.Set(cacheRedis.IgnoreNodeStatusAlarmPrefix+node, "ignore", time.Hour*time.Duration(24*dayNum))
+			if err == nil {
+				resp = fmt.Sprintf("success ignore alarm for %d days", dayNum)
+			}
 		}
 	} else {
-		err = fmt.Errorf("Access denied")
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "Access denied")
+		return
 	}
 	if err != nil {
-		resp = fmt.Sprintf("Error %s", err.Error())
+		logs.Error("Error %s", err.Error())
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("Error %s", err.Error()))
+		return
 	}
 	logs.Info(resp)
 	c.Data["json"] = models.MakeErrorRsp(resp)
@@ -847,9 +851,6 @@ func (c *BotController) ListRelayerAccountStatus() {
 		c.Ctx.Output.Body(htmlBytes)
 		return
 	} else {
-		err := fmt.Errorf("access denied")
-		c.Data["json"] = err.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "access denied")
 	}
 }
@@ -23,14 +23,15 @@ import (
 	"fmt"
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/beego/beego/v2/server/web"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/cacheRedis"
 
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"strconv"
 )
 
@@ -42,9 +43,8 @@ func Init() {
 	if conf.GlobalConfig.RunMode == "dev" {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	dbConn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", dbConfig.User, dbConfig.Password, dbConfig.URL, dbConfig.Scheme)
 	var err error
-	db, err = gorm.Open(mysql.Open(dbConn), &gorm.Config{Logger: Logger})
+	db, err = dbconn.Open(dbConfig, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -74,18 +74,14 @@ func (c *ExplorerController) GetExplorerInfo() {
 	chains := make([]*models.Chain, 0)
 	res := db.Find(&chains)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_CHAIN_NOT_FOUND, "chain does not exist")
 		return
 	}
 
 	// get all chains statistic
 	chainStatistics := make([]*models.ChainStatistic, 0)
 	if db.Find(&chainStatistics).Error != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain stats does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "chain stats does not exist")
 		return
 	}
 
@@ -94,9 +90,7 @@ func (c *ExplorerController) GetExplorerInfo() {
 	res = db.Where("property = ?", 1).
 		Preload("Tokens").Find(&tokenBasics)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_CHAIN_NOT_FOUND, "chain does not exist")
 		return
 	}
 
@@ -109,20 +103,17 @@ func (c *ExplorerController) GetTokenTxList() {
 	var tokenTxListReq models.TokenTxListReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenTxListReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	transactionOnTokens := make([]*models.TransactionOnToken, 0)
 	res := db.Raw(`select a.hash, a.height, a.time, a.chain_id, b.from, b.to, b.amount, 1 as direct from src_transactions a inner join src_transfers b on a.hash = b.tx_hash where b.chain_id = ? and b.asset = ?
 		union select c.hash, c.height, c.time, c.chain_id, d.from, d.to, d.amount, 2 as direct from dst_transactions c inner join dst_transfers d on c.hash = d.tx_hash where d.chain_id = ? and d.asset = ?
-		order by height desc limit ?,?`,
-		tokenTxListReq.ChainId, tokenTxListReq.Token, tokenTxListReq.ChainId, tokenTxListReq.Token, (tokenTxListReq.PageNo-1)*tokenTxListReq.PageSize, tokenTxListReq.PageSize).
+		order by height desc limit ? offset ?`,
+		tokenTxListReq.ChainId, tokenTxListReq.Token, tokenTxListReq.ChainId, tokenTxListReq.Token, tokenTxListReq.PageSize, (tokenTxListReq.PageNo-1)*tokenTxListReq.PageSize).
 		Scan(&transactionOnTokens)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("transactionOnTokens does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "transactionOnTokens does not exist")
 		return
 	}
 	counter := struct {
@@ -131,9 +122,7 @@ func (c *ExplorerController) GetTokenTxList() {
 	res = db.Raw("select sum(in_counter)+sum(out_counter) as counter from token_statistics where chain_id = ? and hash = ?", tokenTxListReq.ChainId, tokenTxListReq.Token).
 		Scan(&counter)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("tokenStatistic does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "tokenStatistic does not exist")
 		return
 	}
 	token := &models.Token{}
@@ -149,9 +138,8 @@ func (c *ExplorerController) GetAddressTxList() {
 	var addressTxListReq models.AddressTxListReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &addressTxListReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	addressTxListReq.Address, _ = basedef.Address2Hash(addressTxListReq.ChainId, addressTxListReq.Address)
 
@@ -160,14 +148,14 @@ func (c *ExplorerController) GetAddressTxList() {
 	if addressTxListReq.ChainId == 0 {
 		res = db.Debug().Raw(`select a.hash, a.height, a.time, a.chain_id, b.from, b.to, b.amount, c.hash as token_hash, c.standard as token_standard, c.name as token_name, 1 as direct, m.precision, m.meta from src_transactions a left join src_transfers b on a.hash = b.tx_hash left join tokens c on b.asset = c.hash and b.chain_id = c.chain_id left JOIN token_basics m on c.token_basic_name = m.name where b.from = ? and c.standard = 0 
 		union select d.hash, d.height, d.time, d.chain_id, e.from, e.to, e.amount, f.hash as token_hash, f.standard as token_standard, f.name as token_name, 2 as direct, n.precision, n.meta from dst_transactions d left join dst_transfers e on d.hash = e.tx_hash left join tokens f on e.asset = f.hash and e.chain_id = f.chain_id left JOIN token_basics n on f.token_basic_name = n.name where e.to = ? and f.standard = 0
-		order by height desc limit ?,?`,
-			addressTxListReq.Address, addressTxListReq.Address, (addressTxListReq.PageNo-1)*addressTxListReq.PageSize, addressTxListReq.PageSize).
+		order by height desc limit ? offset ?`,
+			addressTxListReq.Address, addressTxListReq.Address, addressTxListReq.PageSize, (addressTxListReq.PageNo-1)*addressTxListReq.PageSize).
 			Find(&transactionOnAddresses)
 	} else {
 		res = db.Debug().Raw(`select a.hash, a.height, a.time, a.chain_id, b.from, b.to, b.amount, c.hash as token_hash, c.standard as token_standard, c.name as token_name, 1 as direct, m.precision, m.meta from src_transactions a left join src_transfers b on a.hash = b.tx_hash left join tokens c on b.asset = c.hash and b.chain_id = c.chain_id left JOIN token_basics m on c.token_basic_name = m.name where b.from = ? and b.chain_id = ? and c.standard = 0
 		union select d.hash, d.height, d.time, d.chain_id, e.from, e.to, e.amount, f.hash as token_hash, f.standard as token_standard, f.name as token_name, 2 as direct, n.precision, n.meta from dst_transactions d left join dst_transfers e on d.hash = e.tx_hash left join tokens f on e.asset = f.hash and e.chain_id = f.chain_id left JOIN token_basics n on f.token_basic_name = n.name where e.to = ? and e.chain_id = ? and f.standard = 0
-		order by height desc limit ?,?`,
-			addressTxListReq.Address, addressTxListReq.ChainId, addressTxListReq.Address, addressTxListReq.ChainId, (addressTxListReq.PageNo-1)*addressTxListReq.PageSize, addressTxListReq.PageSize).
+		order by height desc limit ? offset ?`,
+			addressTxListReq.Address, addressTxListReq.ChainId, addressTxListReq.Address, addressTxListReq.ChainId, addressTxListReq.PageSize, (addressTxListReq.PageNo-1)*addressTxListReq.PageSize).
 			Find(&transactionOnAddresses)
 	}
 	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
@@ -179,9 +167,7 @@ func (c *ExplorerController) GetAddressTxList() {
 		return
 	}
 	if res.Error != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("transactionOnAddresses does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "transactionOnAddresses does not exist")
 		return
 	}
 
@@ -201,9 +187,7 @@ func (c *ExplorerController) GetAddressTxList() {
 	}
 
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("counter does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "counter does not exist")
 		return
 	}
 	c.Data["json"] = models.MakeAddressTxList(transactionOnAddresses, counter.Counter)
@@ -216,24 +200,32 @@ func (c *ExplorerController) GetCrossTxList() {
 	var crossTxListReq models.CrossTxListReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &crossTxListReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	logs.Info("crossTxListReq %v", crossTxListReq)
+	cursor, err := models.ParsePageCursor(crossTxListReq.Cursor)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
 	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
-	res := db.Debug().Model(&models.PolyTransaction{}).
+	query := db.Debug().Model(&models.PolyTransaction{}).
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash").
 		Where("src_transactions.standard = ?", 0).
 		Joins("left join src_transactions on src_transactions.hash = poly_transactions.src_hash").
 		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
-		Order("src_transactions.time desc").
-		Limit(crossTxListReq.PageSize).Offset((crossTxListReq.PageNo - 1) * crossTxListReq.PageSize).
-		Find(&srcPolyDstRelations)
+		Preload("SrcTransaction").
+		Order("src_transactions.time desc").Order("src_transactions.id desc").
+		Limit(crossTxListReq.PageSize)
+	if cursor != nil {
+		query = query.Where("src_transactions.time < ? or (src_transactions.time = ? and src_transactions.id < ?)", cursor.Time, cursor.Time, cursor.Id)
+	} else {
+		query = query.Offset((crossTxListReq.PageNo - 1) * crossTxListReq.PageSize)
+	}
+	res := query.Find(&srcPolyDstRelations)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("srcPolyDstRelations does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "srcPolyDstRelations does not exist")
 		return
 	}
 	for _, srcPolyDstRelation := range srcPolyDstRelations {
@@ -249,7 +241,10 @@ func (c *ExplorerController) GetCrossTxList() {
 	}
 
 	logs.Info("GetCrossTxList count counter")
-	counter, err := cacheRedis.Redis.GetCrossTxCounter()
+	var counter int64
+	if !crossTxListReq.NoCount {
+		counter, err = cacheRedis.Redis.GetCrossTxCounter()
+	}
 	if err != nil {
 		logs.Info(err)
 		res := db.Debug().Model(&models.PolyTransaction{}).
@@ -257,9 +252,7 @@ func (c *ExplorerController) GetCrossTxList() {
 			Joins("left join src_transactions on src_transactions.hash = poly_transactions.src_hash").
 			Count(&counter)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("CrossTxCounter does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "CrossTxCounter does not exist")
 			return
 		}
 		err = cacheRedis.Redis.SetCrossTxCounter(counter)
@@ -268,7 +261,9 @@ func (c *ExplorerController) GetCrossTxList() {
 		}
 		logs.Info("GetCrossTxList count counter end")
 	}
-	c.Data["json"] = models.MakeCrossTxListResp(srcPolyDstRelations, counter)
+	crossTxListResp := models.MakeCrossTxListResp(srcPolyDstRelations, counter)
+	crossTxListResp.NextCursor = models.NextPageCursor(crossTxListReq.PageSize, srcPolyDstRelations)
+	c.Data["json"] = crossTxListResp
 	c.ServeJSON()
 }
 
@@ -276,9 +271,8 @@ func (c *ExplorerController) GetCrossTxList() {
 func (c *ExplorerController) GetCrossTx() {
 	var crossTxReq models.CrossTxReq
 	if len(c.Ctx.Input.Query("txhash")) == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	crossTxReq.TxHash = c.Ctx.Input.Query("txhash")
 	fmt.Println("crossTxReq.TxHash", crossTxReq.TxHash)
@@ -290,9 +284,7 @@ func (c *ExplorerController) GetCrossTx() {
 		left join dst_transactions d on d.poly_hash=p.hash where d.hash=?`,
 		crossTxReq.TxHash, crossTxReq.TxHash, crossTxReq.TxHash).First(tx).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) || tx.Hash == "" {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("relations does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "relations does not exist")
 		return
 	}
 	res := db.Model(&models.SrcTransaction{}).
@@ -312,9 +304,7 @@ func (c *ExplorerController) GetCrossTx() {
 		return
 	}
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("relations does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "relations does not exist")
 		return
 	}
 	relation := relations[0]
@@ -406,9 +396,7 @@ func (c *ExplorerController) GetAssetStatistic() {
 	res := db.Preload("TokenBasic").
 		Find(&assetStatistics)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("assetStatistic does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "assetStatistic does not exist")
 		return
 	}
 	c.Data["json"] = models.MakeAssetInfoResp(assetStatistics)
@@ -418,9 +406,8 @@ func (c *ExplorerController) GetAssetStatistic() {
 func (c *ExplorerController) GetTransferStatistic() {
 	var transferStatisticReq models.TransferStatisticReq
 	if len(c.Ctx.Input.Query("chain")) == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("getTransferStatistic request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "getTransferStatistic request parameter is invalid!")
+		return
 	}
 	if chainId, err := strconv.Atoi(c.Ctx.Input.Query("chain")); err == nil {
 		transferStatisticReq.Chain = uint64(chainId)
@@ -440,23 +427,17 @@ func (c *ExplorerController) GetTransferStatistic() {
 		logs.Info("not redisGetAllTransferResp err", err)
 		res := db.Preload("Token").Preload("Token.TokenBasic").Find(&tokenStatistics)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("transferStatistics does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "transferStatistics does not exist")
 			return
 		}
 		res = db.Model(&models.ChainStatistic{}).Find(&chainStatistics)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chainStatistics does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "chainStatistics does not exist")
 			return
 		}
 		res = db.Model(&models.Chain{}).Find(&chains)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chains does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "chains does not exist")
 			return
 		}
 		resp = models.MakeTransferInfoResp(tokenStatistics, chainStatistics, chains)
@@ -472,25 +453,19 @@ func (c *ExplorerController) GetTransferStatistic() {
 			Preload("Token").Preload("Token.TokenBasic").
 			Find(&tokenStatistics)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("transferStatistics does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "transferStatistics does not exist")
 			return
 		}
 		res = db.Model(&models.ChainStatistic{}).
 			Where("chain_id=?", transferStatisticReq.Chain).Find(&chainStatistics)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chainStatistics does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "chainStatistics does not exist")
 			return
 		}
 		res = db.Model(&models.Chain{}).
 			Where("chain_id=?", transferStatisticReq.Chain).Find(&chains)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chains does not exist"))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "chains does not exist")
 			return
 		}
 	}
@@ -500,12 +475,10 @@ func (c *ExplorerController) GetTransferStatistic() {
 
 func (c *ExplorerController) GetLockTokenList() {
 	lockTokenResps := make([]*models.LockTokenResp, 0)
-	res := db.Raw("select  chain_id,CONVERT(sum(in_amount_usd), DECIMAL(37, 0)) as in_amount_usd,COUNT(DISTINCT(`hash`)) as token_num,COUNT(DISTINCT(`item_proxy`)) as proxy_num from lock_token_statistics where in_amount_usd >0 group by chain_id").
+	res := db.Raw("select  chain_id,CAST(sum(CAST(in_amount_usd AS DECIMAL(65, 0))) AS DECIMAL(37, 0)) as in_amount_usd,COUNT(DISTINCT(hash)) as token_num,COUNT(DISTINCT(item_proxy)) as proxy_num from lock_token_statistics where CAST(in_amount_usd AS DECIMAL(65, 0)) >0 group by chain_id").
 		Scan(&lockTokenResps)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("GetLockTokenList does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "GetLockTokenList does not exist")
 		return
 	}
 	c.Data["json"] = models.MakeLockTokenListResp(lockTokenResps)
@@ -515,22 +488,19 @@ func (c *ExplorerController) GetLockTokenList() {
 func (c *ExplorerController) GetLockTokenInfo() {
 	var lockTokenInfoReq models.LockTokenInfoReq
 	if len(c.Ctx.Input.Query("chainId")) == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("GetLockTokenStatistic request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "GetLockTokenStatistic request parameter is invalid!")
+		return
 	}
 	if chainId, err := strconv.Atoi(c.Ctx.Input.Query("chainId")); err == nil {
 		lockTokenInfoReq.ChainId = uint64(chainId)
 	}
 	lockTokenStatistics := make([]*models.LockTokenStatistic, 0)
-	res := db.Where("chain_id = ? and in_amount_usd > 0", lockTokenInfoReq.ChainId).
+	res := db.Where("chain_id = ? and CAST(in_amount_usd AS DECIMAL(65, 0)) > 0", lockTokenInfoReq.ChainId).
 		Preload("Token").
 		Preload("Token.TokenBasic").
 		Find(&lockTokenStatistics)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("lockTokenStatistics does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "lockTokenStatistics does not exist")
 		return
 	}
 	c.Data["json"] = models.MakeLockTokenInfoResp(lockTokenStatistics)
@@ -542,22 +512,18 @@ func (c *ExplorerController) GetEthEffectUser() {
 	var err error
 	err = json.Unmarshal(c.Ctx.Input.RequestBody, &evmosEthNftInfoReq)
 	if err != nil || evmosEthNftInfoReq.PageSize > 500 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	var total int64
-	err = db.Model(&models.NftUser{}).Where("df_chain_id = ? and effect_amount_usd > 0", basedef.ETHEREUM_CROSSCHAIN_ID).
+	err = db.Model(&models.NftUser{}).Where("df_chain_id = ? and CAST(effect_amount_usd AS DECIMAL(65, 0)) > 0", basedef.ETHEREUM_CROSSCHAIN_ID).
 		Count(&total).Error
 	if err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("no data!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "no data!")
 		return
 	}
 	nftUsers := make([]models.NftUser, 0)
-	db.Model(&models.NftUser{}).Where("df_chain_id = ? and effect_amount_usd > 0", basedef.ETHEREUM_CROSSCHAIN_ID).
+	db.Model(&models.NftUser{}).Where("df_chain_id = ? and CAST(effect_amount_usd AS DECIMAL(65, 0)) > 0", basedef.ETHEREUM_CROSSCHAIN_ID).
 		Limit(evmosEthNftInfoReq.PageSize).Offset((evmosEthNftInfoReq.PageNo - 1) * evmosEthNftInfoReq.PageSize).
 		Find(&nftUsers)
 	c.Data["json"] = models.MakeEvmosEthNftInfoResp(nftUsers, total)
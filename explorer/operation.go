@@ -6,6 +6,7 @@ import (
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"strings"
 )
 
@@ -21,8 +22,7 @@ const (
 func (c *OperationController) GetOperationData() {
 	token := c.Ctx.Input.Query("token")
 	if len(token) == 0 || token != conf.GlobalConfig.OperationConfig.ApiToken {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "access denied")
 		return
 	}
 
@@ -33,13 +33,15 @@ func (c *OperationController) GetOperationData() {
 	case AIRDROP:
 		bodyData, err = c.getAirDropData()
 	default:
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("data is null!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("method %s is not supported", method))
 		return
 	}
-	if len(bodyData) == 0 || err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("data is null!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get %s data err: %v", method, err))
+		return
+	}
+	if len(bodyData) == 0 {
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, "data is null!")
 		return
 	}
 	if c.Ctx.ResponseWriter.Header().Get("Content-Type") == "" {
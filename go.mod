@@ -30,11 +30,13 @@ require (
 	github.com/portto/aptos-go-sdk v0.0.0-20221025115549-5c74acafa193
 	github.com/rubblelabs/ripple v0.0.0-20220222071018-38c1a8b14c18
 	github.com/starcoinorg/starcoin-go v0.0.0-20220105024102-530daedc128b
-	github.com/stretchr/testify v1.7.0
+	github.com/stretchr/testify v1.8.2
 	github.com/tendermint/tendermint v0.33.7
 	github.com/urfave/cli v1.22.4
 	gorm.io/driver/mysql v1.0.3
-	gorm.io/gorm v1.20.8
+	gorm.io/driver/postgres v1.2.3
+	gorm.io/driver/sqlite v1.1.4
+	gorm.io/gorm v1.22.3
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.0.0-20221020003552-4126fa611266
 )
 
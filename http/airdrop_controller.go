@@ -2,10 +2,11 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/beego/beego/v2/server/web"
 	"poly-bridge/basedef"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 )
 
 type AirDropController struct {
@@ -16,9 +17,7 @@ func (c *AirDropController) AirDropOfAddress() {
 	var addressReq models.AirDropReq
 	err := json.Unmarshal(c.Ctx.Input.RequestBody, &addressReq)
 	if err != nil || len(addressReq.Users) == 0 || len(addressReq.Users) > 10 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	ethAddrs := make([]string, 0)
@@ -34,14 +33,12 @@ func (c *AirDropController) AirDropOfAddress() {
 	}
 
 	if len(ethAddrs) == 0 && len(otherUsers) == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 
 	airDropInfosUser := make([]*models.AirDropInfo, 0)
-	db.Where("user in ?", otherUsers).
+	db.Where("air_drop_infos.user in ?", otherUsers).
 		Find(&airDropInfosUser)
 	airDropInfosAddr := make([]*models.AirDropInfo, 0)
 	db.Where("bind_addr in ?", ethAddrs).
@@ -56,8 +53,12 @@ func (c *AirDropController) AirDropOfAddress() {
 	}
 
 	airDropRanks := make([]*models.AirDropRank, 0)
-	db.Table("(?) as b", db.Table("(select @curRank := 0) as r, (?) as t",
-		db.Model(&models.AirDropInfo{}).Select("sum(amount) as sum_amount, bind_addr").Group("bind_addr").Order("sum_amount desc, bind_addr")).Select("t.sum_amount as amount,t.bind_addr,@curRank := @curRank + 1 as rank")).
+	sumAmounts := db.Model(&models.AirDropInfo{}).Select("sum(amount) as sum_amount, bind_addr").Group("bind_addr").Order("sum_amount desc, bind_addr")
+	ranks := db.Table("(select @curRank := 0) as r, (?) as t", sumAmounts).Select("t.sum_amount as amount,t.bind_addr,@curRank := @curRank + 1 as rank")
+	if !dbconn.IsMysql(db) {
+		ranks = db.Table("(?) as t", sumAmounts).Select("t.sum_amount as amount,t.bind_addr,row_number() over (order by t.sum_amount desc, t.bind_addr) as rank")
+	}
+	db.Table("(?) as b", ranks).
 		Where("b.bind_addr in ?", bindAddrs).
 		Find(&airDropRanks)
 
@@ -69,9 +70,7 @@ func (c *AirDropController) AirDropClaim() {
 	var addressReq models.AirDropClaimReq
 	err := json.Unmarshal(c.Ctx.Input.RequestBody, &addressReq)
 	if err != nil || len(addressReq.AirDropAddrs) == 0 || len(addressReq.AirDropAddrs) > 10 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	airDropNfts := make([]*models.AirDropNft, 0)
@@ -19,11 +19,11 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/beego/beego/v2/core/logs"
 	"poly-bridge/basedef"
 	"poly-bridge/cacheRedis"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"strconv"
 
 	"github.com/beego/beego/v2/server/web"
@@ -37,17 +37,25 @@ func (c *ChainHealthController) Health() {
 	var chainHealthReq models.ChainHealthReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &chainHealthReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 
 	chainHealthRsp := &models.ChainHealthRsp{
 		Result: make(map[uint64]bool),
 	}
 	logs.Info("chainHealthReq:%+v", chainHealthReq)
+	circuits, err := cacheRedis.Redis.GetCircuitBreaks()
+	if err != nil {
+		logs.Error("get circuit breaks error: %s", err)
+	}
 	for _, chainId := range chainHealthReq.ChainIds {
 		chainHealthRsp.Result[chainId] = true
+		if circuit, ok := circuits[basedef.ChainCircuitKey(chainId)]; ok {
+			logs.Info("chain %d paused: %s", chainId, circuit.Reason)
+			chainHealthRsp.Result[chainId] = false
+			continue
+		}
 		var chainStatus basedef.ChainStatus
 		dataStr, err := cacheRedis.Redis.Get(cacheRedis.ChainStatusPrefix + strconv.FormatUint(chainId, 10))
 		if err == nil {
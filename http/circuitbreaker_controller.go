@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+const circuitBreakLogCount = 50
+
+type CircuitBreakerController struct {
+	web.Controller
+}
+
+func (c *CircuitBreakerController) authorized() bool {
+	cfg := conf.GlobalConfig.CircuitBreakerConfig
+	token := c.Ctx.Input.Query("token")
+	if cfg == nil || cfg.ApiToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.ApiToken)) != 1 {
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "Access denied")
+		return false
+	}
+	return true
+}
+
+// CircuitBreaks lists the paused chains, routes and tokens, with the last pauses and resumes
+func (c *CircuitBreakerController) CircuitBreaks() {
+	if !c.authorized() {
+		return
+	}
+	circuits, err := cacheRedis.Redis.GetCircuitBreaks()
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get circuit breaks err: %v", err))
+		return
+	}
+	rsp := &models.CircuitBreakRsp{CircuitBreaks: make([]*basedef.CircuitBreak, 0, len(circuits))}
+	for _, circuit := range circuits {
+		rsp.CircuitBreaks = append(rsp.CircuitBreaks, circuit)
+	}
+	rsp.Logs, _ = cacheRedis.Redis.GetCircuitBreakLogs(circuitBreakLogCount)
+	c.Data["json"] = rsp
+	c.ServeJSON()
+}
+
+// CircuitBreak pauses or resumes a chain, a route or a token
+func (c *CircuitBreakerController) CircuitBreak() {
+	if !c.authorized() {
+		return
+	}
+	var req models.CircuitBreakReq
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	circuit := &basedef.CircuitBreak{
+		Scope:    req.Scope,
+		Reason:   req.Reason,
+		Operator: req.Operator,
+		Time:     time.Now().Unix(),
+	}
+	switch req.Scope {
+	case basedef.CIRCUIT_CHAIN:
+		circuit.Target = fmt.Sprint(req.ChainId)
+	case basedef.CIRCUIT_ROUTE:
+		circuit.Target = basedef.RouteCircuitTarget(req.SrcChainId, req.DstChainId)
+	case basedef.CIRCUIT_TOKEN:
+		circuit.Target = req.Token
+	}
+	if circuit.Target == "" || circuit.Target == "0" || req.Reason == "" || req.Operator == "" {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "scope target, reason and operator are required")
+		return
+	}
+	var done bool
+	var err error
+	switch req.Action {
+	case "pause":
+		done, err = cacheRedis.Redis.PauseCircuit(circuit)
+	case "resume":
+		done, err = cacheRedis.Redis.ResumeCircuit(circuit)
+	default:
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("action %s is invalid, pause or resume", req.Action))
+		return
+	}
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("%s circuit %s err: %v", req.Action, circuit.Key(), err))
+		return
+	}
+	if !done {
+		apierror.Output(&c.Controller, basedef.ERROR_CIRCUIT_NOT_PAUSED, fmt.Sprintf("circuit %s is not paused", circuit.Key()))
+		return
+	}
+	c.Data["json"] = circuit
+	c.ServeJSON()
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+	"strings"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+const maxFeeRoutes = 100
+
+// GetFees gets the fees of many routes as GetFee, the fee of a route which fails is replaced by its error
+func (c *FeeController) GetFees() {
+	var getFeesReq models.GetFeesReq
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &getFeesReq); err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	routes := make([]*models.GetFeeReq, 0, len(getFeesReq.DstChainIds)+len(getFeesReq.Routes))
+	for _, dstChainId := range getFeesReq.DstChainIds {
+		routes = append(routes, &models.GetFeeReq{SrcChainId: getFeesReq.SrcChainId, Hash: getFeesReq.Hash, DstChainId: dstChainId, User: getFeesReq.User})
+	}
+	for _, route := range getFeesReq.Routes {
+		if route == nil {
+			continue
+		}
+		if route.User == "" {
+			route.User = getFeesReq.User
+		}
+		routes = append(routes, route)
+	}
+	if len(routes) == 0 || len(routes) > maxFeeRoutes {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("request parameter is invalid! routes should be 1 to %d", maxFeeRoutes))
+		return
+	}
+	// the fees are not quoted when the circuits can not be checked
+	circuits, err := cacheRedis.Redis.GetCircuitBreaks()
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get circuit breaks err: %v", err))
+		return
+	}
+	c.Data["json"] = &models.GetFeesRsp{Fees: routeFees(routes, circuits, proxyFeeRatio)}
+	c.ServeJSON()
+}
+
+func tokenKey(chainId uint64, hash string) string {
+	return fmt.Sprintf("%d:%s", chainId, strings.ToLower(hash))
+}
+
+// routeFees calculates the fees of the routes with the chain fees and the tokens loaded once,
+// ratio returns the ratio of the proxy fee of a token as proxyFeeRatio and is called once per token basic
+func routeFees(routes []*models.GetFeeReq, circuits map[string]*basedef.CircuitBreak, ratio func(token *models.Token) *big.Float) []*models.RouteFeeRsp {
+	chainFees := make([]*models.ChainFee, 0)
+	db.Preload("TokenBasic").Find(&chainFees)
+	chain2Fees := make(map[uint64]*models.ChainFee, len(chainFees))
+	for _, chainFee := range chainFees {
+		chain2Fees[chainFee.ChainId] = chainFee
+	}
+	hashes := make([]string, 0, len(routes))
+	for _, route := range routes {
+		hashes = append(hashes, route.Hash)
+		if route.SwapTokenHash != "" {
+			hashes = append(hashes, route.SwapTokenHash)
+		}
+	}
+	tokens := make([]*models.Token, 0)
+	db.Where("hash in ?", hashes).Preload("TokenBasic").Find(&tokens)
+	key2Token := make(map[string]*models.Token, len(tokens))
+	for _, token := range tokens {
+		key2Token[tokenKey(token.ChainId, token.Hash)] = token
+	}
+	ratios := make(map[string]*big.Float)
+	gasLimits := loadGasLimits()
+
+	feeOfRoute := func(route *models.GetFeeReq) *models.RouteFeeRsp {
+		token, ok := key2Token[tokenKey(route.SrcChainId, route.Hash)]
+		if !ok {
+			return models.MakeRouteFeeErrorRsp(route, basedef.ERROR_TOKEN_NOT_FOUND, fmt.Sprintf("chain: %d does not have token: %s", route.SrcChainId, route.Hash))
+		}
+		if circuit := cacheRedis.MatchCircuit(circuits, route.SrcChainId, route.DstChainId, token.TokenBasicName); circuit != nil {
+			paused := models.MakeCircuitBreakPausedRsp(circuit)
+			return models.MakeRouteFeeErrorRsp(route, paused.Code, paused.Message)
+		}
+		if token.TokenBasic == nil || token.TokenBasic.Price == 0 {
+			return models.MakeRouteFeeErrorRsp(route, basedef.ERROR_PRICE_ZERO, fmt.Sprintf("token: %v price is 0", token.TokenBasicName))
+		}
+		chainFee, ok := chain2Fees[route.DstChainId]
+		if !ok {
+			return models.MakeRouteFeeErrorRsp(route, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("chain: %d does not have fee", route.DstChainId))
+		}
+		ethChainFee := chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID]
+		isNftSwap := true
+		var swapToken *models.Token
+		if route.SwapTokenHash != "" {
+			if swapToken = key2Token[tokenKey(route.SrcChainId, route.SwapTokenHash)]; swapToken != nil && swapToken.Standard == models.TokenTypeErc20 {
+				isNftSwap = false
+			}
+		}
+		chainFee = gasLimits.ChainFee(chainFee, routeProxyType(route, swapToken))
+		tokenRatio, ok := ratios[token.TokenBasicName]
+		if !ok {
+			tokenRatio = ratio(token)
+			ratios[token.TokenBasicName] = tokenRatio
+		}
+		rf, err := calcRouteFee(token, chainFee, ethChainFee, route.DstChainId, isNftSwap, tokenRatio)
+		if err != nil {
+			return models.MakeRouteFeeErrorRsp(route, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("get L1 fee failed. err=%v", err))
+		}
+		quote, err := quoteFee(route, rf.tokenFeeWithPrecision)
+		if err != nil {
+			logs.Error("quote fee of %s err: %v", route.User, err)
+		}
+		return models.MakeRouteFeeRsp(route, rf.baseUsdtFee, rf.nftRatio, rf.usdtFee, rf.l1UsdtFee, rf.l1TokenFee, rf.tokenFee,
+			rf.tokenFeeWithPrecision, token.Precision, quote)
+	}
+
+	rsps := make([]*models.RouteFeeRsp, 0, len(routes))
+	for _, route := range routes {
+		rsps = append(rsps, feeOfRoute(route))
+	}
+	return rsps
+}
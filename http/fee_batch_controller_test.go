@@ -0,0 +1,93 @@
+package http
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestRouteFees(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		DBConfig:        &conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "http_route_fees"},
+		RelayUrl:        "http://localhost:30330",
+		FeeListenConfig: []*conf.FeeListenConfig{{ChainId: basedef.BSC_CROSSCHAIN_ID, NftRatio: 50}},
+	}
+	Init()
+	assert.NoError(t, db.AutoMigrate(&models.TokenBasic{}, &models.Token{}, &models.ChainFee{}))
+
+	usdt := "dac17f958d2ee523a2206206994597c13d831ec7"
+	dai := "6b175474e89094c44da98b954eedeac495271d0f"
+	assert.NoError(t, db.Create([]*models.TokenBasic{
+		{Name: "BNB", Precision: 18, Price: 300 * basedef.PRICE_PRECISION},
+		{Name: "USDT", Precision: 6, Price: basedef.PRICE_PRECISION},
+		{Name: "DAI", Precision: 18, Price: basedef.PRICE_PRECISION},
+	}).Error)
+	assert.NoError(t, db.Create([]*models.Token{
+		{Hash: usdt, ChainId: 2, Name: "USDT", Precision: 6, TokenBasicName: "USDT", Standard: models.TokenTypeErc20},
+		{Hash: dai, ChainId: 2, Name: "DAI", Precision: 18, TokenBasicName: "DAI", Standard: models.TokenTypeErc20},
+	}).Error)
+	// 0.001 BNB
+	proxyFee, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	assert.NoError(t, db.Create(&models.ChainFee{
+		ChainId: basedef.BSC_CROSSCHAIN_ID, TokenBasicName: "BNB", ProxyFee: models.NewBigInt(proxyFee),
+		MaxFee: models.NewBigIntFromInt(0), MinFee: models.NewBigIntFromInt(0),
+	}).Error)
+
+	ratios := 0
+	ratio := func(token *models.Token) *big.Float {
+		ratios++
+		return nil
+	}
+	circuits := map[string]*basedef.CircuitBreak{
+		basedef.TokenCircuitKey("DAI"): {Scope: basedef.CIRCUIT_TOKEN, Target: "DAI", Reason: "test"},
+	}
+	fees := routeFees([]*models.GetFeeReq{
+		{SrcChainId: 2, Hash: usdt, DstChainId: basedef.BSC_CROSSCHAIN_ID},
+		{SrcChainId: 2, Hash: usdt, DstChainId: basedef.BSC_CROSSCHAIN_ID, SwapTokenHash: usdt},
+		{SrcChainId: 2, Hash: usdt, DstChainId: 3},
+		{SrcChainId: 2, Hash: dai, DstChainId: basedef.BSC_CROSSCHAIN_ID},
+		{SrcChainId: 3, Hash: usdt, DstChainId: basedef.BSC_CROSSCHAIN_ID},
+	}, circuits, ratio)
+	assert.Len(t, fees, 5)
+	assert.Equal(t, 1, ratios)
+
+	// the nft ratio applies without an erc20 swap token
+	assert.Nil(t, fees[0].Error)
+	assert.Equal(t, "0.3", fees[0].BaseUsdtAmount)
+	assert.Equal(t, "0.5", fees[0].NftRatio)
+	assert.Equal(t, "0.15", fees[0].UsdtAmount)
+	assert.Equal(t, "150000", fees[0].TokenAmountWithPrecision)
+	assert.Equal(t, "0", fees[0].L1TokenAmount)
+	assert.Nil(t, fees[1].Error)
+	assert.Empty(t, fees[1].NftRatio)
+	assert.Equal(t, "300000", fees[1].TokenAmountWithPrecision)
+
+	if assert.NotNil(t, fees[2].Error) {
+		assert.Equal(t, basedef.ERROR_FEE_UNAVAILABLE, fees[2].Error.Code)
+	}
+	if assert.NotNil(t, fees[3].Error) {
+		assert.Equal(t, basedef.ERROR_CIRCUIT_PAUSED, fees[3].Error.Code)
+	}
+	if assert.NotNil(t, fees[4].Error) {
+		assert.Equal(t, basedef.ERROR_TOKEN_NOT_FOUND, fees[4].Error.Code)
+		assert.Equal(t, uint64(3), fees[4].SrcChainId)
+	}
+}
+
+func TestRouteProxyType(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		ChainListenConfig: []*conf.ChainListenConfig{{ChainId: 2, SwapContract: "0x7b8e0dd9c1e7e2bd2d3c63b4a1c43a2a6c3a0a8d"}},
+	}
+	usdt := &models.Token{Hash: "dac17f958d2ee523a2206206994597c13d831ec7", ChainId: 2, Standard: models.TokenTypeErc20}
+	nft := &models.Token{Hash: "b47e3cd837ddf8e4c57f05d70ab865de6e193bbb", ChainId: 2, Standard: models.TokenTypeErc721}
+	assert.Equal(t, models.ProxyTypeLock, routeProxyType(&models.GetFeeReq{SrcChainId: 2, Hash: usdt.Hash}, nil))
+	// the token sent is not a swap even on the chain with the swap contract
+	assert.Equal(t, models.ProxyTypeLock, routeProxyType(&models.GetFeeReq{SrcChainId: 2, Hash: usdt.Hash, SwapTokenHash: usdt.Hash}, usdt))
+	assert.Equal(t, models.ProxyTypeNft, routeProxyType(&models.GetFeeReq{SrcChainId: 2, Hash: usdt.Hash, SwapTokenHash: nft.Hash}, nft))
+	assert.Equal(t, models.ProxyTypeLock, routeProxyType(&models.GetFeeReq{SrcChainId: 6, Hash: usdt.Hash, SwapTokenHash: usdt.Hash}, nil))
+}
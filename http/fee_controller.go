@@ -26,8 +26,10 @@ import (
 	"poly-bridge/common"
 	"poly-bridge/conf"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"poly-bridge/utils/fee"
 	"strings"
+	"time"
 
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/beego/beego/v2/server/web"
@@ -48,108 +50,69 @@ func (c *FeeController) GetFee() {
 	var getFeeReq models.GetFeeReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &getFeeReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	token := new(models.Token)
 	res := db.Where("hash = ? and chain_id = ?", getFeeReq.Hash, getFeeReq.SrcChainId).Preload("TokenBasic").First(token)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have token: %s", getFeeReq.SrcChainId, getFeeReq.Hash))
-		c.Ctx.ResponseWriter.WriteHeader(400)
+		apierror.Output(&c.Controller, basedef.ERROR_TOKEN_NOT_FOUND, fmt.Sprintf("chain: %d does not have token: %s", getFeeReq.SrcChainId, getFeeReq.Hash))
+		return
+	}
+	// the fee is not quoted when the circuits can not be checked
+	circuit, err := cacheRedis.Redis.CheckCircuit(getFeeReq.SrcChainId, getFeeReq.DstChainId, token.TokenBasicName)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("check circuit breaks err: %v", err))
+		return
+	}
+	if circuit != nil {
+		c.Data["json"] = models.MakeCircuitBreakPausedRsp(circuit)
+		c.Ctx.ResponseWriter.WriteHeader(basedef.ErrorStatus(basedef.ERROR_CIRCUIT_PAUSED))
 		c.ServeJSON()
 		return
 	}
 	feeTokenPrecison := token.Precision
 	if token.TokenBasic.Price == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token: %v price is 0", token.TokenBasic.Name))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_PRICE_ZERO, fmt.Sprintf("token: %v price is 0", token.TokenBasic.Name))
 		return
 	}
 	chainFee := new(models.ChainFee)
 	res = db.Where("chain_id = ?", getFeeReq.DstChainId).Preload("TokenBasic").First(chainFee)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", getFeeReq.DstChainId))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("chain: %d does not have fee", getFeeReq.DstChainId))
 		return
 	}
-	//check if rank of src token is risky, if so, change the proxyFee value
-	proxyFee := new(big.Float).SetInt(&chainFee.ProxyFee.Int)
-	//check if any coin marked as dying in redis
-	if exists, _ := cacheRedis.Redis.Exists(cacheRedis.MarkTokenAsDying + token.TokenBasicName); exists {
-		logs.Info("this token is dying", token.TokenBasicName)
-		if val, err := cacheRedis.Redis.Get(cacheRedis.MarkTokenAsDying + token.TokenBasicName); err == nil {
-			manualRatio, ok := big.NewFloat(0.0).SetString(val)
-			if ok {
-				proxyFee.Mul(proxyFee, manualRatio)
-			} else {
-				logs.Error("get dying token manualRatio fail, tokenbasicname: %s", token.TokenBasicName)
-			}
-		}
-	} else {
-		if token.TokenBasic.Rank > riskyCoinRankThreshold {
-			proxyFee.Mul(proxyFee, riskyCoinRisingRate)
-		}
-	}
-	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
-	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
-	usdtFee := new(big.Float).Mul(proxyFee, new(big.Float).SetInt64(chainFee.TokenBasic.Price))
-	usdtFee = new(big.Float).Quo(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-	tokenFee := new(big.Float).Mul(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
-
 	isNftSwap := true
+	var swapToken *models.Token
 	if len(getFeeReq.SwapTokenHash) != 0 {
-		swapToken := new(models.Token)
+		swapToken = new(models.Token)
 		res := db.Where("hash = ? and chain_id = ?", getFeeReq.SwapTokenHash, getFeeReq.SrcChainId).First(swapToken)
 		if res.RowsAffected != 0 {
 			if swapToken.Standard == models.TokenTypeErc20 {
 				isNftSwap = false
 			}
+		} else {
+			swapToken = nil
 		}
 	}
-	if isNftSwap {
-		for _, cfg := range conf.GlobalConfig.FeeListenConfig {
-			if cfg.ChainId == getFeeReq.DstChainId {
-				if cfg.NftRatio > 0 {
-					nftRatio := new(big.Float).Quo(new(big.Float).SetInt64(cfg.NftRatio), new(big.Float).SetInt64(100))
-					usdtFee = new(big.Float).Mul(usdtFee, nftRatio)
-					tokenFee = new(big.Float).Mul(tokenFee, nftRatio)
-				}
-				break
-			}
-		}
+	chainFee = loadGasLimits().ChainFee(chainFee, routeProxyType(&getFeeReq, swapToken))
+	// get L1 fee of layer 2 chains
+	var ethChainFee *models.ChainFee
+	if fee.HasL1Fee(getFeeReq.DstChainId) {
+		ethChainFee = loadEthChainFee()
 	}
-	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
+	routeFee, err := calcRouteFee(token, chainFee, ethChainFee, getFeeReq.DstChainId, isNftSwap, proxyFeeRatio(token))
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("get L1 fee failed. err=%v", err))
+		return
+	}
+	usdtFee, tokenFee, tokenFeeWithPrecision := routeFee.usdtFee, routeFee.tokenFee, routeFee.tokenFeeWithPrecision
 
 	isNative := false
 	nativeTokenAmount := new(big.Float).SetInt64(0)
-	// get optimistic L1 fee on ethereum
-	if basedef.OPTIMISTIC_CROSSCHAIN_ID == getFeeReq.DstChainId {
-		ethChainFee := new(models.ChainFee)
-		res = db.Where("chain_id = ?", basedef.ETHEREUM_CROSSCHAIN_ID).Preload("TokenBasic").First(ethChainFee)
-		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", basedef.ETHEREUM_CROSSCHAIN_ID))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
-			return
-		}
-
-		_, l1UsdtFee, _, err := fee.GetL1Fee(ethChainFee, getFeeReq.DstChainId)
-		if err != nil {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("get ethereum L1 fee failed. err=%v", err))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
-			return
-		}
-
-		l1TokenFee := new(big.Float).Mul(l1UsdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-		l1TokenFee = new(big.Float).Quo(l1TokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
-		l1TokenFeeWithPrecision := new(big.Float).Mul(l1TokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
-		tokenFee = new(big.Float).Add(tokenFee, l1TokenFee)
-		tokenFeeWithPrecision = new(big.Float).Add(tokenFeeWithPrecision, l1TokenFeeWithPrecision)
+	quote, err := quoteFee(&getFeeReq, tokenFeeWithPrecision)
+	if err != nil {
+		logs.Error("quote fee of %s err: %v", getFeeReq.User, err)
 	}
 
 	{
@@ -167,9 +130,7 @@ func (c *FeeController) GetFee() {
 		res = db.Where("chain_id = ?", getFeeReq.SrcChainId).Preload("TokenBasic").
 			First(nativeChainFee)
 		if res.RowsAffected == 0 {
-			c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", getFeeReq.SrcChainId))
-			c.Ctx.ResponseWriter.WriteHeader(400)
-			c.ServeJSON()
+			apierror.Output(&c.Controller, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("chain: %d does not have fee", getFeeReq.SrcChainId))
 			return
 		}
 		preloadTokens := make([]*models.Token, 0)
@@ -183,20 +144,10 @@ func (c *FeeController) GetFee() {
 					nativeFeeAmount := new(big.Float).SetInt(&nativeChainFee.MaxFee.Int)
 					nativeFeeAmount = new(big.Float).Quo(nativeFeeAmount, new(big.Float).SetInt64(basedef.FEE_PRECISION))
 					nativeFeeAmount = new(big.Float).Quo(nativeFeeAmount, new(big.Float).SetInt64(basedef.Int64FromFigure(int(nativeChainFee.TokenBasic.Precision))))
-					if getFeeReq.SrcChainId == basedef.OPTIMISTIC_CROSSCHAIN_ID {
-						ethChainFee := new(models.ChainFee)
-						res = db.Where("chain_id = ?", basedef.ETHEREUM_CROSSCHAIN_ID).Preload("TokenBasic").First(ethChainFee)
-						if res.RowsAffected == 0 {
-							c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", basedef.ETHEREUM_CROSSCHAIN_ID))
-							c.Ctx.ResponseWriter.WriteHeader(400)
-							c.ServeJSON()
-							return
-						}
-						_, _, l1FeeAmount, err := fee.GetL1Fee(ethChainFee, getFeeReq.SrcChainId)
+					if fee.HasL1Fee(getFeeReq.SrcChainId) {
+						_, _, l1FeeAmount, err := fee.GetL1Fee(nativeChainFee, loadEthChainFee())
 						if err != nil {
-							c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("get ethereum L1 fee failed. err=%v", err))
-							c.Ctx.ResponseWriter.WriteHeader(400)
-							c.ServeJSON()
+							apierror.Output(&c.Controller, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("get L1 fee failed. err=%v", err))
 							return
 						}
 						nativeFeeAmount = new(big.Float).Add(nativeFeeAmount, l1FeeAmount)
@@ -211,13 +162,13 @@ func (c *FeeController) GetFee() {
 		res := db.Where("src_token_hash = ? and src_chain_id = ? and dst_chain_id = ?", getFeeReq.SwapTokenHash, getFeeReq.SrcChainId, getFeeReq.DstChainId).Preload("DstToken").First(tokenMap)
 		if res.RowsAffected == 0 {
 			c.Data["json"] = models.MakeGetFeeRsp(getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision,
-				getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison)
+				getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison, quote)
 			c.ServeJSON()
 			return
 		}
 		if tokenMap.DstChainId != getFeeReq.DstChainId || tokenMap.DstToken == nil {
 			c.Data["json"] = models.MakeGetFeeRsp(getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision,
-				getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison)
+				getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison, quote)
 			c.ServeJSON()
 			return
 		}
@@ -257,7 +208,7 @@ func (c *FeeController) GetFee() {
 					tokenBalance, err = cacheRedis.Redis.GetLongTokenBalance(tokenMap.SrcChainId, tokenMap.DstChainId, tokenMap.DstTokenHash)
 					if err != nil {
 						c.Data["json"] = models.MakeGetFeeRsp(getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision,
-							getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison)
+							getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison, quote)
 						c.ServeJSON()
 						return
 					}
@@ -273,23 +224,167 @@ func (c *FeeController) GetFee() {
 			}
 		}
 		c.Data["json"] = models.MakeGetFeeRsp(getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision,
-			getFeeReq.SwapTokenHash, tokenBalance, int(tokenMap.DstToken.Precision), isNative, nativeTokenAmount, feeTokenPrecison)
+			getFeeReq.SwapTokenHash, tokenBalance, int(tokenMap.DstToken.Precision), isNative, nativeTokenAmount, feeTokenPrecison, quote)
 		c.ServeJSON()
 	} else {
 		c.Data["json"] = models.MakeGetFeeRsp(getFeeReq.SrcChainId, getFeeReq.Hash, getFeeReq.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision,
-			getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison)
+			getFeeReq.SwapTokenHash, new(big.Int).SetUint64(0), 0, isNative, nativeTokenAmount, feeTokenPrecison, quote)
 		c.ServeJSON()
 	}
 }
 
+// proxyFeeRatio returns the ratio of the proxy fee charged for the token, the dying tokens are charged by their manual
+// ratios and the risky ones by riskyCoinRisingRate. It is nil for the other tokens.
+func proxyFeeRatio(token *models.Token) *big.Float {
+	//check if any coin marked as dying in redis
+	if exists, _ := cacheRedis.Redis.Exists(cacheRedis.MarkTokenAsDying + token.TokenBasicName); exists {
+		logs.Info("this token is dying", token.TokenBasicName)
+		if val, err := cacheRedis.Redis.Get(cacheRedis.MarkTokenAsDying + token.TokenBasicName); err == nil {
+			manualRatio, ok := big.NewFloat(0.0).SetString(val)
+			if ok {
+				return manualRatio
+			}
+			logs.Error("get dying token manualRatio fail, tokenbasicname: %s", token.TokenBasicName)
+		}
+		return nil
+	}
+	//check if rank of src token is risky, if so, change the proxyFee value
+	if token.TokenBasic.Rank > riskyCoinRankThreshold {
+		return riskyCoinRisingRate
+	}
+	return nil
+}
+
+// routeFee is the fee of a route charged in the fee token, the L1 fee is included in the token fee but not in the usdt fee
+type routeFee struct {
+	baseUsdtFee           *big.Float // usdt fee before the nft ratio
+	nftRatio              *big.Float // nil if it is not applied
+	usdtFee               *big.Float
+	l1UsdtFee             *big.Float
+	l1TokenFee            *big.Float
+	tokenFee              *big.Float
+	tokenFeeWithPrecision *big.Float
+}
+
+// loadGasLimits loads the calibrated gas limits, the fees are of the configured gas limits without them
+func loadGasLimits() fee.GasLimits {
+	limits, err := fee.LoadGasLimits(db)
+	if err != nil {
+		logs.Error("load gas limits err: %v", err)
+	}
+	return limits
+}
+
+// routeProxyType returns the proxy type the transaction of the route is sent to, as the checks of the fees
+// derive it from the source transaction. SwapTokenHash is the token sent on every transfer, so the route is
+// quoted as a lock, or as an nft by the standard of the token, the swap token is nil if it is not found
+func routeProxyType(route *models.GetFeeReq, swapToken *models.Token) string {
+	standard := models.TokenTypeErc20
+	if swapToken != nil {
+		standard = swapToken.Standard
+	}
+	return fee.ProxyType(route.SrcChainId, "", standard)
+}
+
+// loadEthChainFee loads the fee of ethereum for the L1 fees scaled from it, it is nil if ethereum has no fee
+func loadEthChainFee() *models.ChainFee {
+	ethChainFee := new(models.ChainFee)
+	res := db.Where("chain_id = ?", basedef.ETHEREUM_CROSSCHAIN_ID).Preload("TokenBasic").First(ethChainFee)
+	if res.RowsAffected == 0 {
+		return nil
+	}
+	return ethChainFee
+}
+
+// calcRouteFee calculates the fee to dstChainId in the token from the proxy fee of chainFee multiplied by ratio,
+// ethChainFee is the fee of ethereum for the L1 fees of the layer 2 chains scaled from it
+func calcRouteFee(token *models.Token, chainFee, ethChainFee *models.ChainFee, dstChainId uint64, isNftSwap bool, ratio *big.Float) (*routeFee, error) {
+	proxyFee := new(big.Float).SetInt(&chainFee.ProxyFee.Int)
+	if ratio != nil {
+		proxyFee.Mul(proxyFee, ratio)
+	}
+	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
+	proxyFee = new(big.Float).Quo(proxyFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
+	usdtFee := new(big.Float).Mul(proxyFee, new(big.Float).SetInt64(chainFee.TokenBasic.Price))
+	usdtFee = new(big.Float).Quo(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	tokenFee := new(big.Float).Mul(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
+
+	rf := &routeFee{baseUsdtFee: usdtFee, l1UsdtFee: new(big.Float), l1TokenFee: new(big.Float)}
+	if isNftSwap {
+		for _, cfg := range conf.GlobalConfig.FeeListenConfig {
+			if cfg.ChainId == dstChainId {
+				if cfg.NftRatio > 0 {
+					nftRatio := new(big.Float).Quo(new(big.Float).SetInt64(cfg.NftRatio), new(big.Float).SetInt64(100))
+					usdtFee = new(big.Float).Mul(usdtFee, nftRatio)
+					tokenFee = new(big.Float).Mul(tokenFee, nftRatio)
+					rf.nftRatio = nftRatio
+				}
+				break
+			}
+		}
+	}
+	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
+
+	if fee.HasL1Fee(dstChainId) {
+		_, l1UsdtFee, _, err := fee.GetL1Fee(chainFee, ethChainFee)
+		if err != nil {
+			return nil, err
+		}
+
+		l1TokenFee := new(big.Float).Mul(l1UsdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+		l1TokenFee = new(big.Float).Quo(l1TokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
+		l1TokenFeeWithPrecision := new(big.Float).Mul(l1TokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
+		tokenFee = new(big.Float).Add(tokenFee, l1TokenFee)
+		tokenFeeWithPrecision = new(big.Float).Add(tokenFeeWithPrecision, l1TokenFeeWithPrecision)
+		rf.l1UsdtFee, rf.l1TokenFee = l1UsdtFee, l1TokenFee
+	}
+	rf.usdtFee, rf.tokenFee, rf.tokenFeeWithPrecision = usdtFee, tokenFee, tokenFeeWithPrecision
+	return rf, nil
+}
+
+// quoteFee issues a quote of the fee to the user of the request, it is nil if the user is not given or the fees are not quoted
+func quoteFee(getFeeReq *models.GetFeeReq, tokenFeeWithPrecision *big.Float) (*models.FeeQuote, error) {
+	if getFeeReq.User == "" || !fee.QuoteEnabled() {
+		return nil, nil
+	}
+	amount, ok := new(big.Int).SetString(fmt.Sprintf("%.*f", 0, tokenFeeWithPrecision), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid fee amount %v", tokenFeeWithPrecision)
+	}
+	quote, err := fee.NewQuote(getFeeReq.User, getFeeReq.SrcChainId, getFeeReq.DstChainId, getFeeReq.Hash, amount, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Create(quote).Error; err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// quotedFee returns the quote honoured by the wrapper transaction, nil if there is none
+func quotedFee(wrapperTransaction *models.WrapperTransactionWithToken) *models.FeeQuote {
+	quote, err := fee.QuotedFee(db, &models.WrapperTransaction{
+		User:         wrapperTransaction.User,
+		SrcChainId:   wrapperTransaction.SrcChainId,
+		DstChainId:   wrapperTransaction.DstChainId,
+		FeeTokenHash: wrapperTransaction.FeeTokenHash,
+		FeeAmount:    wrapperTransaction.FeeAmount,
+		Time:         wrapperTransaction.Time,
+	})
+	if err != nil {
+		logs.Error("get fee quote of %s err: %v", wrapperTransaction.Hash, err)
+	}
+	return quote
+}
+
 func (c *FeeController) CheckFee() {
 	logs.Debug("check fee request: %s", string(c.Ctx.Input.RequestBody))
 	var checkFeesReq models.CheckFeesReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &checkFeesReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	checkFeesReq4Nomal := make([]*models.CheckFeeReq, 0)
 	checkFeesReq4O3 := make([]*models.CheckFeeReq, 0)
@@ -324,7 +419,7 @@ func (c *FeeController) checkFee(Checks []*models.CheckFeeReq) []*models.CheckFe
 		requestHashs = append(requestHashs, basedef.HexStringReverse(check.Hash))
 	}
 	srcTransactions := make([]*models.SrcTransaction, 0)
-	db.Model(&models.SrcTransaction{}).Where("(`key` in ? or `hash` in ?)", requestHashs, requestHashs).Find(&srcTransactions)
+	db.Model(&models.SrcTransaction{}).Where("(src_transactions.key in ? or hash in ?)", requestHashs, requestHashs).Find(&srcTransactions)
 	key2Txhash := make(map[string]string, 0)
 	isPolyProxy := make(map[string]bool, 0)
 
@@ -426,6 +521,9 @@ func (c *FeeController) checkFee(Checks []*models.CheckFeeReq) []*models.CheckFe
 
 		if feePay.Cmp(feeMin) >= 0 {
 			checkFee.PayState = 1
+		} else if quote := quotedFee(wrapperTransactionWithToken); quote != nil {
+			checkFee.PayState = 1
+			logs.Info("check fee ChainId:%v Hash:%v feePay:%v < feeMin:%v, paid as quote %s", check.ChainId, check.Hash, feePay, feeMin, quote.Id)
 		} else {
 			checkFee.PayState = -1
 			logs.Info("check fee PayState = -1 ChainId:%v Hash:%v feePay:%v < feeMin:%v", check.ChainId, check.Hash, feePay, feeMin)
@@ -464,7 +562,7 @@ func (c *FeeController) CheckSwapFee(Checks []*models.CheckFeeReq) []*models.Che
 		requestHashs = append(requestHashs, basedef.HexStringReverse(check.Hash))
 	}
 	srcTransactions := make([]*models.SrcTransaction, 0)
-	db.Model(&models.SrcTransaction{}).Where("(`key` in ? or `hash` in ?)", requestHashs, requestHashs).Find(&srcTransactions)
+	db.Model(&models.SrcTransaction{}).Where("(src_transactions.key in ? or hash in ?)", requestHashs, requestHashs).Find(&srcTransactions)
 	key2Txhash := make(map[string]string, 0)
 	o3Hashs := make([]string, 0)
 	for _, srcTransaction := range srcTransactions {
@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"fmt"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+)
+
+const (
+	feeHistoryCount    = 100
+	feeHistoryMaxCount = 1000
+)
+
+// FeeHistory gets the samples of the fees of the chain read by the fee listen with the fees published from them,
+// latest first, optionally between the start and the end times
+func (c *FeeController) FeeHistory() {
+	chainId, err := c.GetUint64("chainId")
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	count, _ := c.GetInt("count", feeHistoryCount)
+	if count <= 0 || count > feeHistoryMaxCount {
+		count = feeHistoryCount
+	}
+	query := db.Where("chain_id = ?", chainId)
+	if start, _ := c.GetInt64("start"); start > 0 {
+		query = query.Where("time >= ?", start)
+	}
+	if end, _ := c.GetInt64("end"); end > 0 {
+		query = query.Where("time <= ?", end)
+	}
+	histories := make([]*models.ChainFeeHistory, 0)
+	if err = query.Order("time desc, id desc").Limit(count).Find(&histories).Error; err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get fee history of chain %d err: %v", chainId, err))
+		return
+	}
+	c.Data["json"] = &models.FeeHistoryRsp{ChainId: chainId, Histories: histories}
+	c.ServeJSON()
+}
@@ -2,13 +2,13 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/beego/beego/v2/core/logs"
 	"math/big"
 	"poly-bridge/basedef"
 	"poly-bridge/cacheRedis"
 	"poly-bridge/conf"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"poly-bridge/utils/decimal"
 	"poly-bridge/utils/fee"
 	"strings"
@@ -27,9 +27,7 @@ func (c *FeeController) NewCheckFee() {
 	var mapCheckFeesReq map[string]*models.CheckFeeRequest
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &mapCheckFeesReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	srcHashs := make([]string, 0)
@@ -66,6 +64,7 @@ func (c *FeeController) NewCheckFee() {
 	for _, chainFee := range chainFees {
 		chain2Fees[chainFee.ChainId] = chainFee
 	}
+	gasLimits := loadGasLimits()
 	for k, v := range mapCheckFeesReq {
 		//check fee from cache（special case）
 		if v.SrcTransaction != nil {
@@ -114,18 +113,15 @@ func (c *FeeController) NewCheckFee() {
 				logs.Info("find no fee token", k)
 				continue
 			}
+			if v.SrcTransaction != nil {
+				proxyType := fee.ProxyType(v.SrcTransaction.ChainId, v.SrcTransaction.Contract, v.SrcTransaction.Standard)
+				chainFee = gasLimits.ChainFee(chainFee, proxyType)
+			}
 			feePay, feeMin, gasPay := fee.CheckFeeCal(chainFee, v.WrapperTransactionWithToken.FeeToken, v.WrapperTransactionWithToken.FeeAmount)
 
-			// get optimistic L1 fee on ethereum
-			if chainFee.ChainId == basedef.OPTIMISTIC_CROSSCHAIN_ID {
-				ethChainFee, ok := chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID]
-				if !ok {
-					v.Status = NOT_PAID
-					logs.Info("check fee poly_hash %s NOT_PAID,chainFee hasn't ethereum fee", k)
-					continue
-				}
-
-				L1MinFee, _, _, err := fee.GetL1Fee(ethChainFee, chainFee.ChainId)
+			// get L1 fee of layer 2 chains
+			if fee.HasL1Fee(chainFee.ChainId) {
+				L1MinFee, _, _, err := fee.GetL1Fee(chainFee, chain2Fees[basedef.ETHEREUM_CROSSCHAIN_ID])
 				if err != nil {
 					v.Status = NOT_PAID
 					logs.Info("check fee poly_hash %s NOT_PAID, get L1 fee failed. err=%v", k, err)
@@ -186,18 +182,18 @@ func checkFeeSrcTransaction(chainId uint64, txId string) (*models.SrcTransaction
 	transaction := new(models.SrcTransaction)
 	if strings.Contains(txId, "00000000") {
 		res := db.Model(&models.SrcTransaction{}).
-			Where("chain_id=? and `key` =?", chainId, txId).
+			Where("chain_id=? and src_transactions.key =?", chainId, txId).
 			First(transaction)
 		if res.Error != nil {
 			return nil, res.Error
 		}
 	} else {
 		res := db.Model(&models.SrcTransaction{}).
-			Where("chain_id=? and `hash` =?", chainId, txId).
+			Where("chain_id=? and hash =?", chainId, txId).
 			First(transaction)
 		if res.Error != nil {
 			res := db.Model(&models.SrcTransaction{}).
-				Where("chain_id=? and `hash` =?", chainId, basedef.HexStringReverse(txId)).First(transaction)
+				Where("chain_id=? and hash =?", chainId, basedef.HexStringReverse(txId)).First(transaction)
 			if res.Error != nil {
 				return nil, res.Error
 			}
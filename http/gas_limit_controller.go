@@ -0,0 +1,37 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"fmt"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+	"poly-bridge/utils/fee"
+)
+
+// GasLimits reports the gas limits the fees of the proxies on the chains are of against the gas used by their unlocks
+func (c *FeeController) GasLimits() {
+	limits, err := fee.LoadGasLimits(db)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("load gas limits err: %v", err))
+		return
+	}
+	c.Data["json"] = &models.GasLimitsRsp{GasLimits: limits.Report()}
+	c.ServeJSON()
+}
@@ -18,12 +18,11 @@
 package http
 
 import (
-	"fmt"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 )
 
 var db *gorm.DB
@@ -37,9 +36,8 @@ func Init() {
 		Logger = Logger.LogMode(logger.Info)
 	}
 
-	conn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", config.User, config.Password, config.URL, config.Scheme)
 	var err error
-	db, err = gorm.Open(mysql.Open(conn), &gorm.Config{Logger: Logger})
+	db, err = dbconn.Open(config, Logger)
 	if err != nil {
 		panic(err)
 	}
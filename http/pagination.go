@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/beego/beego/v2/core/logs"
+	"gorm.io/gorm"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/models"
+)
+
+// paginate pages the query ordered by time and id descending, after the cursor when there is one or by the page number
+func paginate(query *gorm.DB, table string, cursor *models.PageCursor, pageSize int, pageNo int) *gorm.DB {
+	query = query.Order(table + ".time desc").Order(table + ".id desc").Limit(pageSize)
+	if cursor == nil {
+		return query.Offset(pageSize * pageNo)
+	}
+	return query.Where(table+".time < ? or ("+table+".time = ? and "+table+".id < ?)", cursor.Time, cursor.Time, cursor.Id)
+}
+
+// paginateById pages the query ordered by id descending, after the cursor when there is one or by the page number
+func paginateById(query *gorm.DB, table string, cursor *models.PageCursor, pageSize int, pageNo int) *gorm.DB {
+	query = query.Order(table + ".id desc").Limit(pageSize)
+	if cursor == nil {
+		return query.Offset(pageSize * pageNo)
+	}
+	return query.Where(table+".id < ?", cursor.Id)
+}
+
+// countTransactions counts the transactions of a list with the filter, the count is cached for a minute and shared by all pages
+func countTransactions(list string, filter interface{}, count func(total *int64) *gorm.DB) int64 {
+	data, _ := json.Marshal(filter)
+	hash := sha256.Sum256(data)
+	key := list + "_" + hex.EncodeToString(hash[:8])
+	if cacheRedis.Redis != nil {
+		if total, err := cacheRedis.Redis.GetTransactionCount(key); err == nil {
+			return total
+		}
+	}
+	var total int64
+	if err := count(&total).Error; err != nil {
+		logs.Error("count transactions of %s err: %v", list, err)
+		return 0
+	}
+	if cacheRedis.Redis != nil {
+		if err := cacheRedis.Redis.SetTransactionCount(key, total); err != nil {
+			logs.Error(err)
+		}
+	}
+	return total
+}
+
+func totalPage(totalCount int64, pageSize int) int {
+	if pageSize <= 0 {
+		return 0
+	}
+	return (int(totalCount) + pageSize - 1) / pageSize
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/beego/beego/v2/server/web"
+	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 )
 
 type PolyNftController struct {
@@ -15,9 +17,8 @@ func (c *PolyNftController) GetNftSign() {
 	var nftSignReq models.NftSignReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &nftSignReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	if nftSignReq.Address[:2] == "0x" || nftSignReq.Address[:2] == "0X" {
 		nftSignReq.Address = nftSignReq.Address[2:]
@@ -27,9 +28,7 @@ func (c *PolyNftController) GetNftSign() {
 	res := db.Where("col_address = ? ", nftSignReq.Address).
 		First(colUser)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("%v does not exist", nftSignReq.Address))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, fmt.Sprintf("%v does not exist", nftSignReq.Address))
 		return
 	}
 	nftUsers = append(nftUsers, colUser)
@@ -0,0 +1,244 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"github.com/beego/beego/v2/server/web"
+	"github.com/beego/beego/v2/server/web/context"
+)
+
+const (
+	apiKeyHeader = "X-Api-Key"
+	// the in-process buckets are swept every bucketSweep or once they reach maxBuckets
+	bucketSweep = time.Minute
+	maxBuckets  = 100000
+)
+
+// bucket is a token bucket of a client in a route group
+type bucket struct {
+	tokens float64
+	time   time.Time
+	full   time.Time // when the bucket is refilled to its burst, it is the same as a new one since then
+}
+
+// take refills the bucket until now and takes a token of it
+func (b *bucket) take(quota *conf.RateLimitQuota, now time.Time) bool {
+	if b.time.IsZero() {
+		b.tokens = float64(quota.Burst)
+		b.time = now
+	}
+	if now.After(b.time) {
+		b.tokens = math.Min(float64(quota.Burst), b.tokens+now.Sub(b.time).Seconds()*quota.Rate)
+		b.time = now
+	}
+	taken := b.tokens >= 1
+	if taken {
+		b.tokens--
+	}
+	if quota.Rate > 0 {
+		b.full = now.Add(time.Duration((float64(quota.Burst) - b.tokens) / quota.Rate * float64(time.Second)))
+	}
+	return taken
+}
+
+// rateLimiter enforces the quotas of the clients in the route groups, the buckets are kept in redis
+// and in the process when redis is not available
+type rateLimiter struct {
+	config  *conf.RateLimitConfig
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+func newRateLimiter(config *conf.RateLimitConfig) *rateLimiter {
+	for _, group := range config.Groups {
+		normalizeQuota(&group.RateLimitQuota)
+	}
+	for _, apiKey := range config.ApiKeys {
+		for _, quota := range apiKey.Quotas {
+			normalizeQuota(quota)
+		}
+	}
+	return &rateLimiter{
+		config:  config,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func normalizeQuota(quota *conf.RateLimitQuota) {
+	if quota != nil && quota.Burst <= 0 {
+		quota.Burst = int64(math.Max(1, math.Ceil(quota.Rate)))
+	}
+}
+
+// RateLimit is the filter rejecting the requests over the quotas of their clients with 429
+func RateLimit(config *conf.RateLimitConfig) web.FilterFunc {
+	return newRateLimiter(config).filter
+}
+
+func (l *rateLimiter) group(path string) *conf.RateLimitGroup {
+	for _, group := range l.config.Groups {
+		if len(group.Routes) == 0 {
+			return group
+		}
+		for _, route := range group.Routes {
+			if strings.HasPrefix(path, route) {
+				return group
+			}
+		}
+	}
+	return nil
+}
+
+func (l *rateLimiter) apiKey(key string) *conf.ApiKeyConfig {
+	var found *conf.ApiKeyConfig
+	for _, apiKey := range l.config.ApiKeys {
+		if apiKey.Key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey.Key)) == 1 {
+			found = apiKey
+		}
+	}
+	return found
+}
+
+// clientIP returns the remote address, or with TrustProxy the address X-Forwarded-For has before the hops
+// of the trusted proxies. The entries before it are sent by the client and can be forged.
+func (l *rateLimiter) clientIP(ctx *context.Context) string {
+	if l.config.TrustProxy {
+		hops := l.config.ProxyHops
+		if hops <= 0 {
+			hops = 1
+		}
+		forwarded := make([]string, 0)
+		for _, header := range ctx.Request.Header.Values("X-Forwarded-For") {
+			for _, ip := range strings.Split(header, ",") {
+				if ip = strings.TrimSpace(ip); ip != "" {
+					forwarded = append(forwarded, ip)
+				}
+			}
+		}
+		if len(forwarded) >= hops {
+			return forwarded[len(forwarded)-hops]
+		}
+	}
+	ip, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+	if err != nil {
+		return ctx.Request.RemoteAddr
+	}
+	return ip
+}
+
+// sweep drops the in-process buckets refilled to their bursts, and all of them if more than half of
+// maxBuckets are still in use, so a flood of clients does not sweep them on every request
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+	if len(l.buckets) > maxBuckets/2 {
+		logs.Warn("drop %d rate limit buckets in process", len(l.buckets))
+		l.buckets = make(map[string]*bucket)
+	}
+	l.swept = now
+}
+
+// take takes a token of the bucket of key, of redis or of the process when redis fails
+func (l *rateLimiter) take(key string, quota *conf.RateLimitQuota) (bool, float64) {
+	now := l.now()
+	if cacheRedis.Redis != nil {
+		taken, tokens, err := cacheRedis.Redis.TakeToken(key, quota.Rate, quota.Burst, now)
+		if err == nil {
+			return taken, tokens
+		}
+		logs.Error("take rate limit token of %s err: %v", key, err)
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	b, ok := l.buckets[key]
+	if !ok && (len(l.buckets) >= maxBuckets || now.Sub(l.swept) >= bucketSweep) {
+		l.sweep(now)
+	}
+	if !ok {
+		b = new(bucket)
+		l.buckets[key] = b
+	}
+	taken := b.take(quota, now)
+	return taken, b.tokens
+}
+
+func (l *rateLimiter) filter(ctx *context.Context) {
+	if ctx.Input.Method() == http.MethodOptions {
+		return
+	}
+	group := l.group(ctx.Request.URL.Path)
+	if group == nil {
+		return
+	}
+	quota := &group.RateLimitQuota
+	client := ""
+	key := ctx.Input.Header(apiKeyHeader)
+	if key == "" {
+		key = ctx.Input.Query("apikey")
+	}
+	if key != "" {
+		apiKey := l.apiKey(key)
+		if apiKey == nil {
+			ctx.Output.SetStatus(http.StatusUnauthorized)
+			ctx.Output.JSON(models.MakeErrorCodeRsp(basedef.ERROR_INVALID_API_KEY, "api key is invalid"), false, false)
+			return
+		}
+		client = "key:" + apiKey.Client
+		if q, ok := apiKey.Quotas[group.Name]; ok && q != nil {
+			quota = q
+		}
+	} else {
+		client = "ip:" + l.clientIP(ctx)
+	}
+	if quota.Rate <= 0 {
+		return
+	}
+	taken, tokens := l.take(fmt.Sprintf("%s_%s", group.Name, client), quota)
+	ctx.Output.Header("X-RateLimit-Limit", strconv.FormatInt(quota.Burst, 10))
+	ctx.Output.Header("X-RateLimit-Remaining", strconv.FormatInt(int64(tokens), 10))
+	if !taken {
+		retryAfter := int64(math.Ceil((1 - tokens) / quota.Rate))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		ctx.Output.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+		ctx.Output.SetStatus(http.StatusTooManyRequests)
+		ctx.Output.JSON(models.MakeRateLimitedRsp(group.Name, retryAfter), false, false)
+	}
+}
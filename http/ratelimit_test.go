@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/server/web/context"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+)
+
+func TestRateLimit(t *testing.T) {
+	limiter := newRateLimiter(&conf.RateLimitConfig{
+		Groups: []*conf.RateLimitGroup{
+			{Name: "heavy", Routes: []string{"/v1/bridge/transactions/", "/v1/bridge/gettokenasset/"}, RateLimitQuota: conf.RateLimitQuota{Rate: 1, Burst: 2}},
+			{Name: "default", RateLimitQuota: conf.RateLimitQuota{Rate: 100}},
+		},
+		ApiKeys: []*conf.ApiKeyConfig{
+			{Client: "wallet", Key: "wallet-key", Quotas: map[string]*conf.RateLimitQuota{"heavy": {Rate: 10, Burst: 5}}},
+		},
+	})
+	now := time.Unix(1610695305, 0)
+	limiter.now = func() time.Time { return now }
+	request := func(path, remoteAddr, apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		r.RemoteAddr = remoteAddr
+		if apiKey != "" {
+			r.Header.Set(apiKeyHeader, apiKey)
+		}
+		ctx := context.NewContext()
+		ctx.Reset(w, r)
+		limiter.filter(ctx)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("/v1/bridge/transactions/", "1.1.1.1:1000", "").Code)
+	assert.Equal(t, http.StatusOK, request("/v1/bridge/gettokenasset/", "1.1.1.1:1001", "").Code)
+	w := request("/v1/bridge/transactions/", "1.1.1.1:1002", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	rsp := new(models.RateLimitedRsp)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rsp))
+	assert.Equal(t, basedef.ERROR_RATE_LIMITED, rsp.Code)
+	assert.Equal(t, "heavy", rsp.Group)
+	assert.Equal(t, int64(1), rsp.RetryAfter)
+
+	// the buckets are by client and by group
+	assert.Equal(t, http.StatusOK, request("/v1/bridge/transactions/", "2.2.2.2:1000", "").Code)
+	assert.Equal(t, http.StatusOK, request("/v1/bridge/tokens/", "1.1.1.1:1003", "").Code)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, request("/v1/bridge/transactions/", "1.1.1.1:1004", "wallet-key").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, request("/v1/bridge/transactions/", "3.3.3.3:1000", "wallet-key").Code)
+	w = request("/v1/bridge/transactions/", "1.1.1.1:1005", "unknown-key")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), basedef.ERROR_INVALID_API_KEY)
+
+	// the buckets are refilled at their rates
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, request("/v1/bridge/transactions/", "1.1.1.1:1006", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request("/v1/bridge/transactions/", "1.1.1.1:1007", "").Code)
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	clientIP := func(config *conf.RateLimitConfig, forwarded ...string) string {
+		r := httptest.NewRequest(http.MethodPost, "/v1/bridge/transactions/", nil)
+		r.RemoteAddr = "10.0.0.1:1000"
+		for _, header := range forwarded {
+			r.Header.Add("X-Forwarded-For", header)
+		}
+		ctx := context.NewContext()
+		ctx.Reset(httptest.NewRecorder(), r)
+		return newRateLimiter(config).clientIP(ctx)
+	}
+	assert.Equal(t, "10.0.0.1", clientIP(&conf.RateLimitConfig{}, "1.1.1.1"))
+	// the entries forged by the client are skipped
+	assert.Equal(t, "1.1.1.1", clientIP(&conf.RateLimitConfig{TrustProxy: true}, "9.9.9.9, 1.1.1.1"))
+	assert.Equal(t, "1.1.1.1", clientIP(&conf.RateLimitConfig{TrustProxy: true, ProxyHops: 2}, "9.9.9.9, 1.1.1.1", "10.0.0.2"))
+	assert.Equal(t, "10.0.0.1", clientIP(&conf.RateLimitConfig{TrustProxy: true, ProxyHops: 2}, "1.1.1.1"))
+}
+
+func TestRateLimitSweep(t *testing.T) {
+	limiter := newRateLimiter(&conf.RateLimitConfig{})
+	now := time.Unix(1610695305, 0)
+	limiter.now = func() time.Time { return now }
+	quota := &conf.RateLimitQuota{Rate: 1, Burst: 100}
+	limiter.take("default_ip:1.1.1.1", quota)
+	for i := 0; i < 100; i++ {
+		limiter.take("default_ip:2.2.2.2", quota)
+	}
+	assert.Len(t, limiter.buckets, 2)
+
+	// the bucket refilled is dropped, the one still refilling is kept
+	now = now.Add(bucketSweep)
+	limiter.take("default_ip:3.3.3.3", quota)
+	assert.Len(t, limiter.buckets, 2)
+	assert.Contains(t, limiter.buckets, "default_ip:2.2.2.2")
+	taken, _ := limiter.take("default_ip:2.2.2.2", quota)
+	assert.True(t, taken)
+}
@@ -19,7 +19,10 @@ package http
 
 import (
 	"github.com/beego/beego/v2/server/web"
+	"poly-bridge/basedef"
 	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/utils/apispec"
 )
 
 func GetRouter(config *conf.Config) web.LinkNamespace {
@@ -30,9 +33,13 @@ func GetRouter(config *conf.Config) web.LinkNamespace {
 		web.NSRouter("/tokens/", &TokenController{}, "post:Tokens"),
 		web.NSRouter("/tokenbasics/", &TokenController{}, "post:TokenBasics"),
 		web.NSRouter("/tokenbasicsinfo/", &TokenController{}, "post:TokenBasicsInfo"),
+		web.NSRouter("/tokenpricehistory/", &TokenController{}, "post:TokenPriceHistory"),
 		web.NSRouter("/tokenmap/", &TokenMapController{}, "post:TokenMap"),
 		web.NSRouter("/tokenmapreverse/", &TokenMapController{}, "post:TokenMapReverse"),
 		web.NSRouter("/getfee/", &FeeController{}, "post:GetFee"),
+		web.NSRouter("/getfees/", &FeeController{}, "post:GetFees"),
+		web.NSRouter("/gaslimits/", &FeeController{}, "get:GasLimits"),
+		web.NSRouter("/feehistory/", &FeeController{}, "get:FeeHistory"),
 		web.NSRouter("/checkfee/", &FeeController{}, "post:CheckFee"),
 		web.NSRouter("/newcheckfee/", &FeeController{}, "post:NewCheckFee"),
 		web.NSRouter("/checkswapfee/", &FeeController{}, "post:CheckSwapFee"),
@@ -45,13 +52,59 @@ func GetRouter(config *conf.Config) web.LinkNamespace {
 		web.NSRouter("/transactionsofunfinished/", &TransactionController{}, "post:TransactionsOfUnfinished"),
 		web.NSRouter("/transactionsofasset/", &TransactionController{}, "post:TransactionsOfAsset"),
 		web.NSRouter("/transactionswithoutwrapper/", &TransactionController{}, "post:TransactionsWithoutWrapper"),
+		web.NSRouter("/transactionstatusstream/", &TransactionController{}, "get:TransactionStatusStream"),
 		web.NSRouter("/expecttime/", &StatisticController{}, "post:ExpectTime"),
 		web.NSRouter("/gettokenasset/", &TokenAssetController{}, "post:Gettokenasset"),
 		web.NSRouter("/getmanualtxdata/", &TransactionController{}, "post:GetManualTxData"),
 		web.NSRouter("/chainhealth/", &ChainHealthController{}, "post:Health"),
+		web.NSRouter("/circuitbreaker/", &CircuitBreakerController{}, "get:CircuitBreaks;post:CircuitBreak"),
+		web.NSRouter("/webhook/", &WebhookController{}, "get:Webhooks;post:Register;delete:Remove"),
+		web.NSRouter("/webhookdeliveries/", &WebhookController{}, "get:Deliveries"),
 		web.NSRouter("/wrappercheck/", &WrapperController{}, "post:WrapperCheck"),
 		web.NSRouter("/airdropofaddress/", &AirDropController{}, "post:AirDropOfAddress"),
 		web.NSRouter("/airdropclaim/", &AirDropController{}, "post:AirDropClaim"),
 	)
 	return ns
 }
+
+// ApiRoutes documents the routes of GetRouter in the OpenAPI document, their request bodies are validated
+// with the models before they reach the controllers
+var ApiRoutes = []*apispec.Route{
+	{Method: "get", Path: "/", Summary: "wrapper contracts of the chains", Response: models.PolyBridgeResp{}},
+	{Method: "post", Path: "/token/", Summary: "token of the hash on the chain", Request: models.TokenReq{}, Response: models.TokenRsp{}},
+	{Method: "post", Path: "/tokens/", Summary: "tokens of the chain", Request: models.TokensReq{}, Response: models.TokensRsp{}},
+	{Method: "post", Path: "/tokenbasics/", Summary: "token basics with their tokens", Request: models.TokenBasicReq{}, Response: models.TokenBasicsRsp{}},
+	{Method: "post", Path: "/tokenbasicsinfo/", Summary: "token basics with their prices and tvl", Request: models.TokenBasicsInfoReq{}, Response: models.TokenBasicsInfoRsp{}},
+	{Method: "post", Path: "/tokenpricehistory/", Summary: "price candles of the token basic or its market", Request: models.TokenPriceHistoryReq{}, Response: models.TokenPriceHistoryRsp{}},
+	{Method: "post", Path: "/tokenmap/", Summary: "destination tokens of the token", Request: models.TokenMapReq{}, Response: models.TokenMapsRsp{}},
+	{Method: "post", Path: "/tokenmapreverse/", Summary: "source tokens of the token", Request: models.TokenMapReq{}, Response: models.TokenMapsRsp{}},
+	{Method: "post", Path: "/getfee/", Summary: "fee of the transfer of the token to the destination chain", Request: models.GetFeeReq{}, Response: models.GetFeeRsp{}},
+	{Method: "post", Path: "/getfees/", Summary: "fees of the transfers of the tokens on many routes", Request: models.GetFeesReq{}, Response: models.GetFeesRsp{}},
+	{Method: "get", Path: "/gaslimits/", Summary: "configured and observed gas limits of the proxies on the chains", Response: models.GasLimitsRsp{}},
+	{Method: "get", Path: "/feehistory/", Summary: "samples of the fees of the chain with the fees published", Query: []string{"chainId", "start", "end", "count"}, Response: models.FeeHistoryRsp{}},
+	{Method: "post", Path: "/checkfee/", Summary: "whether the fees of the transactions are paid", Request: models.CheckFeesReq{}, Response: models.CheckFeesRsp{}},
+	{Method: "post", Path: "/newcheckfee/", Summary: "whether the fees of the transactions are paid, by the keys of the request", Request: map[string]*models.CheckFeeRequest{}, Response: map[string]*models.CheckFeeRequest{}},
+	{Method: "post", Path: "/transactions/", Summary: "wrapper transactions", Request: models.WrapperTransactionsReq{}, Response: models.WrapperTransactionsRsp{}},
+	{Method: "post", Path: "/transactionswithfilter/", Summary: "transactions of the addresses filtered by chains, assets and contracts", Request: models.TransactionsOfAddressWithFilterReq{}, Response: models.TransactionsOfAddressRsp{}},
+	{Method: "post", Path: "/transactionsofaddress/", Summary: "transactions of the addresses", Request: models.TransactionsOfAddressReq{}, Response: models.TransactionsOfAddressRsp{}},
+	{Method: "post", Path: "/transactionofhash/", Summary: "transaction of the hash", Request: models.TransactionOfHashReq{}, Response: models.TransactionRsp{}},
+	{Method: "post", Path: "/transactionofcurve/", Summary: "curve transaction of the hash", Request: models.TransactionOfHashReq{}, Response: models.TransactionRsp{}},
+	{Method: "post", Path: "/transactionsofstate/", Summary: "wrapper transactions of the state", Request: models.TransactionsOfStateReq{}, Response: models.WrapperTransactionsRsp{}},
+	{Method: "post", Path: "/transactionsofunfinished/", Summary: "unfinished transactions", Request: models.TransactionsOfUnfinishedReq{}, Response: models.TransactionOfUnfinishedRsp{}},
+	{Method: "post", Path: "/transactionsofasset/", Summary: "transactions of the asset", Request: models.TransactionsOfAssetReq{}, Response: models.TransactionOfUnfinishedRsp{}},
+	{Method: "post", Path: "/transactionswithoutwrapper/", Summary: "source transactions without wrapper transactions", Request: models.TxWithoutWrapperReq{}, Response: models.TxWithoutWrapperRes{}},
+	{Method: "get", Path: "/transactionstatusstream/", Summary: "server-sent events of the status changes of the hashes and addresses", Query: []string{"hash", "address"}},
+	{Method: "post", Path: "/expecttime/", Summary: "expected time of the transfer between the chains", Request: models.ExpectTimeReq{}, Response: models.ExpectTimeRsp{}},
+	{Method: "post", Path: "/gettokenasset/", Summary: "balances and total supplies of the token", Request: models.TokenAssetReq{}, Response: []*models.AssetDetailRes{}},
+	{Method: "post", Path: "/getmanualtxdata/", Summary: "data of the manual relay of the poly transaction", Request: models.ManualTxDataReq{}, Response: models.ManualTxDataResp{}},
+	{Method: "post", Path: "/chainhealth/", Summary: "health of the chains", Request: models.ChainHealthReq{}, Response: models.ChainHealthRsp{}},
+	{Method: "get", Path: "/circuitbreaker/", Summary: "paused chains, routes and tokens", Query: []string{"token"}, Response: models.CircuitBreakRsp{}},
+	{Method: "post", Path: "/circuitbreaker/", Summary: "pause or resume a chain, route or token", Query: []string{"token"}, Request: models.CircuitBreakReq{}, Response: basedef.CircuitBreak{}},
+	{Method: "get", Path: "/webhook/", Summary: "registered webhooks", Query: []string{"token"}, Response: []*models.WebhookRsp{}},
+	{Method: "post", Path: "/webhook/", Summary: "register a webhook", Query: []string{"token"}, Request: models.WebhookReq{}, Response: models.WebhookRsp{}},
+	{Method: "delete", Path: "/webhook/", Summary: "remove a webhook", Query: []string{"token", "id"}, Response: models.WebhookRsp{}},
+	{Method: "get", Path: "/webhookdeliveries/", Summary: "last deliveries of a webhook", Query: []string{"token", "id", "count"}, Response: models.WebhookDeliveriesRsp{}},
+	{Method: "post", Path: "/wrappercheck/", Summary: "wrapper contracts of the chain", Request: models.WrapperCheckReq{}, Response: models.WrapperCheckRsp{}},
+	{Method: "post", Path: "/airdropofaddress/", Summary: "airdrops of the users", Request: models.AirDropReq{}, Response: models.AirDropRsp{}},
+	{Method: "post", Path: "/airdropclaim/", Summary: "claimed airdrop nfts of the addresses", Request: models.AirDropClaimReq{}, Response: models.AirDropClaimRsp{}},
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/beego/beego/v2/server/web"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/conf"
+)
+
+func TestApiRoutes(t *testing.T) {
+	web.AddNamespace(web.NewNamespace("/v1", GetRouter(&conf.Config{})))
+	documented := make(map[string]bool)
+	for _, route := range ApiRoutes {
+		documented[strings.ToUpper(route.Method)+" /v1/bridge"+route.Path] = true
+	}
+	methods := regexp.MustCompile(`([A-Z*]+):`)
+	tree := web.BeeApp.PrintTree()["Data"].(web.M)
+	for method, routers := range tree {
+		for _, router := range *routers.(*[][]string) {
+			pattern := router[0]
+			if !strings.HasPrefix(pattern, "/v1/bridge/") || pattern == "/v1/bridge/checkswapfee/" {
+				continue
+			}
+			for _, m := range methods.FindAllStringSubmatch(router[1], -1) {
+				if m[1] == method || (m[1] == "*" && method == "GET") {
+					assert.True(t, documented[method+" "+pattern], "%s %s is not documented", method, pattern)
+				}
+			}
+		}
+	}
+	assert.True(t, len(tree) > 0)
+}
@@ -19,9 +19,9 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 
 	"github.com/beego/beego/v2/server/web"
 )
@@ -34,9 +34,8 @@ func (c *StatisticController) ExpectTime() {
 	var expectTimeReq models.ExpectTimeReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &expectTimeReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	var expectTime models.TimeStatistic
 	db.Where("src_chain_id = ? and dst_chain_id = ?", expectTimeReq.SrcChainId, expectTimeReq.DstChainId).First(&expectTime)
@@ -2,10 +2,11 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"math/big"
+	"poly-bridge/basedef"
 	"poly-bridge/common"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"time"
 
 	"github.com/beego/beego/v2/core/logs"
@@ -20,9 +21,8 @@ func (c *TokenAssetController) Gettokenasset() {
 	var tokenAssetReq models.TokenAssetReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenAssetReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	tokenBasics := make([]*models.TokenBasic, 0)
 	if len(tokenAssetReq.NameOrHash) == 40 {
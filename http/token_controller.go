@@ -20,7 +20,10 @@ package http
 import (
 	"encoding/json"
 	"fmt"
+	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+	"time"
 
 	"github.com/beego/beego/v2/server/web"
 )
@@ -33,9 +36,8 @@ func (c *TokenController) Tokens() {
 	var tokensReq models.TokensReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokensReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	tokens := make([]*models.Token, 0)
 	db.Where("chain_id = ? and standard = 0", tokensReq.ChainId).Preload("TokenBasic").Preload("TokenMaps").Preload("TokenMaps.DstToken").Find(&tokens)
@@ -47,16 +49,13 @@ func (c *TokenController) Token() {
 	var tokenReq models.TokenReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	token := new(models.Token)
 	res := db.Where("hash = ? and chain_id = ? and standard = 0", tokenReq.Hash, tokenReq.ChainId).Preload("TokenBasic").Preload("TokenMaps").Preload("TokenMaps.DstToken").First(token)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token: (%s,%d) does not exist", tokenReq.Hash, tokenReq.ChainId))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TOKEN_NOT_FOUND, fmt.Sprintf("token: (%s,%d) does not exist", tokenReq.Hash, tokenReq.ChainId))
 		return
 	}
 	c.Data["json"] = models.MakeTokenRsp(token)
@@ -67,9 +66,8 @@ func (c *TokenController) TokenBasics() {
 	var tokenBasicReq models.TokenBasicReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenBasicReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	tokenBasics := make([]*models.TokenBasic, 0)
 	db.Model(&models.TokenBasic{}).Where("standard = 0 and property = 1").Preload("Tokens").Find(&tokenBasics)
@@ -81,9 +79,8 @@ func (c *TokenController) TokenBasicsInfo() {
 	var tokenBasicReq models.TokenBasicsInfoReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenBasicReq); err != nil || tokenBasicReq.PageSize < 1 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	tokenBasics := make([]*models.TokenBasic, 0)
 	orderBy := "total_count desc, name"
@@ -98,3 +95,41 @@ func (c *TokenController) TokenBasicsInfo() {
 	c.Data["json"] = models.MakeTokenBasicsInfoRsp(&tokenBasicReq, uint64(totalCount), tokenBasics)
 	c.ServeJSON()
 }
+
+const (
+	tokenPriceInterval   = 3600
+	tokenPriceCandles    = 100
+	tokenPriceMaxCandles = 1000
+)
+
+// TokenPriceHistory gets the prices of the token basic, or of its market if Market is set, in candles of the interval
+// between Start and End. The prices older than the interval has been downsampled to are not in the candles.
+func (c *TokenController) TokenPriceHistory() {
+	var req models.TokenPriceHistoryReq
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil || req.Name == "" || req.Interval < 0 || req.Interval%60 != 0 {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	if req.Interval == 0 {
+		req.Interval = tokenPriceInterval
+	}
+	if req.End <= 0 {
+		req.End = time.Now().Unix()
+	}
+	if req.Start <= 0 {
+		req.Start = req.End - req.Interval*tokenPriceCandles
+	}
+	if req.Start >= req.End || (req.End-req.Start)/req.Interval > tokenPriceMaxCandles {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("request parameter is invalid! candles should be 1 to %d", tokenPriceMaxCandles))
+		return
+	}
+	histories := make([]*models.TokenPriceHistory, 0)
+	err := db.Where("name = ? and market = ? and period <= ? and time >= ? and time < ?", req.Name, req.Market, req.Interval, req.Start, req.End).
+		Order("time").Find(&histories).Error
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get price history of token %s err: %v", req.Name, err))
+		return
+	}
+	c.Data["json"] = models.MakeTokenPriceHistoryRsp(&req, histories)
+	c.ServeJSON()
+}
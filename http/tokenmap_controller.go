@@ -20,7 +20,9 @@ package http
 import (
 	"encoding/json"
 	"fmt"
+	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 
 	"github.com/beego/beego/v2/server/web"
 )
@@ -33,16 +35,13 @@ func (c *TokenMapController) TokenMap() {
 	var tokenMapReq models.TokenMapReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenMapReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	tokenMaps := make([]*models.TokenMap, 0)
 	res := db.Where("src_chain_id = ? and src_token_hash = ?", tokenMapReq.ChainId, tokenMapReq.Hash).Preload("SrcToken").Preload("DstToken").Find(&tokenMaps)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token map: (%s,%d) does not exist", tokenMapReq.Hash, tokenMapReq.ChainId))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TOKEN_MAP_NOT_FOUND, fmt.Sprintf("token map: (%s,%d) does not exist", tokenMapReq.Hash, tokenMapReq.ChainId))
 		return
 	}
 	c.Data["json"] = models.MakeTokenMapsRsp(tokenMaps)
@@ -53,16 +52,13 @@ func (c *TokenMapController) TokenMapReverse() {
 	var tokenMapReq models.TokenMapReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &tokenMapReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	tokenMaps := make([]*models.TokenMap, 0)
 	res := db.Where("dst_chain_id = ? and dst_token_hash = ?", tokenMapReq.ChainId, tokenMapReq.Hash).Preload("SrcToken").Preload("DstToken").Find(&tokenMaps)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token map: (%s,%d) does not exist", tokenMapReq.Hash, tokenMapReq.ChainId))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TOKEN_MAP_NOT_FOUND, fmt.Sprintf("token map: (%s,%d) does not exist", tokenMapReq.Hash, tokenMapReq.ChainId))
 		return
 	}
 	c.Data["json"] = models.MakeTokenMapsRsp(tokenMaps)
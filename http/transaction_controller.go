@@ -25,31 +25,26 @@ import (
 	"poly-bridge/basedef"
 	"poly-bridge/cacheRedis"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 	"strings"
 	"time"
 
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/beego/beego/v2/server/web"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionController struct {
 	web.Controller
 }
 
-func (c *TransactionController) return400(message string) {
-	c.Data["json"] = models.MakeErrorRsp(message)
-	c.Ctx.ResponseWriter.WriteHeader(400)
-	c.ServeJSON()
-}
-
 func (c *TransactionController) Transactions() {
 	var transactionsReq models.WrapperTransactionsReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsReq); err != nil || transactionsReq.PageSize == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	transactions := make([]*models.WrapperTransaction, 0)
 	db.Limit(transactionsReq.PageSize).Offset(transactionsReq.PageSize * transactionsReq.PageNo).Order("time asc").Find(&transactions)
@@ -64,9 +59,8 @@ func (c *TransactionController) TransactionsWithFilter() {
 	var transactionsReq models.WrapperTransactionsWithFilterReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsReq); err != nil || transactionsReq.PageSize == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	transactions := make([]*models.WrapperTransaction, 0)
 
@@ -83,9 +77,8 @@ func (c *TransactionController) PolyTransactions() {
 	var transactionsReq models.PolyTransactionsReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	transactions := make([]*models.PolyTransaction, 0)
 	db.Limit(transactionsReq.PageSize).Offset(transactionsReq.PageSize * transactionsReq.PageNo).Order("time asc").Find(&transactions)
@@ -100,9 +93,8 @@ func (c *TransactionController) TransactionsOfAddressWithFilter() {
 	var req models.TransactionsOfAddressWithFilterReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil || req.PageSize == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 
 	for i := 0; i < len(req.Addresses); i++ {
@@ -172,18 +164,15 @@ func (c *TransactionController) TransactionsOfAddressWithFilter() {
 		}
 	}
 	logs.Error("Load data error %v", err)
-	c.Data["json"] = models.MakeErrorRsp("service error!")
-	c.Ctx.ResponseWriter.WriteHeader(500)
-	c.ServeJSON()
+	apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, "service error!")
 }
 
 func (c *TransactionController) TransactionsOfAddress() {
 	var transactionsOfAddressReq models.TransactionsOfAddressReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsOfAddressReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 
 	compatibleAddresses := []string{}
@@ -202,12 +191,17 @@ func (c *TransactionController) TransactionsOfAddress() {
 		}
 	}
 	transactionsOfAddressReq.Addresses = append(transactionsOfAddressReq.Addresses, compatibleAddresses...)
+	cursor, err := models.ParsePageCursor(transactionsOfAddressReq.Cursor)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
 
 	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
-	db.Debug().Table("(?) as u", db.Model(&models.SrcTransfer{}).
+	query := db.Debug().Table("(?) as u", db.Model(&models.SrcTransfer{}).
 		Select("tx_hash as hash, asset as asset, fee_token_hash as fee_token_hash, src_transfers.chain_id as chain_id").
 		Joins("left join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("`from` in ? or src_transfers.dst_user in ?", transactionsOfAddressReq.Addresses, transactionsOfAddressReq.Addresses).
+		Where("src_transfers.from in ? or src_transfers.dst_user in ?", transactionsOfAddressReq.Addresses, transactionsOfAddressReq.Addresses).
 		Where("wrapper_transactions.hash is NOT NULL or src_transfers.chain_id = ?", basedef.RIPPLE_CROSSCHAIN_ID)).
 		Where("src_transactions.standard = ?", 0).
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, u.asset as token_hash, u.fee_token_hash as fee_token_hash").
@@ -223,24 +217,29 @@ func (c *TransactionController) TransactionsOfAddress() {
 		Preload("DstTransaction.DstTransfer").
 		Preload("Token").
 		Preload("Token.TokenBasic").
-		Preload("FeeToken").
-		Limit(transactionsOfAddressReq.PageSize).Offset(transactionsOfAddressReq.PageSize * transactionsOfAddressReq.PageNo).
-		Order("src_transactions.time desc").
+		Preload("FeeToken")
+	paginate(query, "src_transactions", cursor, transactionsOfAddressReq.PageSize, transactionsOfAddressReq.PageNo).
 		Find(&srcPolyDstRelations)
 	var transactionNum int64
-	db.Model(&models.SrcTransfer{}).
-		Joins("left join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("src_transfers.`from` in ? or src_transfers.dst_user in ?", transactionsOfAddressReq.Addresses, transactionsOfAddressReq.Addresses).
-		Where("wrapper_transactions.hash is NOT NULL or src_transfers.chain_id = ?", basedef.RIPPLE_CROSSCHAIN_ID).
-		Count(&transactionNum)
+	if !transactionsOfAddressReq.NoCount {
+		transactionNum = countTransactions("TransactionsOfAddress", transactionsOfAddressReq.Addresses, func(total *int64) *gorm.DB {
+			return db.Model(&models.SrcTransfer{}).
+				Joins("left join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
+				Where("src_transfers.from in ? or src_transfers.dst_user in ?", transactionsOfAddressReq.Addresses, transactionsOfAddressReq.Addresses).
+				Where("wrapper_transactions.hash is NOT NULL or src_transfers.chain_id = ?", basedef.RIPPLE_CROSSCHAIN_ID).
+				Count(total)
+		})
+	}
 	chains := make([]*models.Chain, 0)
 	db.Model(&models.Chain{}).Find(&chains)
 	chainsMap := make(map[uint64]*models.Chain)
 	for _, chain := range chains {
 		chainsMap[chain.ChainId] = chain
 	}
-	c.Data["json"] = models.MakeTransactionsOfUserRsp(transactionsOfAddressReq.PageSize, transactionsOfAddressReq.PageNo,
-		(int(transactionNum)+transactionsOfAddressReq.PageSize-1)/transactionsOfAddressReq.PageSize, int(transactionNum), srcPolyDstRelations, chainsMap)
+	transactionsOfAddressRsp := models.MakeTransactionsOfUserRsp(transactionsOfAddressReq.PageSize, transactionsOfAddressReq.PageNo,
+		totalPage(transactionNum, transactionsOfAddressReq.PageSize), int(transactionNum), srcPolyDstRelations, chainsMap)
+	transactionsOfAddressRsp.NextCursor = models.NextPageCursor(transactionsOfAddressReq.PageSize, srcPolyDstRelations)
+	c.Data["json"] = transactionsOfAddressRsp
 	c.ServeJSON()
 }
 
@@ -302,15 +301,12 @@ func (c *TransactionController) TransactionOfHash() {
 	var transactionOfHashReq models.TransactionOfHashReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionOfHashReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	srcPolyDstRelation, err := c.getTransactionByHash(transactionOfHashReq.Hash)
 	if err != nil {
-		c.Data["json"] = err.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_FOUND, err.Error())
 		return
 	}
 	if srcPolyDstRelation.SrcTransaction.DstChainId != basedef.O3_CROSSCHAIN_ID || srcPolyDstRelation.DstTransaction == nil {
@@ -322,19 +318,16 @@ func (c *TransactionController) TransactionOfHash() {
 		}
 		resp := models.MakeTransactionRsp(srcPolyDstRelation, chainsMap)
 		if resp == nil {
-			c.Data["json"] = "transaction does not exist"
-			c.Ctx.ResponseWriter.WriteHeader(400)
-		} else {
-			c.Data["json"] = resp
+			apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_FOUND, "transaction does not exist")
+			return
 		}
+		c.Data["json"] = resp
 		c.ServeJSON()
 		return
 	}
 	srcPolyDstRelation2, err := c.getTransactionByHash(srcPolyDstRelation.DstHash)
 	if err != nil {
-		c.Data["json"] = err.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_FOUND, err.Error())
 		return
 	}
 	srcPolyDstRelation.DstHash = srcPolyDstRelation2.DstHash
@@ -353,29 +346,21 @@ func (c *TransactionController) TransactionOfCurve() {
 	var transactionOfHashReq models.TransactionOfHashReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionOfHashReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	srcPolyDstRelation1, err := c.getTransactionByDstHash(transactionOfHashReq.Hash)
 	if err != nil {
-		c.Data["json"] = err.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_FOUND, err.Error())
 		return
 	}
 	if srcPolyDstRelation1.SrcTransaction.DstChainId != basedef.O3_CROSSCHAIN_ID || srcPolyDstRelation1.DstTransaction == nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	srcPolyDstRelation2, err := c.getTransactionByHash(srcPolyDstRelation1.DstHash)
 	if err != nil {
-		c.Data["json"] = err.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_FOUND, err.Error())
 		return
 	}
 	chains := make([]*models.Chain, 0)
@@ -392,9 +377,8 @@ func (c *TransactionController) TransactionsOfState() {
 	var transactionsOfStateReq models.TransactionsOfStateReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsOfStateReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	transactions := make([]*models.WrapperTransaction, 0)
 	db.Where("status = ?", transactionsOfStateReq.State).Limit(transactionsOfStateReq.PageSize).Offset(transactionsOfStateReq.PageSize * transactionsOfStateReq.PageNo).Order("time asc").Find(&transactions)
@@ -409,13 +393,17 @@ func (c *TransactionController) TransactionsOfUnfinished() {
 	var transactionsOfUnfinishedReq models.TransactionsOfUnfinishedReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsOfUnfinishedReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	cursor, err := models.ParsePageCursor(transactionsOfUnfinishedReq.Cursor)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
 	tt := time.Now().Unix()
-	res := db.Table("src_transactions").
+	query := db.Table("src_transactions").
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, src_transfers.asset as token_hash, wrapper_transactions.fee_token_hash as fee_token_hash").
 		Where("dst_transactions.hash is null").
 		Where("src_transactions.standard = ?", 0).
@@ -432,27 +420,31 @@ func (c *TransactionController) TransactionsOfUnfinished() {
 		Preload("DstTransaction.DstTransfer").
 		Preload("Token").
 		Preload("Token.TokenBasic").
-		Preload("FeeToken").
-		Limit(transactionsOfUnfinishedReq.PageSize).Offset(transactionsOfUnfinishedReq.PageSize * transactionsOfUnfinishedReq.PageNo).
-		Order("src_transactions.time desc").
+		Preload("FeeToken")
+	res := paginate(query, "src_transactions", cursor, transactionsOfUnfinishedReq.PageSize, transactionsOfUnfinishedReq.PageNo).
 		Find(&srcPolyDstRelations)
 	if res.Error != nil {
-		c.Data["json"] = res.Error.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, res.Error.Error())
 		return
 	}
 	var transactionNum int64
-	db.Table("src_transactions").
-		Where("dst_transactions.hash is null").
-		Where("src_transactions.standard = ?", 0).
-		Where("src_transactions.time > ?", tt-24*60*60*28).
-		Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
-		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
-		Joins("inner join wrapper_transactions on src_transactions.hash = wrapper_transactions.hash").
-		Count(&transactionNum)
-	c.Data["json"] = models.MakeTransactionOfUnfinishedRsp(transactionsOfUnfinishedReq.PageSize, transactionsOfUnfinishedReq.PageNo,
-		(int(transactionNum)+transactionsOfUnfinishedReq.PageSize-1)/transactionsOfUnfinishedReq.PageSize, int(transactionNum), srcPolyDstRelations)
+	if !transactionsOfUnfinishedReq.NoCount {
+		// the window of the count moves with the cached count
+		transactionNum = countTransactions("TransactionsOfUnfinished", nil, func(total *int64) *gorm.DB {
+			return db.Table("src_transactions").
+				Where("dst_transactions.hash is null").
+				Where("src_transactions.standard = ?", 0).
+				Where("src_transactions.time > ?", tt-24*60*60*28).
+				Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
+				Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
+				Joins("inner join wrapper_transactions on src_transactions.hash = wrapper_transactions.hash").
+				Count(total)
+		})
+	}
+	transactionOfUnfinishedRsp := models.MakeTransactionOfUnfinishedRsp(transactionsOfUnfinishedReq.PageSize, transactionsOfUnfinishedReq.PageNo,
+		totalPage(transactionNum, transactionsOfUnfinishedReq.PageSize), int(transactionNum), srcPolyDstRelations)
+	transactionOfUnfinishedRsp.NextCursor = models.NextPageCursor(transactionsOfUnfinishedReq.PageSize, srcPolyDstRelations)
+	c.Data["json"] = transactionOfUnfinishedRsp
 	c.ServeJSON()
 }
 
@@ -460,12 +452,16 @@ func (c *TransactionController) TransactionsOfAsset() {
 	var transactionsOfAssetReq models.TransactionsOfAssetReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &transactionsOfAssetReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	cursor, err := models.ParsePageCursor(transactionsOfAssetReq.Cursor)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
 	}
 	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
-	res := db.Table("src_transactions").
+	query := db.Table("src_transactions").
 		Select("src_transactions.hash as src_hash, poly_transactions.hash as poly_hash, dst_transactions.hash as dst_hash, src_transactions.chain_id as chain_id, src_transfers.asset as token_hash, wrapper_transactions.fee_token_hash as fee_token_hash").
 		Where("src_transfers.asset = ?", transactionsOfAssetReq.Asset).
 		Where("src_transfers.chain_id = ?", transactionsOfAssetReq.Chain).
@@ -482,27 +478,30 @@ func (c *TransactionController) TransactionsOfAsset() {
 		Preload("DstTransaction.DstTransfer").
 		Preload("Token").
 		Preload("Token.TokenBasic").
-		Preload("FeeToken").
-		Limit(transactionsOfAssetReq.PageSize).Offset(transactionsOfAssetReq.PageSize * transactionsOfAssetReq.PageNo).
-		Order("src_transactions.time desc").
+		Preload("FeeToken")
+	res := paginate(query, "src_transactions", cursor, transactionsOfAssetReq.PageSize, transactionsOfAssetReq.PageNo).
 		Find(&srcPolyDstRelations)
 	if res.Error != nil {
-		c.Data["json"] = res.Error.Error()
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, res.Error.Error())
 		return
 	}
 	var transactionNum int64
-	db.Table("src_transactions").
-		Where("src_transfers.asset = ?", transactionsOfAssetReq.Asset).
-		Where("src_transfers.chain_id = ?", transactionsOfAssetReq.Chain).
-		Where("src_transactions.standard = ?", 0).
-		Joins("left join src_transfers on src_transactions.hash = src_transfers.tx_hash").
-		Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
-		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
-		Count(&transactionNum)
-	c.Data["json"] = models.MakeTransactionOfUnfinishedRsp(transactionsOfAssetReq.PageSize, transactionsOfAssetReq.PageNo,
-		(int(transactionNum)+transactionsOfAssetReq.PageSize-1)/transactionsOfAssetReq.PageSize, int(transactionNum), srcPolyDstRelations)
+	if !transactionsOfAssetReq.NoCount {
+		transactionNum = countTransactions("TransactionsOfAsset", []interface{}{transactionsOfAssetReq.Asset, transactionsOfAssetReq.Chain}, func(total *int64) *gorm.DB {
+			return db.Table("src_transactions").
+				Where("src_transfers.asset = ?", transactionsOfAssetReq.Asset).
+				Where("src_transfers.chain_id = ?", transactionsOfAssetReq.Chain).
+				Where("src_transactions.standard = ?", 0).
+				Joins("left join src_transfers on src_transactions.hash = src_transfers.tx_hash").
+				Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
+				Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
+				Count(total)
+		})
+	}
+	transactionOfAssetRsp := models.MakeTransactionOfUnfinishedRsp(transactionsOfAssetReq.PageSize, transactionsOfAssetReq.PageNo,
+		totalPage(transactionNum, transactionsOfAssetReq.PageSize), int(transactionNum), srcPolyDstRelations)
+	transactionOfAssetRsp.NextCursor = models.NextPageCursor(transactionsOfAssetReq.PageSize, srcPolyDstRelations)
+	c.Data["json"] = transactionOfAssetRsp
 	c.ServeJSON()
 }
 
@@ -510,7 +509,7 @@ func (c *TransactionController) GetManualTxData() {
 	var manualTxDataReq models.ManualTxDataReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &manualTxDataReq); err != nil {
-		c.return400("request parameter is invalid!")
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	if manualTxDataReq.PolyHash[:2] == "0x" || manualTxDataReq.PolyHash[:2] == "0X" {
@@ -519,24 +518,24 @@ func (c *TransactionController) GetManualTxData() {
 	polyTransaction := new(models.PolyTransaction)
 	res := db.Where("hash = ?", manualTxDataReq.PolyHash).First(polyTransaction)
 	if res.RowsAffected == 0 {
-		c.return400(fmt.Sprintf("%v is not polyhash", manualTxDataReq.PolyHash))
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_FOUND, fmt.Sprintf("%v is not polyhash", manualTxDataReq.PolyHash))
 		return
 	}
 	if polyTransaction.DstChainId == basedef.ONT_CROSSCHAIN_ID || polyTransaction.DstChainId == basedef.NEO_CROSSCHAIN_ID || polyTransaction.DstChainId == basedef.NEO3_CROSSCHAIN_ID {
-		c.return400(fmt.Sprintf("%v can not submit to dst chain", manualTxDataReq.PolyHash))
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_NOT_RELAYABLE, fmt.Sprintf("%v can not submit to dst chain", manualTxDataReq.PolyHash))
 		return
 	}
 	var x string
 	res = db.Model(&models.DstTransaction{}).Select("hash").Where("poly_hash = ?", manualTxDataReq.PolyHash).First(&x)
 	if res.RowsAffected != 0 {
-		c.return400(fmt.Sprintf("%v was submitted to dst chain", manualTxDataReq.PolyHash))
+		apierror.Output(&c.Controller, basedef.ERROR_TRANSACTION_RELAYED, fmt.Sprintf("%v was submitted to dst chain", manualTxDataReq.PolyHash))
 		return
 	}
 	manualTxDataResp := new(models.ManualTxDataResp)
 	manualData, err := cacheRedis.Redis.GetManualTx(manualTxDataReq.PolyHash)
 	if err == nil {
 		if manualData == "" {
-			c.return400(fmt.Sprintf("%v getManualData loading", manualTxDataReq.PolyHash))
+			apierror.Output(&c.Controller, basedef.ERROR_RELAY_PENDING, fmt.Sprintf("%v getManualData loading", manualTxDataReq.PolyHash))
 			return
 		}
 		json.Unmarshal([]byte(manualData), manualTxDataResp)
@@ -562,7 +561,7 @@ func (c *TransactionController) GetManualTxData() {
 		c.ServeJSON()
 		return
 	}
-	c.return400(fmt.Sprintf("%v getManualData timeout", manualTxDataReq.PolyHash))
+	apierror.Output(&c.Controller, basedef.ERROR_RELAY_TIMEOUT, fmt.Sprintf("%v getManualData timeout", manualTxDataReq.PolyHash))
 	return
 }
 
@@ -570,30 +569,43 @@ func (c *TransactionController) TransactionsWithoutWrapper() {
 	var txWithoutWrapperReq models.TxWithoutWrapperReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &txWithoutWrapperReq); err != nil {
-		c.return400("request parameter is invalid!")
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	cursor, err := models.ParsePageCursor(txWithoutWrapperReq.Cursor)
+	if err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	srcTransfers := make([]*models.SrcTransfer, 0)
-	db.Table("src_transfers").
-		Where("src_transfers.from = ?", txWithoutWrapperReq.User).
+	query := db.Table("src_transfers").
+		Where(clause.Eq{Column: clause.Column{Table: "src_transfers", Name: "from"}, Value: txWithoutWrapperReq.User}).
 		Where("src_transfers.chain_id = ?", txWithoutWrapperReq.ChainId).
 		Where("src_transfers.standard = ?", 0).
 		Where("wrapper_transactions.hash is NULL").
 		Joins("left join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash and src_transfers.chain_id = wrapper_transactions.src_chain_id").
-		Limit(txWithoutWrapperReq.PageSize).
-		Offset(txWithoutWrapperReq.PageSize * txWithoutWrapperReq.PageNo).
-		Order("src_transfers.id desc").
-		Preload("Token").
+		Preload("Token")
+	// the transfers are listed in the order they are indexed
+	paginateById(query, "src_transfers", cursor, txWithoutWrapperReq.PageSize, txWithoutWrapperReq.PageNo).
 		Find(&srcTransfers)
 	var count int64
-	db.Table("src_transfers").
-		Where("src_transfers.from = ?", txWithoutWrapperReq.User).
-		Where("src_transfers.chain_id = ?", txWithoutWrapperReq.ChainId).
-		Where("src_transfers.standard = ?", 0).
-		Where("wrapper_transactions.hash is NULL").
-		Joins("left join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash and src_transfers.chain_id = wrapper_transactions.src_chain_id").
-		Count(&count)
-	c.Data["json"] = models.MakeTxWithoutWrapperRsp(txWithoutWrapperReq.PageSize, txWithoutWrapperReq.PageNo, srcTransfers, count)
+	if !txWithoutWrapperReq.NoCount {
+		count = countTransactions("TransactionsWithoutWrapper", []interface{}{txWithoutWrapperReq.User, txWithoutWrapperReq.ChainId}, func(total *int64) *gorm.DB {
+			return db.Table("src_transfers").
+				Where(clause.Eq{Column: clause.Column{Table: "src_transfers", Name: "from"}, Value: txWithoutWrapperReq.User}).
+				Where("src_transfers.chain_id = ?", txWithoutWrapperReq.ChainId).
+				Where("src_transfers.standard = ?", 0).
+				Where("wrapper_transactions.hash is NULL").
+				Joins("left join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash and src_transfers.chain_id = wrapper_transactions.src_chain_id").
+				Count(total)
+		})
+	}
+	txWithoutWrapperRsp := models.MakeTxWithoutWrapperRsp(txWithoutWrapperReq.PageSize, txWithoutWrapperReq.PageNo, srcTransfers, count)
+	if len(srcTransfers) > 0 && len(srcTransfers) == txWithoutWrapperReq.PageSize {
+		last := srcTransfers[len(srcTransfers)-1]
+		txWithoutWrapperRsp.NextCursor = (&models.PageCursor{Time: last.Time, Id: last.Id}).String()
+	}
+	c.Data["json"] = txWithoutWrapperRsp
 	c.ServeJSON()
 	return
 }
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beego/beego/v2/server/web/context"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/crosschaindao/bridgedao"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestTransactionController_TransactionOfHashSqlite(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		DBConfig: &conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "http_transaction_of_hash"},
+		RelayUrl: "http://localhost:30330",
+	}
+	Init()
+
+	srcHash := "336cd94f1ec80280c684606b8c9358f1ad0e9e7e7ce69f0da35c21a66fa0c729"
+	polyHash := "d2e8e325265ed314d9f538c2cb3f8e0a71ca2adad8b31db98278a4af6aecc1df"
+	dstHash := "6a8b8c4e2f1d3a5b7c9e0f1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e"
+	srcTransactions := []*models.SrcTransaction{{
+		Hash: srcHash, ChainId: 2, State: 1, Time: 1608885420, Fee: models.NewBigIntFromInt(11370800000000), Height: 9329385,
+		User: "ad79c606bd4ef330ac45df9d2ace4e7e7c6db13f", DstChainId: 4, Contract: "d8ae73e06552e270340b63a8bcabf9277a1aac99",
+		Key: "0000000000000000000000000000000000000000000000000000000000000abe",
+		SrcTransfer: &models.SrcTransfer{
+			ChainId: 2, Time: 1608885420, Asset: "0000000000000000000000000000000000000000",
+			From: "8bc7e7304120b88d111431f6a4853589d10e8132", To: "d8ae73e06552e270340b63a8bcabf9277a1aac99",
+			Amount: models.NewBigIntFromInt(9000000000000), DstChainId: 4, DstUser: "ARpuQar5CPtxEoqfcg1fxGWnwDdp7w3jj8",
+		},
+	}}
+	polyTransactions := []*models.PolyTransaction{{
+		Hash: polyHash, State: 1, Time: 1608885450, Fee: models.NewBigIntFromInt(0), Height: 1641497,
+		SrcChainId: 2, SrcHash: srcHash, DstChainId: 4,
+	}}
+	dstTransactions := []*models.DstTransaction{{
+		Hash: dstHash, ChainId: 4, State: 1, Time: 1608885480, Fee: models.NewBigIntFromInt(0), Height: 6000000,
+		SrcChainId: 2, PolyHash: polyHash,
+		DstTransfer: &models.DstTransfer{
+			ChainId: 4, Time: 1608885480, Asset: "bf9c0fd26055ff19245c8080df06d97ae32db3d7",
+			To: "ARpuQar5CPtxEoqfcg1fxGWnwDdp7w3jj8", Amount: models.NewBigIntFromInt(9000000000000),
+		},
+	}}
+	dao := bridgedao.NewBridgeDao(conf.GlobalConfig.DBConfig, false)
+	assert.NoError(t, dao.UpdateEvents(nil, srcTransactions, polyTransactions, dstTransactions, nil, nil))
+
+	request, _ := json.Marshal(&models.TransactionOfHashReq{Hash: srcHash})
+	w := httptest.NewRecorder()
+	ctx := context.NewContext()
+	ctx.Reset(w, httptest.NewRequest(http.MethodPost, "/transactionofhash/", nil))
+	ctx.Input.RequestBody = request
+	c := &TransactionController{}
+	c.Init(ctx, "TransactionController", "TransactionOfHash", c)
+	c.TransactionOfHash()
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	rsp := new(models.TransactionRsp)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rsp))
+	assert.Equal(t, srcHash, rsp.Hash)
+	assert.Equal(t, uint64(4), rsp.DstChainId)
+	assert.Equal(t, "9000000000000", rsp.TransferAmount)
+
+	request, _ = json.Marshal(&models.TransactionOfHashReq{Hash: polyHash})
+	w = httptest.NewRecorder()
+	ctx = context.NewContext()
+	ctx.Reset(w, httptest.NewRequest(http.MethodPost, "/transactionofhash/", nil))
+	ctx.Input.RequestBody = request
+	c = &TransactionController{}
+	c.Init(ctx, "TransactionController", "TransactionOfHash", c)
+	c.TransactionOfHash()
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	errorRsp := new(models.ErrorRsp)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), errorRsp))
+	assert.Equal(t, basedef.ERROR_TRANSACTION_NOT_FOUND, errorRsp.Code)
+}
+
+func TestTransactionController_TransactionsWithoutWrapperCursor(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		DBConfig: &conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "http_transactions_cursor"},
+		RelayUrl: "http://localhost:30330",
+	}
+	Init()
+
+	user := "8bc7e7304120b88d111431f6a4853589d10e8132"
+	srcTransfers := []*models.SrcTransfer{
+		{TxHash: "01", ChainId: 2, Time: 1608885420, From: user, Amount: models.NewBigIntFromInt(1), DstChainId: 4},
+		{TxHash: "02", ChainId: 2, Time: 1608885480, From: user, Amount: models.NewBigIntFromInt(2), DstChainId: 4},
+		{TxHash: "03", ChainId: 2, Time: 1608885480, From: user, Amount: models.NewBigIntFromInt(3), DstChainId: 4},
+	}
+	assert.NoError(t, db.Create(srcTransfers).Error)
+
+	list := func(req *models.TxWithoutWrapperReq) (int, *models.TxWithoutWrapperRes) {
+		request, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		ctx := context.NewContext()
+		ctx.Reset(w, httptest.NewRequest(http.MethodPost, "/transactionswithoutwrapper/", nil))
+		ctx.Input.RequestBody = request
+		c := &TransactionController{}
+		c.Init(ctx, "TransactionController", "TransactionsWithoutWrapper", c)
+		c.TransactionsWithoutWrapper()
+		rsp := new(models.TxWithoutWrapperRes)
+		json.Unmarshal(w.Body.Bytes(), rsp)
+		return w.Code, rsp
+	}
+
+	code, rsp := list(&models.TxWithoutWrapperReq{ChainId: 2, User: user, PageSize: 2})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(3), rsp.Total)
+	if assert.Len(t, rsp.Txs, 2) {
+		assert.Equal(t, "03", rsp.Txs[0].TxHash)
+		assert.Equal(t, "02", rsp.Txs[1].TxHash)
+	}
+	assert.NotEmpty(t, rsp.NextCursor)
+
+	// a new transfer does not shift the next page
+	assert.NoError(t, db.Create(&models.SrcTransfer{TxHash: "04", ChainId: 2, Time: 1608885500, From: user, Amount: models.NewBigIntFromInt(4), DstChainId: 4}).Error)
+	code, rsp = list(&models.TxWithoutWrapperReq{ChainId: 2, User: user, PageSize: 2, Cursor: rsp.NextCursor, NoCount: true})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(0), rsp.Total)
+	if assert.Len(t, rsp.Txs, 1) {
+		assert.Equal(t, "01", rsp.Txs[0].TxHash)
+	}
+	assert.Empty(t, rsp.NextCursor)
+
+	// the transfers indexed later are listed first whatever their time is
+	assert.NoError(t, db.Create(&models.SrcTransfer{TxHash: "05", ChainId: 2, Time: 1608885400, From: user, Amount: models.NewBigIntFromInt(5), DstChainId: 4}).Error)
+	code, rsp = list(&models.TxWithoutWrapperReq{ChainId: 2, User: user, PageSize: 2, NoCount: true})
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, rsp.Txs, 2) {
+		assert.Equal(t, "05", rsp.Txs[0].TxHash)
+		assert.Equal(t, "04", rsp.Txs[1].TxHash)
+	}
+	code, rsp = list(&models.TxWithoutWrapperReq{ChainId: 2, User: user, PageSize: 2, PageNo: 1, NoCount: true})
+	if assert.Len(t, rsp.Txs, 2) {
+		assert.Equal(t, "03", rsp.Txs[0].TxHash)
+		assert.Equal(t, "02", rsp.Txs[1].TxHash)
+	}
+
+	code, _ = list(&models.TxWithoutWrapperReq{ChainId: 2, User: user, PageSize: 2, Cursor: "not a cursor"})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
+	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+)
+
+const (
+	statusStreamKeepAlive = 30 * time.Second
+	statusStreamMaxKeys   = 100
+	statusStreamBuffer    = 64
+)
+
+// statusSubscriber is a stream of the status of some transactions and of the transactions of some addresses
+type statusSubscriber struct {
+	hashes    map[string]bool
+	addresses map[string]bool
+	statuses  chan *models.WrapperTransactionRsp
+}
+
+func normalizeHex(s string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+}
+
+func newStatusSubscriber(hashes, addresses []string) *statusSubscriber {
+	subscriber := &statusSubscriber{
+		hashes:    make(map[string]bool),
+		addresses: make(map[string]bool),
+		statuses:  make(chan *models.WrapperTransactionRsp, statusStreamBuffer),
+	}
+	for _, hash := range hashes {
+		subscriber.hashes[normalizeHex(hash)] = true
+	}
+	for _, address := range addresses {
+		subscriber.addresses[normalizeHex(address)] = true
+	}
+	return subscriber
+}
+
+func (s *statusSubscriber) subscribed(status *models.WrapperTransactionRsp) bool {
+	return s.hashes[normalizeHex(status.Hash)] || s.addresses[normalizeHex(status.User)] || s.addresses[normalizeHex(status.DstUser)]
+}
+
+// statusHub fans out the status changes of the redis channel, which all the http servers subscribe once,
+// to the streams of this server.
+type statusHub struct {
+	lock        sync.Mutex
+	subscribers map[*statusSubscriber]bool
+	statuses    <-chan *models.WrapperTransactionRsp
+	subscribe   func() (<-chan *models.WrapperTransactionRsp, error)
+}
+
+var transactionStatusHub = &statusHub{
+	subscribers: make(map[*statusSubscriber]bool),
+	subscribe: func() (<-chan *models.WrapperTransactionRsp, error) {
+		return cacheRedis.Redis.SubscribeTransactionStatus()
+	},
+}
+
+func (hub *statusHub) add(subscriber *statusSubscriber) error {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+	if hub.statuses == nil {
+		statuses, err := hub.subscribe()
+		if err != nil {
+			return err
+		}
+		hub.statuses = statuses
+		go hub.run(statuses)
+	}
+	hub.subscribers[subscriber] = true
+	return nil
+}
+
+func (hub *statusHub) remove(subscriber *statusSubscriber) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+	delete(hub.subscribers, subscriber)
+}
+
+// run dispatches the statuses until the subscription ends, which ends the streams, the next stream subscribes again
+func (hub *statusHub) run(statuses <-chan *models.WrapperTransactionRsp) {
+	for status := range statuses {
+		hub.dispatch(status)
+	}
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+	hub.statuses = nil
+	for subscriber := range hub.subscribers {
+		close(subscriber.statuses)
+		delete(hub.subscribers, subscriber)
+	}
+}
+
+func (hub *statusHub) dispatch(status *models.WrapperTransactionRsp) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+	for subscriber := range hub.subscribers {
+		if !subscriber.subscribed(status) {
+			continue
+		}
+		select {
+		case subscriber.statuses <- status:
+		default:
+			logs.Warn("transaction status stream is full, drop status %d of %s", status.State, status.Hash)
+		}
+	}
+}
+
+func writeStatusEvent(w io.Writer, status *models.WrapperTransactionRsp) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: status\ndata: %s\n\n", data)
+	return err
+}
+
+func splitQuery(query string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(query, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// TransactionStatusStream pushes the status changes of the transactions of the hashes and of the addresses
+// as server-sent events, starting with the current status of the hashes. Front-ends stream them instead of
+// polling transactionofhash and transactionsofaddress.
+func (c *TransactionController) TransactionStatusStream() {
+	hashes := splitQuery(c.Ctx.Input.Query("hash"))
+	addresses := splitQuery(c.Ctx.Input.Query("address"))
+	if len(hashes)+len(addresses) == 0 || len(hashes)+len(addresses) > statusStreamMaxKeys {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	subscriber := newStatusSubscriber(hashes, addresses)
+	if err := transactionStatusHub.add(subscriber); err != nil {
+		logs.Error("subscribe transaction status err: %v", err)
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, "service error!")
+		return
+	}
+	defer transactionStatusHub.remove(subscriber)
+
+	c.EnableRender = false
+	w := c.Ctx.ResponseWriter
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+
+	if len(subscriber.hashes) > 0 {
+		normalized := make([]string, 0, len(subscriber.hashes))
+		for hash := range subscriber.hashes {
+			normalized = append(normalized, hash)
+		}
+		wrapperTransactions := make([]*models.WrapperTransaction, 0)
+		if err := db.Where("hash in ?", normalized).Find(&wrapperTransactions).Error; err != nil {
+			logs.Error("get status of transactions %v err: %v", normalized, err)
+		}
+		for _, wrapperTransaction := range wrapperTransactions {
+			if err := writeStatusEvent(w, models.MakeWrapperTransactionRsp(wrapperTransaction)); err != nil {
+				return
+			}
+		}
+	}
+	w.Flush()
+
+	keepAlive := time.NewTicker(statusStreamKeepAlive)
+	defer keepAlive.Stop()
+	done := c.Ctx.Request.Context().Done()
+	for {
+		select {
+		case <-done:
+			return
+		case status, ok := <-subscriber.statuses:
+			if !ok {
+				return
+			}
+			if err := writeStatusEvent(w, status); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
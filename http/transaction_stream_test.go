@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/server/web/context"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestStatusSubscriber(t *testing.T) {
+	subscriber := newStatusSubscriber([]string{"0xAB01"}, []string{"0x8BC7"})
+	assert.True(t, subscriber.subscribed(&models.WrapperTransactionRsp{Hash: "ab01"}))
+	assert.True(t, subscriber.subscribed(&models.WrapperTransactionRsp{Hash: "ab02", User: "8bc7"}))
+	assert.True(t, subscriber.subscribed(&models.WrapperTransactionRsp{Hash: "ab02", DstUser: "0x8bc7"}))
+	assert.False(t, subscriber.subscribed(&models.WrapperTransactionRsp{Hash: "ab02", User: "8bc8"}))
+}
+
+func TestTransactionController_TransactionStatusStream(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		DBConfig: &conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "http_transaction_status_stream"},
+		RelayUrl: "http://localhost:30330",
+	}
+	Init()
+	assert.NoError(t, db.Create(&models.WrapperTransaction{
+		Hash: "ab01", User: "8bc7", SrcChainId: 2, DstChainId: 6, FeeAmount: models.NewBigIntFromInt(0),
+		PaidGas: models.NewBigIntFromInt(0), Status: 2,
+	}).Error)
+
+	statuses := make(chan *models.WrapperTransactionRsp)
+	subscribe := transactionStatusHub.subscribe
+	defer func() { transactionStatusHub.subscribe = subscribe }()
+	transactionStatusHub.subscribe = func() (<-chan *models.WrapperTransactionRsp, error) {
+		return statuses, nil
+	}
+
+	w := httptest.NewRecorder()
+	ctx := context.NewContext()
+	ctx.Reset(w, httptest.NewRequest(http.MethodGet, "/transactionstatusstream/?hash=0xab01&address=9c1d", nil))
+	c := &TransactionController{}
+	c.Init(ctx, "TransactionController", "TransactionStatusStream", c)
+	done := make(chan struct{})
+	go func() {
+		c.TransactionStatusStream()
+		close(done)
+	}()
+
+	for i := 0; i < 100; i++ {
+		transactionStatusHub.lock.Lock()
+		subscribed := len(transactionStatusHub.subscribers) > 0
+		transactionStatusHub.lock.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	statuses <- &models.WrapperTransactionRsp{Hash: "ab01", User: "8bc7", State: 4}
+	statuses <- &models.WrapperTransactionRsp{Hash: "ab02", User: "8bc7", State: 4}
+	statuses <- &models.WrapperTransactionRsp{Hash: "ab03", DstUser: "9c1d", State: 0}
+	close(statuses)
+	<-done
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	events := strings.Split(strings.TrimSpace(w.Body.String()), "\n\n")
+	if assert.Len(t, events, 3) {
+		assert.Contains(t, events[0], `"Hash":"ab01"`)
+		assert.Contains(t, events[0], `"State":2`)
+		assert.Contains(t, events[1], `"Hash":"ab01"`)
+		assert.Contains(t, events[1], `"State":4`)
+		assert.Contains(t, events[2], `"Hash":"ab03"`)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package http
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
+	"poly-bridge/utils/webhook"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+const (
+	webhookDeliveryCount    = 50
+	webhookDeliveryMaxCount = 500
+)
+
+type WebhookController struct {
+	web.Controller
+}
+
+func (c *WebhookController) authorized() bool {
+	cfg := conf.GlobalConfig.WebhookConfig
+	token := c.Ctx.Input.Query("token")
+	if cfg == nil || cfg.ApiToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.ApiToken)) != 1 {
+		apierror.Output(&c.Controller, basedef.ERROR_ACCESS_DENIED, "Access denied")
+		return false
+	}
+	return true
+}
+
+// Webhooks lists the registered webhooks without their secrets
+func (c *WebhookController) Webhooks() {
+	if !c.authorized() {
+		return
+	}
+	webhooks := make([]*models.Webhook, 0)
+	if err := db.Order("id").Find(&webhooks).Error; err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get webhooks err: %v", err))
+		return
+	}
+	rsp := make([]*models.WebhookRsp, 0, len(webhooks))
+	for _, v := range webhooks {
+		rsp = append(rsp, models.MakeWebhookRsp(v, false))
+	}
+	c.Data["json"] = rsp
+	c.ServeJSON()
+}
+
+// Register adds a webhook, the secret of its signatures is only shown in the response
+func (c *WebhookController) Register() {
+	if !c.authorized() {
+		return
+	}
+	var req models.WebhookReq
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &req); err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("url %s is invalid", req.Url))
+		return
+	}
+	if req.Partner == "" {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "partner is required")
+		return
+	}
+	for _, event := range req.Events {
+		if !webhook.ValidEvent(event) {
+			apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, fmt.Sprintf("event %s is invalid, one of %s", event, strings.Join(webhook.Events, ", ")))
+			return
+		}
+	}
+	if req.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("generate secret err: %v", err))
+			return
+		}
+		req.Secret = hex.EncodeToString(secret)
+	}
+	hook := &models.Webhook{
+		Partner:  req.Partner,
+		Url:      req.Url,
+		Secret:   req.Secret,
+		ChainId:  req.ChainId,
+		Token:    req.Token,
+		Address:  req.Address,
+		Contract: req.Contract,
+		Events:   strings.Join(req.Events, ","),
+		Time:     time.Now().Unix(),
+	}
+	if err = db.Create(hook).Error; err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("register webhook err: %v", err))
+		return
+	}
+	c.Data["json"] = models.MakeWebhookRsp(hook, true)
+	c.ServeJSON()
+}
+
+// Remove deletes a webhook, its pending deliveries are given up and its delivery log is kept
+func (c *WebhookController) Remove() {
+	if !c.authorized() {
+		return
+	}
+	id, err := c.GetInt64("id")
+	if err != nil || id <= 0 {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	hook := new(models.Webhook)
+	res := db.Where("id = ?", id).Limit(1).Find(hook)
+	if res.Error == nil && res.RowsAffected == 0 {
+		apierror.Output(&c.Controller, basedef.ERROR_NOT_FOUND, fmt.Sprintf("webhook %d does not exist", id))
+		return
+	}
+	if res.Error == nil {
+		res = db.Delete(hook)
+	}
+	if res.Error != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("remove webhook %d err: %v", id, res.Error))
+		return
+	}
+	c.Data["json"] = models.MakeWebhookRsp(hook, false)
+	c.ServeJSON()
+}
+
+// Deliveries is the log of the last deliveries of a webhook
+func (c *WebhookController) Deliveries() {
+	if !c.authorized() {
+		return
+	}
+	id, err := c.GetInt64("id")
+	if err != nil || id <= 0 {
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
+		return
+	}
+	count, _ := c.GetInt("count", webhookDeliveryCount)
+	if count <= 0 || count > webhookDeliveryMaxCount {
+		count = webhookDeliveryCount
+	}
+	deliveries := make([]*models.WebhookDelivery, 0)
+	if err = db.Where("webhook_id = ?", id).Order("id desc").Limit(count).Find(&deliveries).Error; err != nil {
+		apierror.Output(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("get deliveries of webhook %d err: %v", id, err))
+		return
+	}
+	c.Data["json"] = &models.WebhookDeliveriesRsp{WebhookId: id, Deliveries: deliveries}
+	c.ServeJSON()
+}
@@ -2,9 +2,10 @@ package http
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/beego/beego/v2/server/web"
+	"poly-bridge/basedef"
 	"poly-bridge/models"
+	"poly-bridge/utils/apierror"
 )
 
 type WrapperController struct {
@@ -15,16 +16,12 @@ func (c *WrapperController) WrapperCheck() {
 	var wrapperCheckReq models.WrapperCheckReq
 	var err error
 	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &wrapperCheckReq); err != nil {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid!")
 		return
 	}
 	wrappers, ok := contractCheck[wrapperCheckReq.ChainId]
 	if !ok {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("no this chain!"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		apierror.Output(&c.Controller, basedef.ERROR_CHAIN_NOT_FOUND, "no this chain!")
 		return
 	}
 	wrapperCheckRsp := &models.WrapperCheckRsp{
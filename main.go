@@ -25,9 +25,11 @@ import (
 	"poly-bridge/cacheRedis"
 	"poly-bridge/common"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/explorer"
 	"poly-bridge/http"
 	"poly-bridge/nft_http"
+	"poly-bridge/utils/apispec"
 
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/beego/beego/v2/server/web"
@@ -70,6 +72,9 @@ func run(ctx *cli.Context) {
 		//fmt.Println(config.HttpConfig)
 		panic("Invalid server config")
 	}
+	if err := dbconn.CheckSchema(config); err != nil {
+		panic(err)
+	}
 	logs.SetLogger(logs.AdapterFile, fmt.Sprintf(`{"filename":"%s"}`, config.HttpLogFile))
 
 	basedef.ConfirmEnv(config.Env)
@@ -79,11 +84,14 @@ func run(ctx *cli.Context) {
 		&cors.Options{
 			AllowAllOrigins:  true,
 			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowHeaders:     []string{"Origin", "Authorization", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
-			ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
+			AllowHeaders:     []string{"Origin", "Authorization", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type", "X-Api-Key"},
+			ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type", "X-RateLimit-Limit", "X-RateLimit-Remaining", "Retry-After"},
 			AllowCredentials: true,
 		},
 	))
+	if config.RateLimitConfig != nil {
+		web.InsertFilter("*", web.BeforeRouter, http.RateLimit(config.RateLimitConfig))
+	}
 
 	// TG bot
 	common.TgBotInit()
@@ -101,8 +109,12 @@ func run(ctx *cli.Context) {
 			nft_http.Init(config),
 			http.GetRouter(config),
 			explorer.GetRouter(),
+			web.NSRouter("/openapi.json", &apispec.Controller{}, "get:Get"),
 		),
 	)
+	apispec.Register("/v1/bridge", http.ApiRoutes)
+	apispec.Register("/v1/nft", nft_http.ApiRoutes)
+	web.InsertFilter("/v1/*", web.BeforeRouter, apispec.Validator)
 
 	// Insert web config
 	web.BConfig.Listen.HTTPAddr = config.HttpConfig.Address
@@ -0,0 +1,454 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package baseline keeps the models of the tables as they were when the migrations were introduced, the
+// baseline migration creates them so that it creates the same schema whatever the models become later.
+// They must not be edited, the changes of the models get their own migrations.
+package baseline
+
+import (
+	"poly-bridge/models"
+)
+
+// Tables returns the baseline models of all the tables
+func Tables() []interface{} {
+	return []interface{}{
+		&AirDropInfo{},
+		&AirDropNft{},
+		&AssetStatistic{},
+		&Chain{},
+		&ChainBlock{},
+		&ChainFee{},
+		&ChainStatistic{},
+		&DeadLetterBlock{},
+		&DstSwap{},
+		&DstTransaction{},
+		&DstTransfer{},
+		&LockTokenStatistic{},
+		&NFTProfile{},
+		&NftUser{},
+		&PolyDetail{},
+		&PolyTransaction{},
+		&PriceMarket{},
+		&SrcSwap{},
+		&SrcTransaction{},
+		&SrcTransfer{},
+		&TimeStatistic{},
+		&TokenBasic{},
+		&TokenMap{},
+		&TokenPriceAvg{},
+		&Token{},
+		&TokenStatistic{},
+		&WrapperDetail{},
+		&WrapperTransaction{},
+	}
+}
+
+type AirDropInfo struct {
+	Id          int64  `gorm:"primaryKey;autoIncrement"`
+	User        string `gorm:"uniqueIndex;type:varchar(66);not null"`
+	ChainID     uint64 `gorm:"type:bigint(20);not null"`
+	IsEth       bool   `gorm:"type:int(8);not null"`
+	BindAddr    string `gorm:"type:varchar(66);not null"`
+	BindChainId uint64 `gorm:"type:bigint(20);not null"`
+	Amount      int64  `gorm:"type:bigint(20);not null"`
+	SrcTxId     int64  `gorm:"index:airdropinfos_srctxid;type:bigint(20);not null"`
+	IsClaim     bool   `gorm:"type:int(8);not null"`
+}
+
+type AirDropNft struct {
+	Id          int64  `gorm:"primaryKey;autoIncrement"`
+	Amount      int64  `gorm:"type:bigint(20);not null"`
+	Rank        int64  `gorm:"type:bigint(20);not null"`
+	BindChainId uint64 `gorm:"type:bigint(20);not null"`
+	BindAddr    string `gorm:"uniqueIndex;type:varchar(66);not null"`
+	NftTbId     int64  `gorm:"index:airdropnfts_nfttbid;type:int;not null"`
+	NftDfId     int64  `gorm:"index:airdropnfts_nftdfid;type:int;not null"`
+	NftTbSig    string `gorm:"size:132;not null"`
+	NftDfSig    string `gorm:"size:132;not null"`
+	IsClaimTb   bool   `gorm:"type:int(8);not null"`
+	IsClaimDf   bool   `gorm:"type:int(8);not null"`
+}
+
+type AssetStatistic struct {
+	Id             int64          `gorm:"primaryKey;autoIncrement"`
+	Amount         *models.BigInt `gorm:"type:varchar(64);not null"`
+	Txnum          uint64         `gorm:"type:bigint(20);not null"`
+	Addressnum     uint64         `gorm:"type:bigint(20);not null"`
+	TokenBasicName string         `gorm:"uniqueIndex;size:64;not null"`
+	AmountBtc      *models.BigInt `gorm:"type:varchar(64);not null"`
+	AmountUsd      *models.BigInt `gorm:"type:varchar(64);not null"`
+	LastCheckId    int64          `gorm:"type:int"`
+	TokenBasic     *TokenBasic    `gorm:"foreignKey:TokenBasicName;references:Name"`
+}
+
+type Chain struct {
+	Id                       int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId                  uint64 `gorm:"uniqueIndex;type:bigint(20);not null"`
+	Name                     string `gorm:"type:varchar(32)"`
+	Height                   uint64 `gorm:"type:bigint(20);not null"`
+	CrossChainSequenceNumber uint64 `gorm:"type:bigint(20);not null"`
+	ExecuteTxSequenceNumber  uint64 `gorm:"type:bigint(20);not null"`
+	HeightSwap               uint64 `gorm:"type:bigint(20);not null"`
+	BackwardBlockNumber      uint64 `gorm:"type:bigint(20);not null"`
+	ChainLogo                string `gorm:"type:varchar(128)"`
+	ChainExplorerUrl         string `gorm:"type:varchar(128)"`
+}
+
+type ChainBlock struct {
+	Id         int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId    uint64 `gorm:"uniqueIndex:idx_chain_block;type:bigint(20);not null"`
+	Height     uint64 `gorm:"uniqueIndex:idx_chain_block;type:bigint(20);not null"`
+	Hash       string `gorm:"size:66;not null"`
+	ParentHash string `gorm:"size:66;not null"`
+}
+
+type ChainFee struct {
+	Id             int64          `gorm:"primaryKey;autoIncrement"`
+	ChainId        uint64         `gorm:"uniqueIndex;type:bigint(20);not null"`
+	TokenBasicName string         `gorm:"size:64;not null"`
+	TokenBasic     *TokenBasic    `gorm:"foreignKey:TokenBasicName;references:Name"`
+	MaxFee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	MinFee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	ProxyFee       *models.BigInt `gorm:"type:varchar(64);not null"`
+	Ind            uint64         `gorm:"type:bigint(20);not null"`
+	Time           int64          `gorm:"type:bigint(20);not null"`
+}
+
+type ChainStatistic struct {
+	Id             int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId        uint64 `gorm:"uniqueIndex;type:bigint(20);not null"`
+	Addresses      int64  `gorm:"type:bigint(20);not null"`
+	In             int64  `gorm:"type:bigint(20);not null"`
+	Out            int64  `gorm:"type:bigint(20);not null"`
+	LastInCheckId  int64  `gorm:"type:int"`
+	LastOutCheckId int64  `gorm:"type:int"`
+}
+
+type DeadLetterBlock struct {
+	Id            int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId       uint64 `gorm:"uniqueIndex:idx_dead_letter_block;type:bigint(20);not null"`
+	StartHeight   uint64 `gorm:"uniqueIndex:idx_dead_letter_block;type:bigint(20);not null"`
+	EndHeight     uint64 `gorm:"type:bigint(20);not null"`
+	Error         string `gorm:"type:varchar(1024)"`
+	Attempts      uint64 `gorm:"type:bigint(20);not null"`
+	NextRetryTime int64  `gorm:"index;type:bigint(20);not null"`
+	Status        int    `gorm:"type:int;not null"`
+	CreateTime    int64  `gorm:"type:bigint(20);not null"`
+	UpdateTime    int64  `gorm:"type:bigint(20);not null"`
+}
+
+type DstSwap struct {
+	Id         int64          `gorm:"primaryKey;autoIncrement"`
+	TxHash     string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+	PoolId     uint64         `gorm:"type:bigint(20);not null"`
+	InAsset    string         `gorm:"type:varchar(120);not null"`
+	InAmount   *models.BigInt `gorm:"type:varchar(64);not null"`
+	OutAsset   string         `gorm:"type:varchar(120);not null"`
+	OutAmount  *models.BigInt `gorm:"type:varchar(64);not null"`
+	DstChainId uint64         `gorm:"type:bigint(20);not null"`
+	DstAsset   string         `gorm:"type:varchar(120);not null"`
+	DstUser    string         `gorm:"type:varchar(66);not null"`
+	Type       uint64         `gorm:"type:bigint(20);not null"`
+}
+
+type DstTransaction struct {
+	Id          int64          `gorm:"primaryKey;autoIncrement"`
+	Hash        string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId     uint64         `gorm:"type:bigint(20);not null"`
+	Standard    uint8          `gorm:"type:int(8);not null"`
+	State       uint64         `gorm:"type:bigint(20);not null"`
+	Time        uint64         `gorm:"type:bigint(20);not null"`
+	Fee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height      uint64         `gorm:"type:bigint(20);not null"`
+	SrcChainId  uint64         `gorm:"type:bigint(20);not null"`
+	Contract    string         `gorm:"type:varchar(66);not null"`
+	PolyHash    string         `gorm:"index;size:66;not null"`
+	Sequence    uint64         `gorm:"type:bigint(20);not null"`
+	DstTransfer *DstTransfer   `gorm:"foreignKey:TxHash;references:Hash"`
+	DstSwap     *DstSwap       `gorm:"foreignKey:TxHash;references:Hash"`
+}
+
+type DstTransfer struct {
+	Id       int64          `gorm:"primaryKey;autoIncrement"`
+	TxHash   string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId  uint64         `gorm:"type:bigint(20);not null"`
+	Standard uint8          `gorm:"type:int(8);not null"`
+	Time     uint64         `gorm:"type:bigint(20);not null"`
+	Asset    string         `gorm:"type:varchar(120);not null"`
+	From     string         `gorm:"type:varchar(66);not null"`
+	To       string         `gorm:"type:varchar(66);not null"`
+	Amount   *models.BigInt `gorm:"type:varchar(80);not null"`
+}
+
+type LockTokenStatistic struct {
+	Id          int64          `gorm:"primaryKey;autoIncrement"`
+	Hash        string         `gorm:"uniqueIndex:idx_locktoken;size:66;not null"`
+	ChainId     uint64         `gorm:"uniqueIndex:idx_locktoken;type:bigint(20);not null"`
+	ItemProxy   string         `gorm:"uniqueIndex:idx_locktoken;type:varchar(66);not null"`
+	ItemName    string         `gorm:"type:varchar(32);not null"`
+	InAmount    *models.BigInt `gorm:"type:varchar(64);not null"`
+	InAmountBtc *models.BigInt `gorm:"type:varchar(64);not null"`
+	InAmountUsd *models.BigInt `gorm:"type:varchar(64);not null"`
+	UpdateTime  uint64         `gorm:"type:bigint(20);not null"`
+	Token       *Token         `gorm:"foreignKey:Hash,ChainId;references:Hash,ChainId"`
+}
+
+type NFTProfile struct {
+	Id             int64  `gorm:"primaryKey;autoIncrement"`
+	TokenBasicName string `gorm:"uniqueIndex:idx_name_token;size:64;not null"`
+	NftTokenId     string `gorm:"uniqueIndex:idx_name_token;type:varchar(64);not null"`
+	Name           string `gorm:"size:64;not null"`
+	Url            string `gorm:"type:varchar(256)"`
+	Image          string `gorm:"type:varchar(256);not null"`
+	Description    string `gorm:"type:varchar(256)"`
+	Text           string `gorm:"type:text"`
+}
+
+type NftUser struct {
+	Id              int64          `gorm:"primaryKey;autoIncrement"`
+	ColChainId      uint64         `gorm:"type:bigint(20);not null"`
+	DfChainId       uint64         `gorm:"type:bigint(20)"`
+	AddrHash        string         `gorm:"type:varchar(66);not null"`
+	ColAddress      string         `gorm:"uniqueIndex:nftusers_coladdress;type:varchar(66);not null"`
+	DfAddress       string         `gorm:"index:nftusers_dfaddress;type:varchar(66)"`
+	Txnum           uint64         `gorm:"type:bigint(20);not null"`
+	FirstTime       uint64         `gorm:"type:bigint(20);not null"`
+	TxAmountUsd     *models.BigInt `gorm:"type:varchar(64);not null"`
+	EffectAmountUsd *models.BigInt `gorm:"type:varchar(64);not null"`
+	NftColId        int            `gorm:"index:nftusers_nftcolid;type:int;not null"`
+	NftDfId         int            `gorm:"index:nftusers_nftdfid;type:int"`
+	NftColsig       string         `gorm:"size:132;not null"`
+	NftDfsig        string         `gorm:"size:132"`
+	IsClaimCol      uint64         `gorm:"type:bigint(20);not null"`
+	IsClaimDf       uint64         `gorm:"type:bigint(20);not null"`
+}
+
+type PolyDetail struct {
+	Id          int64          `gorm:"primaryKey;autoIncrement"`
+	Hash        string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId     uint64         `gorm:"type:bigint(20);not null"`
+	State       uint64         `gorm:"type:bigint(20);not null"`
+	Time        uint64         `gorm:"type:bigint(20);not null"`
+	Fee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height      uint64         `gorm:"type:bigint(20);not null"`
+	SrcChainId  uint64         `gorm:"type:bigint(20);not null"`
+	SrcHash     string         `gorm:"index;size:66;not null"`
+	DstChainId  uint64         `gorm:"type:bigint(20);not null"`
+	Key         string         `gorm:"type:varchar(8192);not null"`
+	DstSequence uint64         `gorm:"type:bigint(20);not null"`
+}
+
+type PolyTransaction struct {
+	Id          int64          `gorm:"primaryKey;autoIncrement"`
+	Hash        string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId     uint64         `gorm:"type:bigint(20);not null"`
+	State       uint64         `gorm:"type:bigint(20);not null"`
+	Time        uint64         `gorm:"type:bigint(20);not null"`
+	Fee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height      uint64         `gorm:"type:bigint(20);not null"`
+	SrcChainId  uint64         `gorm:"type:bigint(20);not null"`
+	SrcHash     string         `gorm:"index;size:66;not null"`
+	DstChainId  uint64         `gorm:"type:bigint(20);not null"`
+	Key         string         `gorm:"type:varchar(8192);not null"`
+	DstSequence uint64         `gorm:"type:bigint(20);not null"`
+}
+
+type PriceMarket struct {
+	Id             int64       `gorm:"primaryKey;autoIncrement"`
+	TokenBasicName string      `gorm:"uniqueIndex:idx_tokenmarket;size:64;not null"`
+	MarketName     string      `gorm:"uniqueIndex:idx_tokenmarket;size:64;not null"`
+	CoinMarketId   int         `gorm:"type:int(32)"`
+	Name           string      `gorm:"size:64;not null"`
+	Price          int64       `gorm:"type:bigint(20);not null"`
+	Ind            uint64      `gorm:"type:bigint(20);not null"`
+	Time           int64       `gorm:"type:bigint(20);not null"`
+	Rank           int         `gorm:"type:int(8);not null"`
+	TokenBasic     *TokenBasic `gorm:"foreignKey:TokenBasicName;references:Name"`
+}
+
+type SrcSwap struct {
+	Id         int64          `gorm:"primaryKey;autoIncrement"`
+	TxHash     string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+	Asset      string         `gorm:"type:varchar(120);not null"`
+	From       string         `gorm:"type:varchar(66);not null"`
+	To         string         `gorm:"type:varchar(66);not null"`
+	Amount     *models.BigInt `gorm:"type:varchar(64);not null"`
+	PoolId     uint64         `gorm:"type:bigint(20);not null"`
+	DstChainId uint64         `gorm:"type:bigint(20);not null"`
+	DstAsset   string         `gorm:"type:varchar(120);not null"`
+	DstUser    string         `gorm:"type:varchar(66);not null"`
+	Type       uint64         `gorm:"type:bigint(20);not null"`
+}
+
+type SrcTransaction struct {
+	Id          int64          `gorm:"primaryKey;autoIncrement"`
+	Hash        string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId     uint64         `gorm:"type:bigint(20);not null"`
+	Standard    uint8          `gorm:"type:int(8);not null"`
+	State       uint64         `gorm:"type:bigint(20);not null"`
+	Time        uint64         `gorm:"type:bigint(20);not null"`
+	Fee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height      uint64         `gorm:"type:bigint(20);not null"`
+	User        string         `gorm:"type:varchar(66);not null"`
+	DstChainId  uint64         `gorm:"type:bigint(20);not null"`
+	Contract    string         `gorm:"type:varchar(66);not null"`
+	Key         string         `gorm:"index;size:128;not null"`
+	Param       string         `gorm:"type:varchar(8192);not null"`
+	SrcTransfer *SrcTransfer   `gorm:"foreignKey:TxHash;references:Hash"`
+	SrcSwap     *SrcSwap       `gorm:"foreignKey:TxHash;references:Hash"`
+}
+
+type SrcTransfer struct {
+	Id         int64          `gorm:"primaryKey;autoIncrement"`
+	TxHash     string         `gorm:"uniqueIndex;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	Standard   uint8          `gorm:"type:int(8);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+	Asset      string         `gorm:"type:varchar(120);not null"`
+	From       string         `gorm:"type:varchar(66);not null"`
+	To         string         `gorm:"type:varchar(66);not null"`
+	Amount     *models.BigInt `gorm:"type:varchar(80);not null"`
+	DstChainId uint64         `gorm:"type:bigint(20);not null"`
+	DstAsset   string         `gorm:"type:varchar(120);not null"`
+	DstUser    string         `gorm:"type:varchar(66);not null"`
+	Token      *Token         `gorm:"foreignKey:Hash,ChainId;references:Asset,ChainId"`
+}
+
+type TimeStatistic struct {
+	Id         int64  `gorm:"primaryKey;autoIncrement"`
+	SrcChainId uint64 `gorm:"uniqueIndex:idx_chains;type:bigint(20);not null"`
+	DstChainId uint64 `gorm:"uniqueIndex:idx_chains;type:bigint(20);not null"`
+	Time       uint64 `gorm:"type:bigint(20);not null"`
+}
+
+type Token struct {
+	Id              int64          `gorm:"primaryKey;autoIncrement"`
+	Hash            string         `gorm:"uniqueIndex:idx_token;size:120;not null"`
+	ChainId         uint64         `gorm:"uniqueIndex:idx_token;type:bigint(20);not null"`
+	Name            string         `gorm:"size:64;not null"`
+	Precision       uint64         `gorm:"type:bigint(20);not null"`
+	TokenBasicName  string         `gorm:"size:64;not null"`
+	Property        int64          `gorm:"type:bigint(20);not null"`
+	Standard        uint8          `gorm:"type:int(8);not null"`
+	TokenType       string         `gorm:"type:varchar(32)"`
+	AvailableAmount *models.BigInt `gorm:"type:varchar(64)"`
+	TokenBasic      *TokenBasic    `gorm:"foreignKey:TokenBasicName;references:Name"`
+	TokenMaps       []*TokenMap    `gorm:"foreignKey:SrcTokenHash,SrcChainId;references:Hash,ChainId"`
+}
+
+type TokenBasic struct {
+	Id              int64          `gorm:"primaryKey;autoIncrement"`
+	Name            string         `gorm:"uniqueIndex;size:64;not null"`
+	Precision       uint64         `gorm:"type:bigint(20);not null"`
+	Price           int64          `gorm:"size:64;not null"`
+	ChainId         uint64         `gorm:"type:bigint(20);not null"` //该tokenbasicname的源链ID
+	Ind             uint64         `gorm:"type:bigint(20);not null"` // 显示价格是否可用
+	Time            int64          `gorm:"type:bigint(20);not null"`
+	Property        int64          `gorm:"type:bigint(20);not null"` // token是否上线, 1为上线
+	Standard        uint8          `gorm:"type:int(8);not null"`     // 0为erc20， 1为erc721
+	Meta            string         `gorm:"type:varchar(128)"`
+	TotalAmount     *models.BigInt `gorm:"type:varchar(64)"`
+	TotalCount      uint64         `gorm:"type:bigint(20)"`
+	StatsUpdateTime int64          `gorm:"type:bigint(20)"`
+	SocialTwitter   string         `gorm:"type:varchar(256)"`
+	SocialTelegram  string         `gorm:"type:varchar(256)"`
+	SocialWebsite   string         `gorm:"type:varchar(256)"`
+	SocialOther     string         `gorm:"type:varchar(256)"`
+	MetaFetcherType int            `gorm:"type:int(8);not null"` // nft meta profile fetcher type, e.g: unknown 0, opensea: 1, standard: 2,
+	Rank            int            `gorm:"type:int(8);not null"`
+	PriceMarkets    []*PriceMarket `gorm:"foreignKey:TokenBasicName;references:Name"`
+	Tokens          []*Token       `gorm:"foreignKey:TokenBasicName;references:Name"`
+}
+
+type TokenMap struct {
+	Id           int64  `gorm:"primaryKey;autoIncrement"`
+	SrcChainId   uint64 `gorm:"uniqueIndex:idx_token_map;type:bigint(20);not null"`
+	SrcTokenHash string `gorm:"uniqueIndex:idx_token_map;size:120;not null"`
+	DstChainId   uint64 `gorm:"uniqueIndex:idx_token_map;type:bigint(20);not null"`
+	DstTokenHash string `gorm:"uniqueIndex:idx_token_map;size:120;not null"`
+	SrcToken     *Token `gorm:"foreignKey:SrcTokenHash,SrcChainId;references:Hash,ChainId"`
+	DstToken     *Token `gorm:"foreignKey:DstTokenHash,DstChainId;references:Hash,ChainId"`
+	Standard     uint8  `gorm:"type:int(8);not null"`
+	Property     int64  `gorm:"type:bigint(20);not null"`
+}
+
+type TokenPriceAvg struct {
+	Id          int64  `gorm:"primaryKey;autoIncrement"`
+	Name        string `gorm:"uniqueIndex;size:64;not null"`
+	PriceAvg    int64  `gorm:"type:bigint(20);not null"`
+	UpdateTime  int64  `gorm:"type:bigint(20);not null"`
+	PriceTotal  int64  `gorm:"type:bigint(20);not null"`
+	PriceNumber int64  `gorm:"type:bigint(20);not null"`
+	PriceTime   int64  `gorm:"type:bigint(20);not null"`
+}
+
+type TokenStatistic struct {
+	Id             int64          `gorm:"primaryKey;autoIncrement"`
+	Hash           string         `gorm:"uniqueIndex:idx_token_statistic;size:120;not null"`
+	ChainId        uint64         `gorm:"uniqueIndex:idx_token_statistic;type:bigint(20);not null"`
+	InCounter      int64          `gorm:"type:bigint(20)"`
+	InAmount       *models.BigInt `gorm:"type:varchar(64)"`
+	InAmountBtc    *models.BigInt `gorm:"type:varchar(64)"`
+	InAmountUsd    *models.BigInt `gorm:"type:varchar(64)"`
+	OutCounter     int64          `gorm:"type:bigint(20)"`
+	OutAmount      *models.BigInt `gorm:"type:varchar(64)"`
+	OutAmountBtc   *models.BigInt `gorm:"type:varchar(64)"`
+	OutAmountUsd   *models.BigInt `gorm:"type:varchar(64)"`
+	LastInCheckId  int64          `gorm:"type:int;not null"`
+	LastOutCheckId int64          `gorm:"type:int;not null"`
+	Token          *Token         `gorm:"foreignKey:Hash,ChainId;references:Hash,ChainId"`
+}
+
+type WrapperDetail struct {
+	Id           int64          `gorm:"primaryKey;autoIncrement"`
+	WrapperHash  string         `gorm:"index:wrapper_details_wrapper_hash;size:66;not null"`
+	Hash         string         `gorm:"uniqueIndex;size:66;not null"`
+	User         string         `gorm:"type:varchar(66);not null"`
+	SrcChainId   uint64         `gorm:"type:bigint(20);not null"`
+	Standard     uint8          `gorm:"type:int(8);not null"`
+	BlockHeight  uint64         `gorm:"type:bigint(20);not null"`
+	Time         uint64         `gorm:"type:bigint(20);not null"`
+	DstChainId   uint64         `gorm:"type:bigint(20);not null"`
+	DstUser      string         `gorm:"type:varchar(66);not null"`
+	ServerId     uint64         `gorm:"type:bigint(20);not null"`
+	FeeTokenHash string         `gorm:"size:66;not null"`
+	FeeAmount    *models.BigInt `gorm:"type:varchar(64);not null"`
+	Status       uint64         `gorm:"type:bigint(20);not null"`
+}
+
+type WrapperTransaction struct {
+	Id           int64          `gorm:"primaryKey;autoIncrement"`
+	Hash         string         `gorm:"uniqueIndex;size:66;not null"`
+	User         string         `gorm:"type:varchar(66);not null"`
+	SrcChainId   uint64         `gorm:"type:bigint(20);not null"`
+	Standard     uint8          `gorm:"type:int(8);not null"`
+	BlockHeight  uint64         `gorm:"type:bigint(20);not null"`
+	Time         uint64         `gorm:"type:bigint(20);not null"`
+	DstChainId   uint64         `gorm:"type:bigint(20);not null"`
+	DstUser      string         `gorm:"type:varchar(66);not null"`
+	ServerId     uint64         `gorm:"type:bigint(20);not null"`
+	FeeTokenHash string         `gorm:"size:66;not null"`
+	FeeAmount    *models.BigInt `gorm:"type:varchar(64);not null"`
+	Status       uint64         `gorm:"type:bigint(20);not null"`
+	IsPaid       bool           `gorm:"type:tinyint(1);not null"`
+	PaidGas      *models.BigInt `gorm:"type:varchar(64);not null"`
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a versioned change of the schema, a migration without Down cannot be reverted
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration is an applied migration
+type SchemaMigration struct {
+	Version   uint64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:128;not null"`
+	AppliedAt int64  `gorm:"type:bigint(20);not null"`
+}
+
+// MigrationStatus is a migration of the binary or of the database, AppliedAt is 0 when it is pending
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	AppliedAt int64
+	Unknown   bool // applied by a newer binary
+}
+
+// Migrations are the migrations of the binary in the order of their versions
+func Migrations() []*Migration {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// Latest is the version of the schema of the binary
+func Latest() uint64 {
+	all := Migrations()
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// applied loads the applied migrations, none of them without the table of the migrations, which is created by Up
+func applied(db *gorm.DB) (map[uint64]*SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return make(map[uint64]*SchemaMigration), nil
+	}
+	schemaMigrations := make([]*SchemaMigration, 0)
+	if err := db.Order("version").Find(&schemaMigrations).Error; err != nil {
+		return nil, err
+	}
+	versions := make(map[uint64]*SchemaMigration, len(schemaMigrations))
+	for _, v := range schemaMigrations {
+		versions[v.Version] = v
+	}
+	return versions, nil
+}
+
+// Status lists the migrations of the binary and the ones applied by newer binaries
+func Status(db *gorm.DB) ([]*MigrationStatus, error) {
+	versions, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]*MigrationStatus, 0, len(versions))
+	for _, migration := range Migrations() {
+		v := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if schemaMigration, ok := versions[migration.Version]; ok {
+			v.AppliedAt = schemaMigration.AppliedAt
+			delete(versions, migration.Version)
+		}
+		status = append(status, v)
+	}
+	for _, schemaMigration := range versions {
+		status = append(status, &MigrationStatus{
+			Version:   schemaMigration.Version,
+			Name:      schemaMigration.Name,
+			AppliedAt: schemaMigration.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Version < status[j].Version
+	})
+	return status, nil
+}
+
+// Pending are the migrations of the binary which are not applied yet
+func Pending(db *gorm.DB) ([]*Migration, error) {
+	versions, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]*Migration, 0)
+	for _, migration := range Migrations() {
+		if _, ok := versions[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations up to the version, all of them when the version is 0
+func Up(db *gorm.DB, to uint64) ([]*Migration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0, len(pending))
+	for _, migration := range pending {
+		if to != 0 && migration.Version > to {
+			break
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().Unix()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s up err: %v", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the applied migrations after the version, from the newest one
+func Down(db *gorm.DB, to uint64) ([]*Migration, error) {
+	versions, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	all := Migrations()
+	done := make([]*Migration, 0)
+	for i := len(all) - 1; i >= 0; i-- {
+		migration := all[i]
+		if migration.Version <= to {
+			break
+		}
+		if _, ok := versions[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return done, fmt.Errorf("migration %d %s cannot be reverted", migration.Version, migration.Name)
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s down err: %v", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Check tells whether the schema is behind the binary
+func Check(db *gorm.DB) error {
+	pending, err := Pending(db)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("schema is behind the binary, %d migrations are pending from version %d %s, apply them with bridge_tools migrate up",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
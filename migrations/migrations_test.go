@@ -0,0 +1,120 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"poly-bridge/models"
+)
+
+func openSqlite(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	assert.NoError(t, err)
+	return db
+}
+
+func TestMigrations(t *testing.T) {
+	all := Migrations()
+	for i, migration := range all {
+		assert.NotEmpty(t, migration.Name)
+		assert.NotNil(t, migration.Up, migration.Name)
+		if i > 0 {
+			assert.Greater(t, migration.Version, all[i-1].Version, "versions must be unique")
+		}
+	}
+	assert.Equal(t, all[len(all)-1].Version, Latest())
+}
+
+func TestUpDown(t *testing.T) {
+	db := openSqlite(t, "migrations_up_down")
+	assert.Error(t, Check(db))
+	// the check does not change the schema
+	assert.False(t, db.Migrator().HasTable(&SchemaMigration{}))
+
+	done, err := Up(db, 1)
+	assert.NoError(t, err)
+	assert.Len(t, done, 1)
+	for _, table := range models.Tables() {
+		assert.True(t, db.Migrator().HasTable(table))
+	}
+	// the baseline creates the tables as they were, the later columns are added by their migrations
+	assert.False(t, db.Migrator().HasColumn(&models.DstTransaction{}, "GasUsed"))
+	assert.False(t, db.Migrator().HasColumn(&models.ChainFee{}, "L1Fee"))
+	assert.Error(t, Check(db))
+
+	done, err = Up(db, 0)
+	assert.NoError(t, err)
+	assert.Len(t, done, len(Migrations())-1)
+	assert.NoError(t, Check(db))
+	assert.True(t, db.Migrator().HasTable(&models.WebhookDelivery{}))
+	assert.True(t, db.Migrator().HasTable(&models.FeeQuote{}))
+	assert.True(t, db.Migrator().HasTable(&models.GasLimitStat{}))
+	assert.True(t, db.Migrator().HasColumn(&models.DstTransaction{}, "GasUsed"))
+	assert.True(t, db.Migrator().HasColumn(&models.ChainFee{}, "L1Fee"))
+	assert.True(t, db.Migrator().HasColumn(&models.ChainFee{}, "L1FeeTime"))
+	assert.True(t, db.Migrator().HasTable(&models.ChainFeeHistory{}))
+	assert.True(t, db.Migrator().HasTable(&models.TokenPriceHistory{}))
+	done, err = Up(db, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, done)
+
+	done, err = Down(db, 1)
+	assert.NoError(t, err)
+	assert.Len(t, done, len(Migrations())-1)
+	pending, err := Pending(db)
+	assert.NoError(t, err)
+	assert.Len(t, pending, len(Migrations())-1)
+	assert.False(t, db.Migrator().HasTable(&models.Webhook{}))
+	assert.False(t, db.Migrator().HasTable(&models.FeeQuote{}))
+	assert.False(t, db.Migrator().HasTable(&models.GasLimitStat{}))
+	assert.False(t, db.Migrator().HasColumn(&models.DstTransaction{}, "GasUsed"))
+	assert.False(t, db.Migrator().HasColumn(&models.ChainFee{}, "L1Fee"))
+	assert.False(t, db.Migrator().HasColumn(&models.ChainFee{}, "L1FeeTime"))
+	assert.False(t, db.Migrator().HasTable(&models.ChainFeeHistory{}))
+	assert.False(t, db.Migrator().HasTable(&models.TokenPriceHistory{}))
+
+	// the tables are not dropped by the baseline
+	_, err = Down(db, 0)
+	assert.Error(t, err)
+	assert.True(t, db.Migrator().HasTable(&models.SrcTransaction{}))
+}
+
+func TestStatus(t *testing.T) {
+	db := openSqlite(t, "migrations_status")
+	_, err := Up(db, 0)
+	assert.NoError(t, err)
+	// applied by a newer binary
+	assert.NoError(t, db.Create(&SchemaMigration{Version: Latest() + 1, Name: "newer", AppliedAt: 1}).Error)
+
+	status, err := Status(db)
+	assert.NoError(t, err)
+	if assert.Len(t, status, len(Migrations())+1) {
+		for _, v := range status[:len(status)-1] {
+			assert.Greater(t, v.AppliedAt, int64(0))
+			assert.False(t, v.Unknown)
+		}
+		assert.True(t, status[len(status)-1].Unknown)
+		assert.Equal(t, "newer", status[len(status)-1].Name)
+	}
+	assert.NoError(t, Check(db))
+}
+
+func TestRenameDuplicatedIndexes(t *testing.T) {
+	db := openSqlite(t, "migrations_rename_indexes")
+	_, err := Up(db, 1)
+	assert.NoError(t, err)
+	// the index of token_statistics of a mysql database created before the rename, sqlite names the indexes of all tables at once
+	assert.NoError(t, db.Migrator().DropIndex(&models.Token{}, "idx_token"))
+	assert.NoError(t, db.Exec("CREATE UNIQUE INDEX idx_token ON token_statistics(hash, chain_id)").Error)
+
+	_, err = Up(db, 2)
+	assert.NoError(t, err)
+	assert.False(t, db.Migrator().HasIndex(&models.TokenStatistic{}, "idx_token"))
+	assert.True(t, db.Migrator().HasIndex(&models.TokenStatistic{}, "idx_token_statistic"))
+}
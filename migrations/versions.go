@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migrations
+
+import (
+	"poly-bridge/migrations/baseline"
+	"poly-bridge/models"
+
+	"gorm.io/gorm"
+)
+
+// migrations change the schema with the gorm migrator, so that they run on every dialect.
+// A new change of the models gets a new migration, the applied ones must not be edited.
+var migrations = []*Migration{
+	{
+		// the tables of the models, which were created by deploy and the migrate methods of bridge_tools before.
+		// An existing database only gets its missing tables, columns and indexes.
+		Version: 1,
+		Name:    "create_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(baseline.Tables()...)
+		},
+	},
+	{
+		// the indexes of token_statistics and air_drop_nfts had the names of the indexes of tokens and nft_users
+		Version: 2,
+		Name:    "rename_duplicated_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, index := range []struct {
+				model interface{}
+				name  string
+			}{
+				{&models.TokenStatistic{}, "idx_token"},
+				{&models.AirDropNft{}, "nftusers_nftcolid"},
+				{&models.AirDropNft{}, "nftusers_nftdfid"},
+			} {
+				if !tx.Migrator().HasIndex(index.model, index.name) {
+					continue
+				}
+				if err := tx.Migrator().DropIndex(index.model, index.name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			// the dropped indexes were duplicates of the renamed ones
+			return nil
+		},
+	},
+	{
+		// the webhooks of the partners and the log of their deliveries
+		Version: 3,
+		Name:    "create_webhooks",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.Webhook{}, &models.WebhookDelivery{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.WebhookDelivery{}, &models.Webhook{})
+		},
+	},
+	{
+		// the signed fee quotes of getfee honoured by the fee checking
+		Version: 4,
+		Name:    "create_fee_quotes",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.FeeQuote{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.FeeQuote{})
+		},
+	},
+	{
+		// the gas used by the unlocks and the gas limits calibrated from it
+		Version: 5,
+		Name:    "create_gas_limit_stats",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.GasLimitStat{}); err != nil {
+				return err
+			}
+			return addColumn(tx, &models.DstTransaction{}, "GasUsed")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&models.GasLimitStat{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.DstTransaction{}, "GasUsed")
+		},
+	},
+	{
+		// the L1 data fees of the layer 2 chains read from their oracles
+		Version: 6,
+		Name:    "add_chain_fee_l1_fee",
+		Up: func(tx *gorm.DB) error {
+			return addColumn(tx, &models.ChainFee{}, "L1Fee")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&models.ChainFee{}, "L1Fee")
+		},
+	},
+	{
+		// the samples of the chain fees with the smoothed fees published
+		Version: 7,
+		Name:    "create_chain_fee_histories",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.ChainFeeHistory{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.ChainFeeHistory{})
+		},
+	},
+	{
+		// the prices of the token basics and their markets downsampled as they age
+		Version: 8,
+		Name:    "create_token_price_histories",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.TokenPriceHistory{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.TokenPriceHistory{})
+		},
+	},
+	{
+		// the time of the L1 fee read from the oracle, a stale one is scaled from ethereum instead
+		Version: 9,
+		Name:    "add_chain_fee_l1_fee_time",
+		Up: func(tx *gorm.DB) error {
+			return addColumn(tx, &models.ChainFee{}, "L1FeeTime")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&models.ChainFee{}, "L1FeeTime")
+		},
+	},
+}
+
+// addColumn adds the column of the field of the model unless the database has it already
+func addColumn(tx *gorm.DB, model interface{}, field string) error {
+	if tx.Migrator().HasColumn(model, field) {
+		return nil
+	}
+	return tx.Migrator().AddColumn(model, field)
+}
@@ -46,7 +46,7 @@ type AirDropReqData struct {
 }
 
 type AirDropReq struct {
-	Users []AirDropReqData
+	Users []AirDropReqData `valid:"MinSize(1);MaxSize(10)"`
 }
 
 type AirDropRspData struct {
@@ -105,7 +105,7 @@ func MakeAirDropRsp(addressReq AirDropReq, airDropInfos []*AirDropInfo, airDropR
 }
 
 type AirDropClaimReq struct {
-	AirDropAddrs []string
+	AirDropAddrs []string `valid:"MinSize(1);MaxSize(10)"`
 }
 
 type AirDropClaimNft struct {
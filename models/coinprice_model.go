@@ -71,6 +71,8 @@ type ChainFee struct {
 	MaxFee         *BigInt     `gorm:"type:varchar(64);not null"`
 	MinFee         *BigInt     `gorm:"type:varchar(64);not null"`
 	ProxyFee       *BigInt     `gorm:"type:varchar(64);not null"`
+	L1Fee          *BigInt     `gorm:"type:varchar(64);not null;default:0"`
+	L1FeeTime      int64       `gorm:"type:bigint(20);not null;default:0"`
 	Ind            uint64      `gorm:"type:bigint(20);not null"`
 	Time           int64       `gorm:"type:bigint(20);not null"`
 }
@@ -106,8 +108,8 @@ type Token struct {
 
 type TokenStatistic struct {
 	Id             int64   `gorm:"primaryKey;autoIncrement"`
-	Hash           string  `gorm:"uniqueIndex:idx_token;size:120;not null"`
-	ChainId        uint64  `gorm:"uniqueIndex:idx_token;type:bigint(20);not null"`
+	Hash           string  `gorm:"uniqueIndex:idx_token_statistic;size:120;not null"`
+	ChainId        uint64  `gorm:"uniqueIndex:idx_token_statistic;type:bigint(20);not null"`
 	InCounter      int64   `gorm:"type:bigint(20)"`
 	InAmount       *BigInt `gorm:"type:varchar(64)"`
 	InAmountBtc    *BigInt `gorm:"type:varchar(64)"`
@@ -30,6 +30,27 @@ type Chain struct {
 	ChainExplorerUrl         string `gorm:"type:varchar(128)"`
 }
 
+type ChainBlock struct {
+	Id         int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId    uint64 `gorm:"uniqueIndex:idx_chain_block;type:bigint(20);not null"`
+	Height     uint64 `gorm:"uniqueIndex:idx_chain_block;type:bigint(20);not null"`
+	Hash       string `gorm:"size:66;not null"`
+	ParentHash string `gorm:"size:66;not null"`
+}
+
+type DeadLetterBlock struct {
+	Id            int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId       uint64 `gorm:"uniqueIndex:idx_dead_letter_block;type:bigint(20);not null"`
+	StartHeight   uint64 `gorm:"uniqueIndex:idx_dead_letter_block;type:bigint(20);not null"`
+	EndHeight     uint64 `gorm:"type:bigint(20);not null"`
+	Error         string `gorm:"type:varchar(1024)"`
+	Attempts      uint64 `gorm:"type:bigint(20);not null"`
+	NextRetryTime int64  `gorm:"index;type:bigint(20);not null"`
+	Status        int    `gorm:"type:int;not null"`
+	CreateTime    int64  `gorm:"type:bigint(20);not null"`
+	UpdateTime    int64  `gorm:"type:bigint(20);not null"`
+}
+
 type ChainStatistic struct {
 	Id             int64  `gorm:"primaryKey;autoIncrement"`
 	ChainId        uint64 `gorm:"uniqueIndex;type:bigint(20);not null"`
@@ -147,6 +168,7 @@ type DstTransaction struct {
 	Contract    string       `gorm:"type:varchar(66);not null"`
 	PolyHash    string       `gorm:"index;size:66;not null"`
 	Sequence    uint64       `gorm:"type:bigint(20);not null"`
+	GasUsed     uint64       `gorm:"type:bigint(20);not null;default:0"`
 	DstTransfer *DstTransfer `gorm:"foreignKey:TxHash;references:Hash"`
 	DstSwap     *DstSwap     `gorm:"foreignKey:TxHash;references:Hash"`
 }
@@ -282,6 +304,7 @@ type AssetInfo struct {
 	Price          int64
 	TokenBasicName string
 	Precision      uint64
+	Day            int64
 }
 
 type TransactionOnToken struct {
@@ -336,8 +359,8 @@ type AirDropNft struct {
 	Rank        int64  `gorm:"type:bigint(20);not null"`
 	BindChainId uint64 `gorm:"type:bigint(20);not null"`
 	BindAddr    string `gorm:"uniqueIndex;type:varchar(66);not null"`
-	NftTbId     int64  `gorm:"index:nftusers_nftcolid;type:int;not null"`
-	NftDfId     int64  `gorm:"index:nftusers_nftdfid;type:int;not null"`
+	NftTbId     int64  `gorm:"index:airdropnfts_nfttbid;type:int;not null"`
+	NftDfId     int64  `gorm:"index:airdropnfts_nftdfid;type:int;not null"`
 	NftTbSig    string `gorm:"size:132;not null"`
 	NftDfSig    string `gorm:"size:132;not null"`
 	IsClaimTb   bool   `gorm:"type:int(8);not null"`
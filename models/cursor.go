@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// PageCursor is the position of the last row of a page ordered by time and id descending, or by id descending only.
+// The next page starts after it, so that it does not shift when new transactions arrive.
+type PageCursor struct {
+	Time uint64
+	Id   int64
+}
+
+// ParsePageCursor decodes the opaque cursor of a request, it is nil when the cursor is empty
+func ParsePageCursor(cursor string) (*PageCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %s: %v", cursor, err)
+	}
+	pageCursor := new(PageCursor)
+	if _, err = fmt.Sscanf(string(data), "%d:%d", &pageCursor.Time, &pageCursor.Id); err != nil {
+		return nil, fmt.Errorf("invalid cursor %s: %v", cursor, err)
+	}
+	return pageCursor, nil
+}
+
+func (c *PageCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.Time, c.Id)))
+}
+
+// NextPageCursor is the cursor after the last source transaction of a full page, it is empty on the last page
+func NextPageCursor(pageSize int, relations []*SrcPolyDstRelation) string {
+	if pageSize <= 0 || len(relations) < pageSize {
+		return ""
+	}
+	for i := len(relations) - 1; i >= 0; i-- {
+		if src := relations[i].SrcTransaction; src != nil {
+			return (&PageCursor{Time: src.Time, Id: src.Id}).String()
+		}
+	}
+	return ""
+}
@@ -38,6 +38,7 @@ type ECCMUnlockEvent struct {
 	Contract string
 	Height   uint64
 	Fee      uint64
+	GasUsed  uint64
 }
 type ProxyLockEvent struct {
 	BlockNumber   uint64
@@ -474,6 +474,8 @@ func MakeCrossTxResp(srcPolyDst *PolyTxRelation) *CrossTxResp {
 type CrossTxListReq struct {
 	PageSize int
 	PageNo   int
+	Cursor   string
+	NoCount  bool
 }
 
 type CrossTxOutlineResp struct {
@@ -493,6 +495,7 @@ type CrossTxOutlineResp struct {
 type CrossTxListResp struct {
 	CrossTxList []*CrossTxOutlineResp `json:"crosstxs"`
 	Total       int64                 `json:"total"`
+	NextCursor  string                `json:"next_cursor"`
 }
 
 func MakeCrossTxListResp(txs []*SrcPolyDstRelation, counter int64) *CrossTxListResp {
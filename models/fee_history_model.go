@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+// the states of the chain fee samples
+const (
+	FeeSampleAccepted = "accepted" // published smoothed with the latest accepted samples
+	FeeSampleAlerted  = "alerted"  // beyond the bound of the change and published still
+	FeeSampleRejected = "rejected" // beyond the bound of the change and not published
+)
+
+// ChainFeeHistory is a sample of the fees of a chain read by the fee listen, with the fees published by it.
+// MinFee, MaxFee and ProxyFee are the fees read, the published ones are smoothed from the accepted samples.
+type ChainFeeHistory struct {
+	Id                int64   `gorm:"primaryKey;autoIncrement"`
+	ChainId           uint64  `gorm:"index:idx_chain_fee_history,priority:1;type:bigint(20);not null"`
+	Time              int64   `gorm:"index:idx_chain_fee_history,priority:2;type:bigint(20);not null"`
+	MinFee            *BigInt `gorm:"type:varchar(64);not null"`
+	MaxFee            *BigInt `gorm:"type:varchar(64);not null"`
+	ProxyFee          *BigInt `gorm:"type:varchar(64);not null"`
+	L1Fee             *BigInt `gorm:"type:varchar(64);not null;default:0"`
+	PublishedMinFee   *BigInt `gorm:"type:varchar(64);not null"`
+	PublishedMaxFee   *BigInt `gorm:"type:varchar(64);not null"`
+	PublishedProxyFee *BigInt `gorm:"type:varchar(64);not null"`
+	State             string  `gorm:"size:16;not null"`
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+// FeeQuote is a fee quoted to a user for a route by getfee. The fee checking accepts a wrapper transaction of the user
+// on the route which pays the quoted amount of the fee token within the validity window of the quote, even if the
+// fee has risen since. Signature is the hmac of the fields by the server, the quotes without a valid one are ignored.
+type FeeQuote struct {
+	Id           string  `gorm:"primaryKey;size:32"`
+	User         string  `gorm:"index:idx_fee_quote_route,priority:1;size:66;not null"`
+	SrcChainId   uint64  `gorm:"index:idx_fee_quote_route,priority:2;type:bigint(20);not null"`
+	DstChainId   uint64  `gorm:"index:idx_fee_quote_route,priority:3;type:bigint(20);not null"`
+	FeeTokenHash string  `gorm:"index:idx_fee_quote_route,priority:4;size:66;not null"`
+	Amount       *BigInt `gorm:"type:varchar(64);not null"`
+	Time         int64   `gorm:"type:bigint(20);not null"`
+	ExpireTime   int64   `gorm:"index;type:bigint(20);not null"`
+	Signature    string  `gorm:"size:64;not null"`
+}
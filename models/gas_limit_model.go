@@ -0,0 +1,39 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+// the proxy types of the unlocks, whose gas limits are calibrated apart
+const (
+	ProxyTypeLock = "lock"
+	ProxyTypeNft  = "nft"
+	ProxyTypeSwap = "swap"
+)
+
+// GasLimitStat is the gas used by the latest unlocks of a proxy type on a chain observed by the calibration.
+// GasLimit is the calibrated gas limit, which is 0 without enough samples.
+type GasLimitStat struct {
+	Id         int64  `gorm:"primaryKey;autoIncrement"`
+	ChainId    uint64 `gorm:"uniqueIndex:idx_gas_limit_stat,priority:1;type:bigint(20);not null"`
+	ProxyType  string `gorm:"uniqueIndex:idx_gas_limit_stat,priority:2;size:16;not null"`
+	Samples    int64  `gorm:"type:bigint(20);not null"`
+	P50        uint64 `gorm:"type:bigint(20);not null"`
+	P90        uint64 `gorm:"type:bigint(20);not null"`
+	P99        uint64 `gorm:"type:bigint(20);not null"`
+	GasLimit   int64  `gorm:"type:bigint(20);not null"`
+	UpdateTime int64  `gorm:"type:bigint(20);not null"`
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import "sort"
+
+// TokenPriceHistory is the price of a token basic in a period, from a market or aggregated from the markets if
+// Market is empty. The prices of the updates are of Period 0 and are downsampled into the longer periods as they age.
+type TokenPriceHistory struct {
+	Id      int64  `gorm:"primaryKey;autoIncrement"`
+	Name    string `gorm:"index:idx_token_price_history,priority:1;size:64;not null"`
+	Market  string `gorm:"index:idx_token_price_history,priority:2;size:64;not null"`
+	Period  int64  `gorm:"index:idx_token_price_history,priority:3;type:bigint(20);not null"`
+	Time    int64  `gorm:"index:idx_token_price_history,priority:4;type:bigint(20);not null"`
+	Open    int64  `gorm:"type:bigint(20);not null"`
+	High    int64  `gorm:"type:bigint(20);not null"`
+	Low     int64  `gorm:"type:bigint(20);not null"`
+	Close   int64  `gorm:"type:bigint(20);not null"`
+	Samples int64  `gorm:"type:bigint(20);not null"`
+}
+
+func NewTokenPriceHistory(name string, market string, time int64, price int64) *TokenPriceHistory {
+	return &TokenPriceHistory{Name: name, Market: market, Time: time, Open: price, High: price, Low: price, Close: price, Samples: 1}
+}
+
+// Merge merges the prices of a later time into the prices
+func (history *TokenPriceHistory) Merge(later *TokenPriceHistory) {
+	if later.High > history.High {
+		history.High = later.High
+	}
+	if later.Low < history.Low {
+		history.Low = later.Low
+	}
+	history.Close = later.Close
+	history.Samples += later.Samples
+}
+
+// DownsampleTokenPrices merges the prices into the ones of the period by name, market and the time of the period,
+// in the order of the time
+func DownsampleTokenPrices(histories []*TokenPriceHistory, period int64) []*TokenPriceHistory {
+	sorted := make([]*TokenPriceHistory, len(histories))
+	copy(sorted, histories)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+	type bucket struct {
+		name   string
+		market string
+		time   int64
+	}
+	buckets := make(map[bucket]*TokenPriceHistory)
+	downsampled := make([]*TokenPriceHistory, 0)
+	for _, history := range sorted {
+		key := bucket{history.Name, history.Market, history.Time - history.Time%period}
+		if merged, ok := buckets[key]; ok {
+			merged.Merge(history)
+			continue
+		}
+		merged := *history
+		merged.Id = 0
+		merged.Period = period
+		merged.Time = key.time
+		buckets[key] = &merged
+		downsampled = append(downsampled, &merged)
+	}
+	return downsampled
+}
@@ -35,7 +35,22 @@ type PolyBridgeResp struct {
 }
 
 type ErrorRsp struct {
+	Code    string `json:",omitempty"`
 	Message string
+	Fields  []*FieldError `json:",omitempty"`
+}
+
+// FieldError is the error of a field of a request, an empty field is of the whole request
+type FieldError struct {
+	Field   string `json:",omitempty"`
+	Message string
+}
+
+func (e *FieldError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
 }
 
 func MakeErrorRsp(messgae string) *ErrorRsp {
@@ -45,6 +60,101 @@ func MakeErrorRsp(messgae string) *ErrorRsp {
 	return errorRsp
 }
 
+func MakeErrorCodeRsp(code string, message string) *ErrorRsp {
+	errorRsp := &ErrorRsp{
+		Code:    code,
+		Message: message,
+	}
+	return errorRsp
+}
+
+// RateLimitedRsp is the response of a request rejected by the quota of its client in the route group
+type RateLimitedRsp struct {
+	Code       string
+	Message    string
+	Group      string
+	RetryAfter int64 // seconds until a request is allowed again
+}
+
+func MakeRateLimitedRsp(group string, retryAfter int64) *RateLimitedRsp {
+	return &RateLimitedRsp{
+		Code:       basedef.ERROR_RATE_LIMITED,
+		Message:    fmt.Sprintf("rate limit of %s is exceeded, retry after %d seconds", group, retryAfter),
+		Group:      group,
+		RetryAfter: retryAfter,
+	}
+}
+
+type CircuitBreakReq struct {
+	Action     string `valid:"Required"` // pause or resume
+	Scope      string `valid:"Required"` // chain, route or token
+	ChainId    uint64
+	SrcChainId uint64
+	DstChainId uint64
+	Token      string // token basic name
+	Reason     string
+	Operator   string
+}
+
+type CircuitBreakRsp struct {
+	CircuitBreaks []*basedef.CircuitBreak
+	Logs          []string
+}
+
+func MakeCircuitBreakPausedRsp(circuit *basedef.CircuitBreak) *ErrorRsp {
+	return MakeErrorCodeRsp(basedef.ERROR_CIRCUIT_PAUSED, fmt.Sprintf("%s %s is paused: %s", circuit.Scope, circuit.Target, circuit.Reason))
+}
+
+type WebhookReq struct {
+	Partner  string `valid:"Required"`
+	Url      string `valid:"Required"`
+	Secret   string // HMAC key of the signatures of the payloads, generated when it is empty
+	ChainId  uint64
+	Token    string
+	Address  string
+	Contract string
+	Events   []string // wrapper, src, poly, dst or finished, all of them when it is empty
+}
+
+type WebhookRsp struct {
+	Id       int64
+	Partner  string
+	Url      string
+	Secret   string `json:",omitempty"`
+	ChainId  uint64
+	Token    string
+	Address  string
+	Contract string
+	Events   []string
+	Disabled bool
+	Time     int64
+}
+
+// MakeWebhookRsp shows the secret only when the webhook is registered
+func MakeWebhookRsp(webhook *Webhook, withSecret bool) *WebhookRsp {
+	rsp := &WebhookRsp{
+		Id:       webhook.Id,
+		Partner:  webhook.Partner,
+		Url:      webhook.Url,
+		ChainId:  webhook.ChainId,
+		Token:    webhook.Token,
+		Address:  webhook.Address,
+		Contract: webhook.Contract,
+		Events:   webhook.EventList(),
+		Disabled: webhook.Disabled,
+		Time:     webhook.Time,
+	}
+	if withSecret {
+		rsp.Secret = webhook.Secret
+	}
+	return rsp
+}
+
+type WebhookDeliveriesRsp struct {
+	WebhookId  int64
+	Deliveries []*WebhookDelivery
+}
+
 type TokenBasicReq struct {
 	Name string
 }
@@ -61,6 +171,49 @@ type TokenBasicRsp struct {
 	Tokens       []*TokenRsp
 }
 
+type TokenPriceHistoryReq struct {
+	Name     string
+	Market   string
+	Interval int64
+	Start    int64
+	End      int64
+}
+
+type TokenPriceCandleRsp struct {
+	Time    int64
+	Open    string
+	High    string
+	Low     string
+	Close   string
+	Samples int64
+}
+
+type TokenPriceHistoryRsp struct {
+	Name     string
+	Market   string
+	Interval int64
+	Prices   []*TokenPriceCandleRsp
+}
+
+func formatPrice(price int64) string {
+	return new(big.Float).Quo(new(big.Float).SetInt64(price), new(big.Float).SetInt64(basedef.PRICE_PRECISION)).String()
+}
+
+func MakeTokenPriceHistoryRsp(req *TokenPriceHistoryReq, histories []*TokenPriceHistory) *TokenPriceHistoryRsp {
+	rsp := &TokenPriceHistoryRsp{Name: req.Name, Market: req.Market, Interval: req.Interval, Prices: make([]*TokenPriceCandleRsp, 0)}
+	for _, history := range DownsampleTokenPrices(histories, req.Interval) {
+		rsp.Prices = append(rsp.Prices, &TokenPriceCandleRsp{
+			Time:    history.Time,
+			Open:    formatPrice(history.Open),
+			High:    formatPrice(history.High),
+			Low:     formatPrice(history.Low),
+			Close:   formatPrice(history.Close),
+			Samples: history.Samples,
+		})
+	}
+	return rsp
+}
+
 type TxHashChainIdPair struct {
 	SrcHash    string
 	PolyHash   string
@@ -118,7 +271,7 @@ func MakeTokenBasicsRsp(tokenBasics []*TokenBasic) *TokenBasicsRsp {
 }
 
 type TokenBasicsInfoReq struct {
-	PageSize int
+	PageSize int `valid:"Min(1)"`
 	PageNo   int
 	Order    string
 }
@@ -315,9 +468,10 @@ func MakeTokenMapsRsp(tokenMaps []*TokenMap) *TokenMapsRsp {
 
 type GetFeeReq struct {
 	SrcChainId    uint64
-	Hash          string
+	Hash          string `valid:"Required"`
 	DstChainId    uint64
 	SwapTokenHash string
+	User          string // sender of the transaction, the fee is quoted to it when given
 }
 
 type GetFeeRsp struct {
@@ -332,10 +486,13 @@ type GetFeeRsp struct {
 	BalanceWithPrecision     string
 	IsNative                 bool
 	NativeTokenAmount        string
+	QuoteId                  string // the quote of TokenAmountWithPrecision to the user, empty if it is not quoted
+	QuoteExpireTime          int64
+	QuoteSignature           string
 }
 
 func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount *big.Float, tokenAmount *big.Float, tokenAmountWithPrecision *big.Float,
-	swapTokenHash string, tokenBalance *big.Int, tokenBalancePrecision int, isNative bool, nativeTokenAmount *big.Float, feeTokenPricison uint64) *GetFeeRsp {
+	swapTokenHash string, tokenBalance *big.Int, tokenBalancePrecision int, isNative bool, nativeTokenAmount *big.Float, feeTokenPricison uint64, quote *FeeQuote) *GetFeeRsp {
 	getFeeRsp := &GetFeeRsp{
 		SrcChainId:               srcChainId,
 		Hash:                     hash,
@@ -352,12 +509,112 @@ func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount
 	if feeTokenPricison > 0 {
 		getFeeRsp.TokenAmount = fmt.Sprintf("%.*f", feeTokenPricison, tokenAmount)
 	}
+	if quote != nil {
+		getFeeRsp.QuoteId = quote.Id
+		getFeeRsp.QuoteExpireTime = quote.ExpireTime
+		getFeeRsp.QuoteSignature = quote.Signature
+	}
 	if getFeeRsp.DstChainId == basedef.BSC_CROSSCHAIN_ID {
 		logs.Info("tobscgetfee srcChain:%v swapTokenHash:%v feeAmount:%v", srcChainId, swapTokenHash, getFeeRsp.TokenAmount)
 	}
 	return getFeeRsp
 }
 
+// GetFeesReq gets the fees of the token of SrcChainId to each of DstChainIds and the fees of Routes in a batch
+type GetFeesReq struct {
+	SrcChainId  uint64
+	Hash        string
+	DstChainIds []uint64     `valid:"MaxSize(100)"`
+	Routes      []*GetFeeReq `valid:"MaxSize(100)"`
+	User        string       // sender of the transactions, the fees are quoted to it when given
+}
+
+// RouteFeeRsp is the fee of a route as getfee. UsdtAmount is BaseUsdtAmount multiplied by NftRatio if the ratio applies,
+// TokenAmount is UsdtAmount in the token plus the L1 fee of L1TokenAmount. Error is set instead for a route without fee.
+type RouteFeeRsp struct {
+	SrcChainId               uint64
+	Hash                     string
+	DstChainId               uint64
+	SwapTokenHash            string
+	BaseUsdtAmount           string
+	NftRatio                 string
+	UsdtAmount               string
+	L1UsdtAmount             string
+	L1TokenAmount            string
+	TokenAmount              string
+	TokenAmountWithPrecision string
+	QuoteId                  string
+	QuoteExpireTime          int64
+	QuoteSignature           string
+	Error                    *ErrorRsp `json:",omitempty"`
+}
+
+type GetFeesRsp struct {
+	Fees []*RouteFeeRsp
+}
+
+func MakeRouteFeeRsp(route *GetFeeReq, baseUsdtAmount, nftRatio, usdtAmount, l1UsdtAmount, l1TokenAmount, tokenAmount, tokenAmountWithPrecision *big.Float,
+	feeTokenPricison uint64, quote *FeeQuote) *RouteFeeRsp {
+	getFeeRsp := MakeGetFeeRsp(route.SrcChainId, route.Hash, route.DstChainId, usdtAmount, tokenAmount, tokenAmountWithPrecision,
+		route.SwapTokenHash, new(big.Int), 0, false, new(big.Float), feeTokenPricison, quote)
+	routeFeeRsp := &RouteFeeRsp{
+		SrcChainId:               route.SrcChainId,
+		Hash:                     route.Hash,
+		DstChainId:               route.DstChainId,
+		SwapTokenHash:            route.SwapTokenHash,
+		BaseUsdtAmount:           fmt.Sprintf("%v", baseUsdtAmount),
+		UsdtAmount:               getFeeRsp.UsdtAmount,
+		L1UsdtAmount:             fmt.Sprintf("%v", l1UsdtAmount),
+		L1TokenAmount:            fmt.Sprintf("%v", l1TokenAmount),
+		TokenAmount:              getFeeRsp.TokenAmount,
+		TokenAmountWithPrecision: getFeeRsp.TokenAmountWithPrecision,
+		QuoteId:                  getFeeRsp.QuoteId,
+		QuoteExpireTime:          getFeeRsp.QuoteExpireTime,
+		QuoteSignature:           getFeeRsp.QuoteSignature,
+	}
+	if nftRatio != nil {
+		routeFeeRsp.NftRatio = nftRatio.String()
+	}
+	return routeFeeRsp
+}
+
+func MakeRouteFeeErrorRsp(route *GetFeeReq, code string, message string) *RouteFeeRsp {
+	return &RouteFeeRsp{
+		SrcChainId:    route.SrcChainId,
+		Hash:          route.Hash,
+		DstChainId:    route.DstChainId,
+		SwapTokenHash: route.SwapTokenHash,
+		Error:         MakeErrorCodeRsp(code, message),
+	}
+}
+
+// GasLimitRsp compares the configured gas limit of a proxy type on a chain with the gas used by its unlocks,
+// GasLimit is the one the fees are of and Source tells whether it is the override, the observed or the configured one
+type GasLimitRsp struct {
+	ChainId            uint64
+	ChainName          string
+	ProxyType          string
+	ConfiguredGasLimit int64
+	OverrideGasLimit   int64
+	ObservedGasLimit   int64
+	Samples            int64
+	P50                uint64
+	P90                uint64
+	P99                uint64
+	GasLimit           int64
+	Source             string
+	UpdateTime         int64
+}
+
+type GasLimitsRsp struct {
+	GasLimits []*GasLimitRsp
+}
+
+type FeeHistoryRsp struct {
+	ChainId   uint64
+	Histories []*ChainFeeHistory
+}
+
 type CheckFeeReq struct {
 	Hash    string
 	ChainId uint64
@@ -447,12 +704,12 @@ func MakeWrapperTransactionRsp(transaction *WrapperTransaction) *WrapperTransact
 }
 
 type WrapperTransactionsReq struct {
-	PageSize int
+	PageSize int `valid:"Min(1)"`
 	PageNo   int
 }
 
 type WrapperTransactionsWithFilterReq struct {
-	PageSize   int
+	PageSize   int `valid:"Min(1)"`
 	PageNo     int
 	SrcChainId int
 	DstChainId int
@@ -481,7 +738,7 @@ func MakeWrapperTransactionsRsp(pageSize int, pageNo int, totalPage int, totalCo
 }
 
 type TransactionOfHashReq struct {
-	Hash string
+	Hash string `valid:"Required"`
 }
 
 type TransactionStateRsp struct {
@@ -982,6 +1239,8 @@ type TransactionsOfAddressReq struct {
 	Addresses []string
 	PageSize  int
 	PageNo    int
+	Cursor    string // NextCursor of the last page, PageNo is ignored when it is set
+	NoCount   bool
 }
 
 type TransactionsOfAddressWithFilterReq struct {
@@ -991,7 +1250,7 @@ type TransactionsOfAddressWithFilterReq struct {
 	DstChainId int
 	Assets     []string
 	Contracts  []string
-	PageSize   int
+	PageSize   int `valid:"Min(1)"`
 	PageNo     int
 }
 
@@ -1000,6 +1259,7 @@ type TransactionsOfAddressRsp struct {
 	PageNo       int
 	TotalPage    int
 	TotalCount   int
+	NextCursor   string
 	Transactions []*TransactionRsp
 }
 
@@ -1026,6 +1286,8 @@ type TransactionsOfStateReq struct {
 type TransactionsOfUnfinishedReq struct {
 	PageSize int
 	PageNo   int
+	Cursor   string
+	NoCount  bool
 }
 
 type TransactionsOfAssetReq struct {
@@ -1033,6 +1295,8 @@ type TransactionsOfAssetReq struct {
 	Chain    int
 	PageSize int
 	PageNo   int
+	Cursor   string
+	NoCount  bool
 }
 
 type CrossChainTransactionRsp struct {
@@ -1048,6 +1312,7 @@ type TransactionOfUnfinishedRsp struct {
 	PageNo       int
 	TotalPage    int
 	TotalCount   int
+	NextCursor   string
 	Transactions []*CrossChainTransactionRsp
 }
 
@@ -1319,7 +1584,7 @@ func MakeExpectTimeRsp(srcchainId uint64, dstchainid uint64, time uint64) *Expec
 }
 
 type TokenAssetReq struct {
-	NameOrHash string
+	NameOrHash string `valid:"Required"`
 }
 
 type AssetDetailRes struct {
@@ -1437,7 +1702,7 @@ func MakeBottxsRsp(pageSize int, pageNo int, totalPage int, totalCount int, tran
 }
 
 type ManualTxDataReq struct {
-	PolyHash string
+	PolyHash string `valid:"Required"`
 }
 
 type ManualTxDataResp struct {
@@ -1467,13 +1732,16 @@ type TxWithoutWrapperReq struct {
 	User     string
 	PageSize int
 	PageNo   int
+	Cursor   string
+	NoCount  bool
 }
 
 type TxWithoutWrapperRes struct {
-	Total    int64
-	PageSize int
-	PageNo   int
-	Txs      []*TxWithoutWrappertx
+	Total      int64
+	PageSize   int
+	PageNo     int
+	NextCursor string
+	Txs        []*TxWithoutWrappertx
 }
 
 type TxWithoutWrappertx struct {
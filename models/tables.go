@@ -0,0 +1,35 @@
+package models
+
+// Tables returns the models of the tables of the baseline schema, the tables added later are created by their own migrations
+func Tables() []interface{} {
+	return []interface{}{
+		&AirDropInfo{},
+		&AirDropNft{},
+		&AssetStatistic{},
+		&Chain{},
+		&ChainBlock{},
+		&ChainFee{},
+		&ChainStatistic{},
+		&DeadLetterBlock{},
+		&DstSwap{},
+		&DstTransaction{},
+		&DstTransfer{},
+		&LockTokenStatistic{},
+		&NFTProfile{},
+		&NftUser{},
+		&PolyDetail{},
+		&PolyTransaction{},
+		&PriceMarket{},
+		&SrcSwap{},
+		&SrcTransaction{},
+		&SrcTransfer{},
+		&TimeStatistic{},
+		&TokenBasic{},
+		&TokenMap{},
+		&TokenPriceAvg{},
+		&Token{},
+		&TokenStatistic{},
+		&WrapperDetail{},
+		&WrapperTransaction{},
+	}
+}
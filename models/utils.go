@@ -144,17 +144,25 @@ func (bigInt *BigInt) Value() (driver.Value, error) {
 }
 
 func (bigInt *BigInt) Scan(v interface{}) error {
-	value, ok := v.([]byte)
-	if !ok {
+	var str string
+	switch value := v.(type) {
+	case []byte:
+		str = string(value)
+	case string:
+		// postgres returns texts and numerics as strings
+		str = value
+	case int64:
+		// sqlite returns the numeric results as integers
+		str = strconv.FormatInt(value, 10)
+	default:
 		return fmt.Errorf("type error, %v", v)
 	}
-	str := string(value)
 	if str == "null" || str == "nil" || str == "<nil>" || str == "" {
 		return nil
 	}
 	data, ok := new(big.Int).SetString(str, 10)
 	if !ok {
-		return fmt.Errorf("not a valid big integer: %s", value)
+		return fmt.Errorf("not a valid big integer: %s", str)
 	}
 	bigInt.Int = *data
 	return nil
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+import "strings"
+
+// Webhook is a callback of a partner for the lifecycle events of the cross chain transactions.
+// Empty filters match all the transactions, a transaction matches a chain, token, address or contract
+// on either its source or its destination side.
+type Webhook struct {
+	Id       int64  `gorm:"primaryKey;autoIncrement"`
+	Partner  string `gorm:"size:64;not null"`
+	Url      string `gorm:"size:512;not null"`
+	Secret   string `gorm:"size:128;not null"`
+	ChainId  uint64 `gorm:"type:bigint(20);not null"`
+	Token    string `gorm:"size:66;not null"`
+	Address  string `gorm:"size:66;not null"`
+	Contract string `gorm:"size:66;not null"`
+	Events   string `gorm:"size:128;not null"` // comma separated events, empty for all
+	Disabled bool   `gorm:"type:tinyint(1);not null"`
+	Time     int64  `gorm:"type:bigint(20);not null"`
+}
+
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return nil
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// WebhookDelivery is the signed payload of an event to a webhook, which is retried with backoff until it is
+// delivered or given up. It is kept as the delivery log of the webhook.
+type WebhookDelivery struct {
+	Id            int64  `gorm:"primaryKey;autoIncrement"`
+	WebhookId     int64  `gorm:"uniqueIndex:idx_webhook_delivery;type:bigint(20);not null"`
+	Event         string `gorm:"uniqueIndex:idx_webhook_delivery;size:16;not null"`
+	Hash          string `gorm:"uniqueIndex:idx_webhook_delivery;size:66;not null"`
+	Payload       string `gorm:"type:text;not null"`
+	Status        int    `gorm:"index:idx_webhook_delivery_due,priority:1;type:int(8);not null"`
+	Attempts      int    `gorm:"type:int(8);not null"`
+	NextTime      int64  `gorm:"index:idx_webhook_delivery_due,priority:2;type:bigint(20);not null"`
+	LastCode      int    `gorm:"type:int(11);not null"`
+	LastError     string `gorm:"size:256;not null"`
+	Time          int64  `gorm:"type:bigint(20);not null"`
+	DeliveredTime int64  `gorm:"type:bigint(20);not null"`
+}
+
+// WebhookEvent is the payload of a delivery, Transaction is the wrapper, source, poly or destination transaction
+// of the event, or the wrapper transaction of the finished event.
+type WebhookEvent struct {
+	Event       string
+	Hash        string
+	ChainId     uint64
+	Time        int64
+	Transaction interface{}
+}
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"github.com/beego/beego/v2/core/logs"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"poly-bridge/basedef"
 	"poly-bridge/cacheRedis"
 	"poly-bridge/common"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/monitor/healthmonitor/ethereummonitor"
 	"poly-bridge/monitor/healthmonitor/neo3monitor"
 	"poly-bridge/monitor/healthmonitor/neomonitor"
@@ -34,9 +34,8 @@ func Init() {
 		Logger = Logger.LogMode(logger.Info)
 	}
 	dbConfig := conf.GlobalConfig.DBConfig
-	dbConn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", dbConfig.User, dbConfig.Password, dbConfig.URL, dbConfig.Scheme)
 	var err error
-	db, err = gorm.Open(mysql.Open(dbConn), &gorm.Config{Logger: Logger})
+	db, err = dbconn.Open(dbConfig, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -483,32 +482,36 @@ func sendRelayerAccountStatusDingAlarm(relayerStatus *basedef.RelayerAccountStat
 	return err
 }
 
+type MonitorHandleFactory func(monitorConfig *conf.HealthMonitorConfig) MonitorHandle
+
+func newEthereumHealthMonitor(monitorConfig *conf.HealthMonitorConfig) MonitorHandle {
+	return ethereummonitor.NewEthereumHealthMonitor(monitorConfig)
+}
+
+var monitorHandleFactories = map[string]MonitorHandleFactory{
+	basedef.FAMILY_POLY:   func(cfg *conf.HealthMonitorConfig) MonitorHandle { return polymonitor.NewPolyHealthMonitor(cfg) },
+	basedef.FAMILY_EVM:    newEthereumHealthMonitor,
+	basedef.FAMILY_ONTEVM: newEthereumHealthMonitor,
+	basedef.FAMILY_O3:     newEthereumHealthMonitor,
+	basedef.FAMILY_NEO:    func(cfg *conf.HealthMonitorConfig) MonitorHandle { return neomonitor.NewNeoHealthMonitor(cfg) },
+	basedef.FAMILY_ONTOLOGY: func(cfg *conf.HealthMonitorConfig) MonitorHandle {
+		return ontologymonitor.NewOntologyHealthMonitor(cfg)
+	},
+	basedef.FAMILY_NEO3:    func(cfg *conf.HealthMonitorConfig) MonitorHandle { return neo3monitor.NewNeo3HealthMonitor(cfg) },
+	basedef.FAMILY_ZILLIQA: func(cfg *conf.HealthMonitorConfig) MonitorHandle { return zilliqamonitor.NewZilliqaHealthMonitor(cfg) },
+	basedef.FAMILY_RIPPLE:  func(cfg *conf.HealthMonitorConfig) MonitorHandle { return ripplemonitor.NewRippleHealthMonitor(cfg) },
+	//basedef.FAMILY_SWITCHEO: func(cfg *conf.HealthMonitorConfig) MonitorHandle { return switcheomonitor.NewSwitcheoHealthMonitor(cfg) },
+}
+
+// RegisterMonitorHandle sets the health monitor factory of a chain family
+func RegisterMonitorHandle(family string, factory MonitorHandleFactory) {
+	monitorHandleFactories[family] = factory
+}
+
 func NewHealthMonitorHandle(monitorConfig *conf.HealthMonitorConfig) MonitorHandle {
-	switch monitorConfig.ChainId {
-	case basedef.POLY_CROSSCHAIN_ID:
-		return polymonitor.NewPolyHealthMonitor(monitorConfig)
-	case basedef.ETHEREUM_CROSSCHAIN_ID, basedef.O3_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.PLT_CROSSCHAIN_ID,
-		basedef.OK_CROSSCHAIN_ID, basedef.HECO_CROSSCHAIN_ID, basedef.MATIC_CROSSCHAIN_ID, basedef.ARBITRUM_CROSSCHAIN_ID,
-		basedef.XDAI_CROSSCHAIN_ID, basedef.FANTOM_CROSSCHAIN_ID, basedef.AVAX_CROSSCHAIN_ID, basedef.OPTIMISTIC_CROSSCHAIN_ID,
-		basedef.METIS_CROSSCHAIN_ID, basedef.RINKEBY_CROSSCHAIN_ID, basedef.BOBA_CROSSCHAIN_ID, basedef.OASIS_CROSSCHAIN_ID,
-		basedef.HARMONY_CROSSCHAIN_ID, basedef.KCC_CROSSCHAIN_ID, basedef.BYTOM_CROSSCHAIN_ID, basedef.HSC_CROSSCHAIN_ID,
-		basedef.KAVA_CROSSCHAIN_ID, basedef.CUBE_CROSSCHAIN_ID, basedef.ZKSYNC_CROSSCHAIN_ID, basedef.CELO_CROSSCHAIN_ID,
-		basedef.CLOVER_CROSSCHAIN_ID, basedef.CONFLUX_CROSSCHAIN_ID, basedef.ASTAR_CROSSCHAIN_ID, basedef.BRISE_CROSSCHAIN_ID,
-		basedef.DEXIT_CROSSCHAIN_ID, basedef.CLOUDTX_CROSSCHAIN_ID, basedef.XINFIN_CROSSCHAIN_ID, basedef.ONTEVM_CROSSCHAIN_ID:
-		return ethereummonitor.NewEthereumHealthMonitor(monitorConfig)
-	case basedef.NEO_CROSSCHAIN_ID:
-		return neomonitor.NewNeoHealthMonitor(monitorConfig)
-	case basedef.ONT_CROSSCHAIN_ID:
-		return ontologymonitor.NewOntologyHealthMonitor(monitorConfig)
-	//case basedef.SWITCHEO_CROSSCHAIN_ID:
-	//	return switcheomonitor.NewSwitcheoHealthMonitor(monitorConfig)
-	case basedef.NEO3_CROSSCHAIN_ID:
-		return neo3monitor.NewNeo3HealthMonitor(monitorConfig)
-	case basedef.ZILLIQA_CROSSCHAIN_ID:
-		return zilliqamonitor.NewZilliqaHealthMonitor(monitorConfig)
-	case basedef.RIPPLE_CROSSCHAIN_ID:
-		return ripplemonitor.NewRippleHealthMonitor(monitorConfig)
-	default:
+	factory, ok := monitorHandleFactories[basedef.GetChainFamily(monitorConfig.ChainId)]
+	if !ok {
 		return nil
 	}
+	return factory(monitorConfig)
 }
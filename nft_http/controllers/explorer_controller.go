@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"errors"
-	"fmt"
 	"gorm.io/gorm"
 	"poly-bridge/basedef"
 	"poly-bridge/models"
@@ -85,7 +84,7 @@ func (c *ExplorerController) TransactionsOfAddress() {
 			Find(&relations)
 		db.Model(&models.SrcTransfer{}).
 			Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-			Where("src_transfers.standard = ? and (`from` in ? or src_transfers.dst_user in ?)", models.TokenTypeErc721, req.Addresses, req.Addresses).
+			Where("src_transfers.standard = ? and (src_transfers.from in ? or src_transfers.dst_user in ?)", models.TokenTypeErc721, req.Addresses, req.Addresses).
 			Count(&transactionNum)
 	} else {
 		db.Raw("select wp.*, tr.amount as token_id, tr.asset as src_asset "+
@@ -98,7 +97,7 @@ func (c *ExplorerController) TransactionsOfAddress() {
 			Find(&relations)
 		db.Model(&models.SrcTransfer{}).
 			Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-			Where("src_transfers.standard = ? and (`from` in ? or src_transfers.dst_user in ?) and (wrapper_transactions.src_chain_id = ? or wrapper_transactions.dst_chain_id = ?)",
+			Where("src_transfers.standard = ? and (src_transfers.from in ? or src_transfers.dst_user in ?) and (wrapper_transactions.src_chain_id = ? or wrapper_transactions.dst_chain_id = ?)",
 				models.TokenTypeErc721, req.Addresses, req.Addresses, req.ChainId, req.ChainId).
 			Count(&transactionNum)
 	}
@@ -132,9 +131,7 @@ func (c *ExplorerController) TransactionDetail() {
 		left join dst_transactions d on d.poly_hash=p.hash where d.hash=?`,
 		req.Hash, req.Hash, req.Hash).First(tx).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) || tx.Hash == "" {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("relations does not exist"))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		customOutput(&c.Controller, ErrCodeNotExist, "relations does not exist")
 		return
 	}
 	res := db.Table("src_transactions").
@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"math/big"
 	"poly-bridge/basedef"
+	"poly-bridge/cacheRedis"
 	"poly-bridge/models"
+	"poly-bridge/utils/fee"
 
+	"github.com/beego/beego/v2/core/logs"
 	"github.com/beego/beego/v2/server/web"
 )
 
@@ -40,14 +43,22 @@ func (c *FeeController) GetFee() {
 		Preload("TokenBasic").
 		First(token)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have token: %s", req.SrcChainId, req.Hash))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		customOutput(&c.Controller, basedef.ERROR_TOKEN_NOT_FOUND, fmt.Sprintf("chain: %d does not have token: %s", req.SrcChainId, req.Hash))
 		return
 	}
 	if token.TokenBasic.Price == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("token: %v price is 0", token.TokenBasic.Name))
-		c.Ctx.ResponseWriter.WriteHeader(400)
+		customOutput(&c.Controller, basedef.ERROR_PRICE_ZERO, fmt.Sprintf("token: %v price is 0", token.TokenBasic.Name))
+		return
+	}
+	// the fee is not quoted when the circuits can not be checked
+	circuit, err := cacheRedis.Redis.CheckCircuit(req.SrcChainId, req.DstChainId, "")
+	if err != nil {
+		customOutput(&c.Controller, basedef.ERROR_SERVICE_ERROR, fmt.Sprintf("check circuit breaks err: %v", err))
+		return
+	}
+	if circuit != nil {
+		c.Data["json"] = models.MakeCircuitBreakPausedRsp(circuit)
+		c.Ctx.ResponseWriter.WriteHeader(basedef.ErrorStatus(basedef.ERROR_CIRCUIT_PAUSED))
 		c.ServeJSON()
 		return
 	}
@@ -55,18 +66,19 @@ func (c *FeeController) GetFee() {
 	chainFee := new(models.ChainFee)
 	res = db.Where("chain_id = ?", req.DstChainId).Preload("TokenBasic").First(chainFee)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", req.DstChainId))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		customOutput(&c.Controller, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("chain: %d does not have fee", req.DstChainId))
 		return
 	}
+	gasLimits, err := fee.LoadGasLimits(db)
+	if err != nil {
+		logs.Error("load gas limits err: %v", err)
+	}
+	chainFee = gasLimits.ChainFee(chainFee, models.ProxyTypeNft)
 	chainFeeToken := new(models.Token)
 	res = db.Where("chain_id = ? and token_basic_name = ?", chainFee.ChainId, chainFee.TokenBasicName).
 		First(chainFeeToken)
 	if res.RowsAffected == 0 {
-		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("chain: %d does not have fee", req.DstChainId))
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		customOutput(&c.Controller, basedef.ERROR_FEE_UNAVAILABLE, fmt.Sprintf("chain: %d does not have fee", req.DstChainId))
 		return
 	}
 	fzero := new(big.Float).SetUint64(0)
@@ -78,6 +90,6 @@ func (c *FeeController) GetFee() {
 	tokenFee := new(big.Float).Mul(usdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
 	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
 	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
-	c.Data["json"] = models.MakeGetFeeRsp(req.SrcChainId, req.Hash, req.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision, "", new(big.Int).SetUint64(0), 0, false, fzero, feeTokenPricison)
+	c.Data["json"] = models.MakeGetFeeRsp(req.SrcChainId, req.Hash, req.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision, "", new(big.Int).SetUint64(0), 0, false, fzero, feeTokenPricison, nil)
 	c.ServeJSON()
 }
@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"fmt"
+	"poly-bridge/basedef"
 	"poly-bridge/models"
 	"poly-bridge/nft_http/meta"
 	"poly-bridge/utils/net"
@@ -40,9 +41,7 @@ type InfoController struct {
 func (c *InfoController) Get() {
 	url, err := captureUrl()
 	if err != nil {
-		c.Data["json"] = models.MakeErrorRsp(err.Error())
-		c.Ctx.ResponseWriter.WriteHeader(400)
-		c.ServeJSON()
+		customOutput(&c.Controller, basedef.ERROR_SERVICE_ERROR, err.Error())
 		return
 	}
 	explorer := &PolyBridgeInfoResp{
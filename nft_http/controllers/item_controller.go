@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"math/big"
+	"poly-bridge/basedef"
 	"poly-bridge/chainsdk"
 	"poly-bridge/models"
 	mcm "poly-bridge/nft_http/meta/common"
@@ -57,12 +58,12 @@ func (c *ItemController) fetchSingleNFTItem(req *ItemsOfAddressReq) {
 	}
 	sdk, wrapper, _, err := selectNodeAndWrapper(req.ChainId)
 	if err != nil {
-		customInput(&c.Controller, ErrCodeRequest, err.Error())
+		customInput(&c.Controller, basedef.ERROR_CHAIN_NOT_FOUND, err.Error())
 		return
 	}
 	token := selectNFTAsset(req.Asset)
 	if token == nil {
-		customInput(&c.Controller, ErrCodeRequest, "NFT Asset not exist")
+		customInput(&c.Controller, ErrCodeNotExist, "NFT Asset not exist")
 		return
 	}
 
@@ -86,12 +87,12 @@ func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq) {
 	//}
 	sdk, wrapper, _, err := selectNodeAndWrapper(req.ChainId)
 	if err != nil {
-		customInput(&c.Controller, ErrCodeRequest, err.Error())
+		customInput(&c.Controller, basedef.ERROR_CHAIN_NOT_FOUND, err.Error())
 		return
 	}
 	token := selectNFTAsset(req.Asset)
 	if token == nil {
-		customInput(&c.Controller, ErrCodeRequest, "NFT Asset not exist")
+		customInput(&c.Controller, ErrCodeNotExist, "NFT Asset not exist")
 		return
 	}
 
@@ -100,7 +101,7 @@ func (c *ItemController) batchFetchNFTItems(req *ItemsOfAddressReq) {
 	owner := common.HexToAddress(req.Address)
 	bigTotalCnt, err := sdk.NFTBalance(asset, owner)
 	if err != nil {
-		customInput(&c.Controller, ErrCodeRequest, err.Error())
+		customInput(&c.Controller, ErrCodeNodeInvalid, err.Error())
 		return
 	}
 	totalCnt := int(bigTotalCnt.Uint64())
@@ -237,12 +237,12 @@ func (s *WrapperTransactionRsp) instance(transaction *models.WrapperTransaction)
 }
 
 type TransactionBriefsReq struct {
-	PageSize int
+	PageSize int `valid:"Max(10)"`
 	PageNo   int
 }
 
 type TransactionBriefsOfAddressReq struct {
-	PageSize  int
+	PageSize  int `valid:"Max(10)"`
 	PageNo    int
 	Addresses []string
 	ChainId   uint64
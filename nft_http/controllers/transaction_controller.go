@@ -39,13 +39,13 @@ func (c *TransactionController) TransactionsOfAddress() {
 	wrapTxs := make([]*models.WrapperTransaction, 0)
 	if req.State == -1 {
 		db.Model(&models.WrapperTransaction{}).
-			Where("standard = ? and (user in ? or dst_user in ? )", models.TokenTypeErc721, req.Addresses, req.Addresses).
+			Where("standard = ? and (wrapper_transactions.user in ? or dst_user in ? )", models.TokenTypeErc721, req.Addresses, req.Addresses).
 			Limit(req.PageSize).Offset(req.PageSize * req.PageNo).
 			Order("time desc").
 			Find(&wrapTxs)
 	} else {
 		db.Model(&models.WrapperTransaction{}).
-			Where("standard = ? and status = ? and (user in ? or dst_user in ?)", models.TokenTypeErc721, req.State, req.Addresses, req.Addresses).
+			Where("standard = ? and status = ? and (wrapper_transactions.user in ? or dst_user in ?)", models.TokenTypeErc721, req.State, req.Addresses, req.Addresses).
 			Limit(req.PageSize).Offset(req.PageSize * req.PageNo).
 			Order("time desc").
 			Find(&wrapTxs)
@@ -57,7 +57,7 @@ func (c *TransactionController) TransactionsOfAddress() {
 	var transactionNum int64
 	db.Model(&models.SrcTransfer{}).
 		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("src_transfers.standard = ? and (`from` in ? or src_transfers.dst_user in ?)", models.TokenTypeErc721, req.Addresses, req.Addresses).
+		Where("src_transfers.standard = ? and (src_transfers.from in ? or src_transfers.dst_user in ?)", models.TokenTypeErc721, req.Addresses, req.Addresses).
 		Count(&transactionNum)
 
 	// get chains
@@ -22,10 +22,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"poly-bridge/basedef"
 	"poly-bridge/chainsdk"
 	"poly-bridge/conf"
+	"poly-bridge/dbconn"
 	"poly-bridge/models"
 	"poly-bridge/nft_http/meta"
+	"poly-bridge/utils/apierror"
 	"regexp"
 	"strings"
 	"time"
@@ -34,7 +37,6 @@ import (
 	"github.com/beego/beego/v2/server/web"
 	"github.com/ethereum/go-ethereum/common"
 	lru "github.com/hashicorp/golang-lru"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -54,16 +56,11 @@ var (
 )
 
 func NewDB(cfg *conf.DBConfig) *gorm.DB {
-	user := cfg.User
-	password := cfg.Password
-	url := cfg.URL
-	scheme := cfg.Scheme
 	Logger := logger.Default
 	if cfg.Debug {
 		Logger = Logger.LogMode(logger.Info)
 	}
-	format := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8", user, password, url, scheme)
-	db, err := gorm.Open(mysql.Open(format), &gorm.Config{Logger: Logger})
+	db, err := dbconn.Open(cfg, Logger)
 	if err != nil {
 		panic(err)
 	}
@@ -226,12 +223,12 @@ func selectAssetsByChainId(chainId uint64) []*models.Token {
 }
 
 const (
-	ErrCodeRequest     int = 400
-	ErrCodeNotExist    int = 404
-	ErrCodeNodeInvalid int = 500
+	ErrCodeRequest     = basedef.ERROR_INVALID_PARAMETER
+	ErrCodeNotExist    = basedef.ERROR_NOT_FOUND
+	ErrCodeNodeInvalid = basedef.ERROR_NODE_ERROR
 )
 
-var errMap = map[int]string{
+var errMap = map[string]string{
 	ErrCodeRequest:     "request parameter is invalid!",
 	ErrCodeNotExist:    "not found",
 	ErrCodeNodeInvalid: "blockchain node exception",
@@ -247,27 +244,20 @@ func input(c *web.Controller, req interface{}) bool {
 	}
 }
 
-func customInput(c *web.Controller, code int, msg string) {
-	c.Data["json"] = models.MakeErrorRsp(msg)
-	c.Ctx.ResponseWriter.WriteHeader(code)
-	c.ServeJSON()
+func customInput(c *web.Controller, code string, msg string) {
+	customOutput(c, code, msg)
 }
 
 func notExist(c *web.Controller) {
 	code := ErrCodeNotExist
-	c.Data["json"] = models.MakeErrorRsp(errMap[code])
-	c.Ctx.ResponseWriter.WriteHeader(code)
-	c.ServeJSON()
+	customOutput(c, code, errMap[code])
 }
 
 func checkPageSize(c *web.Controller, size int) bool {
 	if size <= 10 {
 		return true
 	}
-	code := ErrCodeRequest
-	c.Data["json"] = models.MakeErrorRsp("page size too big, should be smaller than 10")
-	c.Ctx.ResponseWriter.WriteHeader(code)
-	c.ServeJSON()
+	customOutput(c, ErrCodeRequest, "page size too big, should be smaller than 10")
 	return false
 }
 
@@ -293,9 +283,7 @@ func checkNumString(numStr string) (*big.Int, error) {
 
 func nodeInvalid(c *web.Controller) {
 	code := ErrCodeNodeInvalid
-	c.Data["json"] = models.MakeErrorRsp(errMap[code])
-	c.Ctx.ResponseWriter.WriteHeader(code)
-	c.ServeJSON()
+	customOutput(c, code, errMap[code])
 }
 
 func output(c *web.Controller, data interface{}) {
@@ -303,10 +291,9 @@ func output(c *web.Controller, data interface{}) {
 	c.ServeJSON()
 }
 
-func customOutput(c *web.Controller, code int, msg string) {
-	c.Data["json"] = models.MakeErrorRsp(msg)
-	c.Ctx.ResponseWriter.WriteHeader(code)
-	c.ServeJSON()
+// customOutput responds the error code with its http status, the message describes the error
+func customOutput(c *web.Controller, code string, msg string) {
+	apierror.Output(c, code, msg)
 }
 
 func getPageNo(totalNo, pageSize int) int {
@@ -19,7 +19,9 @@ package nft_http
 
 import (
 	"poly-bridge/conf"
+	"poly-bridge/models"
 	"poly-bridge/nft_http/controllers"
+	"poly-bridge/utils/apispec"
 
 	"github.com/beego/beego/v2/server/web"
 )
@@ -53,3 +55,19 @@ func Init(config *conf.Config) web.LinkNamespace {
 	)
 	return ns
 }
+
+// ApiRoutes documents the routes of Init in the OpenAPI document, their request bodies are validated
+// with the models before they reach the controllers
+var ApiRoutes = []*apispec.Route{
+	{Method: "get", Path: "/", Summary: "nft wrapper contracts of the chains", Response: controllers.PolyBridgeInfoResp{}},
+	{Method: "post", Path: "/assetshow/", Summary: "nft assets of the chain with their first items", Request: controllers.HomeReq{}, Response: controllers.HomeRsp{}},
+	{Method: "post", Path: "/asset/", Summary: "nft asset of the hash on the chain", Request: controllers.AssetReq{}, Response: controllers.AssetMap{}},
+	{Method: "post", Path: "/assets/", Summary: "nft assets of the chain", Request: controllers.AssetsReq{}, Response: controllers.AssetsRsp{}},
+	{Method: "post", Path: "/items/", Summary: "nft items of the address", Request: controllers.ItemsOfAddressReq{}, Response: controllers.ItemsOfAddressRsp{}},
+	{Method: "post", Path: "/getfee/", Summary: "fee of the nft transfer to the destination chain", Request: models.GetFeeReq{}, Response: models.GetFeeRsp{}},
+	{Method: "post", Path: "/exp_transactions/", Summary: "nft transactions", Request: controllers.TransactionBriefsReq{}, Response: controllers.TransactionBriefsRsp{}},
+	{Method: "post", Path: "/exp_transactionsofaddress/", Summary: "nft transactions of the addresses", Request: controllers.TransactionBriefsOfAddressReq{}, Response: controllers.TransactionBriefsRsp{}},
+	{Method: "post", Path: "/exp_transactionofhash/", Summary: "nft transaction of the hash", Request: controllers.TransactionDetailReq{}, Response: controllers.TransactionDetailRsp{}},
+	{Method: "post", Path: "/transactionsofaddress/", Summary: "nft transactions of the addresses", Request: models.TransactionsOfAddressReq{}, Response: controllers.TransactionsOfAddressRsp{}},
+	{Method: "post", Path: "/transactionofhash/", Summary: "nft transaction of the hash", Request: models.TransactionOfHashReq{}, Response: controllers.TransactionRsp{}},
+}
@@ -0,0 +1,15 @@
+// Package apierror responds the errors of the catalogue in basedef from the controllers of all the servers
+package apierror
+
+import (
+	"github.com/beego/beego/v2/server/web"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+)
+
+// Output responds the error code with its http status, the message describes the error
+func Output(c *web.Controller, code string, message string) {
+	c.Data["json"] = models.MakeErrorCodeRsp(code, message)
+	c.Ctx.ResponseWriter.WriteHeader(basedef.ErrorStatus(code))
+	c.ServeJSON()
+}
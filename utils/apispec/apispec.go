@@ -0,0 +1,275 @@
+package apispec
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"poly-bridge/models"
+)
+
+const openApiVersion = "3.0.3"
+
+// Route is an operation of the api, Request and Response are values of the models of its body and its response,
+// nil for none
+type Route struct {
+	Method   string // get, post, put or delete
+	Path     string
+	Summary  string
+	Query    []string // query params of the operation
+	Request  interface{}
+	Response interface{}
+}
+
+// Schema is an OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type parameter struct {
+	Name   string  `json:"name"`
+	In     string  `json:"in"`
+	Schema *Schema `json:"schema"`
+}
+
+type operation struct {
+	Summary     string               `json:"summary,omitempty"`
+	Parameters  []*parameter         `json:"parameters,omitempty"`
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Document is an OpenAPI 3 document generated from the models of the routes
+type Document struct {
+	lock       sync.RWMutex
+	OpenApi    string                           `json:"openapi"`
+	Info       *info                            `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components *components                      `json:"components"`
+	names      map[reflect.Type]string
+	routes     map[string]*Route
+}
+
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenApi:    openApiVersion,
+		Info:       &info{Title: title, Version: version},
+		Paths:      make(map[string]map[string]*operation),
+		Components: &components{Schemas: make(map[string]*Schema)},
+		names:      make(map[reflect.Type]string),
+		routes:     make(map[string]*Route),
+	}
+}
+
+func routeKey(method, path string) string {
+	return strings.ToLower(method) + " " + path
+}
+
+// Add adds the routes under the path prefix
+func (d *Document) Add(prefix string, routes []*Route) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, route := range routes {
+		method := strings.ToLower(route.Method)
+		path := prefix + route.Path
+		op := &operation{
+			Summary:   route.Summary,
+			Responses: make(map[string]*response),
+		}
+		for _, name := range route.Query {
+			op.Parameters = append(op.Parameters, &parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+		if route.Request != nil {
+			op.RequestBody = &requestBody{
+				Required: true,
+				Content:  map[string]*mediaType{"application/json": {Schema: d.schema(reflect.TypeOf(route.Request))}},
+			}
+		}
+		ok := &response{Description: "OK"}
+		if route.Response != nil {
+			ok.Content = map[string]*mediaType{"application/json": {Schema: d.schema(reflect.TypeOf(route.Response))}}
+		}
+		op.Responses["200"] = ok
+		// the errors are responded with the statuses of their codes
+		op.Responses["default"] = &response{Description: "Error", Content: map[string]*mediaType{"application/json": {Schema: d.schema(reflect.TypeOf(models.ErrorRsp{}))}}}
+		if d.Paths[path] == nil {
+			d.Paths[path] = make(map[string]*operation)
+		}
+		d.Paths[path][method] = op
+		d.routes[routeKey(method, path)] = route
+	}
+}
+
+// Route returns the route of the method and the path, the trailing slash of the path is optional
+func (d *Document) Route(method, path string) *Route {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if route, ok := d.routes[routeKey(method, path)]; ok || strings.HasSuffix(path, "/") {
+		return route
+	}
+	return d.routes[routeKey(method, path+"/")]
+}
+
+func (d *Document) MarshalJSON() ([]byte, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	type document Document
+	return json.Marshal((*document)(d))
+}
+
+var (
+	bigIntType        = reflect.TypeOf(big.Int{})
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func implements(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+// schema returns the schema of the type, the named structs are referenced from the components
+func (d *Document) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == bigIntType:
+		return &Schema{Type: "integer"}
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.NumField() == 1 && t.Field(0).Anonymous && t.Field(0).Type == bigIntType:
+		// models.BigInt
+		return &Schema{Type: "integer"}
+	case implements(t, jsonMarshalerType):
+		return &Schema{}
+	case implements(t, textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := float64(0)
+		return &Schema{Type: "integer", Format: "int64", Minimum: &min}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.object(t)
+		}
+		name, ok := d.names[t]
+		if !ok {
+			name = t.Name()
+			if _, taken := d.Components.Schemas[name]; taken {
+				name = t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:] + "." + name
+			}
+			d.names[t] = name
+			d.Components.Schemas[name] = &Schema{}
+			*d.Components.Schemas[name] = *d.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+// object is the schema of the fields of a struct, the embedded structs are flattened as encoding/json does
+func (d *Document) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t) {
+		s.Properties[field.name] = d.schema(field.Type)
+		if field.required {
+			s.Required = append(s.Required, field.name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+type structField struct {
+	reflect.StructField
+	name     string
+	required bool
+}
+
+// fields returns the json fields of a struct
+func fields(t reflect.Type) []*structField {
+	res := make([]*structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != bigIntType {
+			res = append(res, fields(ft)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		res = append(res, &structField{
+			StructField: field,
+			name:        name,
+			required:    strings.Contains(field.Tag.Get("valid"), "Required"),
+		})
+	}
+	return res
+}
@@ -0,0 +1,108 @@
+package apispec
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beego/beego/v2/server/web/context"
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+)
+
+type pageReq struct {
+	Hash     string `valid:"Required"`
+	PageSize int    `valid:"Min(1)"`
+	Cursor   string `json:"cursor,omitempty"`
+	Skipped  string `json:"-"`
+}
+
+type pageRsp struct {
+	models.PageCursor
+	Amount *models.BigInt
+	Items  []*models.ErrorRsp
+	Counts map[uint64]bool
+}
+
+func TestDocument(t *testing.T) {
+	d := NewDocument("test", "1.0.0")
+	d.Add("/v1/test", []*Route{
+		{Method: "post", Path: "/page/", Request: pageReq{}, Response: pageRsp{}},
+		{Method: "get", Path: "/stream/", Query: []string{"hash"}},
+	})
+	data, err := json.Marshal(d)
+	assert.NoError(t, err)
+	doc := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, openApiVersion, doc["openapi"])
+
+	paths := doc["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/v1/test/page/")
+	assert.Contains(t, paths, "/v1/test/stream/")
+	schemas := d.Components.Schemas
+	req := schemas["pageReq"]
+	if assert.NotNil(t, req) {
+		assert.Equal(t, []string{"Hash"}, req.Required)
+		assert.Contains(t, req.Properties, "cursor")
+		assert.NotContains(t, req.Properties, "Skipped")
+		assert.Equal(t, "int32", req.Properties["PageSize"].Format)
+	}
+	rsp := schemas["pageRsp"]
+	if assert.NotNil(t, rsp) {
+		// the embedded cursor is flattened
+		assert.Contains(t, rsp.Properties, "Time")
+		assert.Equal(t, "integer", rsp.Properties["Amount"].Type)
+		assert.Equal(t, "#/components/schemas/ErrorRsp", rsp.Properties["Items"].Items.Ref)
+		assert.Equal(t, "boolean", rsp.Properties["Counts"].AdditionalProperties.Type)
+	}
+	assert.Contains(t, schemas, "FieldError")
+
+	assert.NotNil(t, d.Route("POST", "/v1/test/page/"))
+	assert.NotNil(t, d.Route("post", "/v1/test/page"))
+	assert.Nil(t, d.Route("get", "/v1/test/page/"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, Validate([]byte(`{"Hash":"01","PageSize":10}`), new(pageReq)))
+	assert.Len(t, Validate(nil, new(pageReq)), 1)
+	assert.Len(t, Validate([]byte(`{"Hash":"01","PageSize":10} {}`), new(pageReq)), 1)
+
+	errs := Validate([]byte(`{"Hash":"01","PageSize":"10"}`), new(pageReq))
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "PageSize", errs[0].Field)
+		assert.Equal(t, "should be integer instead of string", errs[0].Message)
+	}
+	errs = Validate([]byte(`{"PageSize":0}`), new(pageReq))
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "Hash", errs[0].Field)
+		assert.Equal(t, "PageSize", errs[1].Field)
+	}
+	// the requests which are not structs are only decoded
+	assert.Nil(t, Validate([]byte(`{"a":{"ChainId":2}}`), new(map[string]*models.CheckFeeRequest)))
+}
+
+func TestValidator(t *testing.T) {
+	Register("/v1/test", []*Route{{Method: "post", Path: "/page/", Request: pageReq{}}})
+	request := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ctx := context.NewContext()
+		ctx.Reset(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		ctx.Input.RequestBody = []byte(body)
+		Validator(ctx)
+		return w
+	}
+	assert.Equal(t, http.StatusOK, request("/v1/test/page/", `{"Hash":"01","PageSize":1}`).Code)
+	assert.Equal(t, http.StatusOK, request("/v1/test/other/", `{`).Code)
+
+	w := request("/v1/test/page", `{"PageSize":1}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	rsp := new(models.ErrorRsp)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), rsp))
+	assert.Equal(t, basedef.ERROR_INVALID_PARAMETER, rsp.Code)
+	if assert.Len(t, rsp.Fields, 1) {
+		assert.Equal(t, "Hash", rsp.Fields[0].Field)
+	}
+}
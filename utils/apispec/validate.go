@@ -0,0 +1,119 @@
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/beego/beego/v2/core/validation"
+	"github.com/beego/beego/v2/server/web"
+	"github.com/beego/beego/v2/server/web/context"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+)
+
+// Spec is the document of the routes of the http server, it is served at /v1/openapi.json
+var Spec = NewDocument("poly bridge", "1.0.0")
+
+// Register adds the routes under the path prefix to the document, their request bodies are validated by the filter
+func Register(prefix string, routes []*Route) {
+	Spec.Add(prefix, routes)
+}
+
+// Validate decodes the body into req and checks the valid tags of its fields, it returns the errors of the fields
+func Validate(body []byte, req interface{}) []*models.FieldError {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return []*models.FieldError{{Message: "request body is required"}}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return []*models.FieldError{{Field: typeErr.Field, Message: fmt.Sprintf("should be %s instead of %s", jsonType(typeErr.Type), typeErr.Value)}}
+		}
+		return []*models.FieldError{{Message: fmt.Sprintf("invalid json: %v", err)}}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return []*models.FieldError{{Message: "invalid json: data after the request body"}}
+	}
+	t := reflect.TypeOf(req)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	valid := validation.Validation{}
+	if ok, err := valid.Valid(req); err != nil {
+		return []*models.FieldError{{Message: err.Error()}}
+	} else if ok {
+		return nil
+	}
+	names := make(map[string]string)
+	for _, field := range fields(t) {
+		names[field.Name] = field.name
+	}
+	fieldErrors := make([]*models.FieldError, 0, len(valid.Errors))
+	for _, err := range valid.Errors {
+		name, ok := names[err.Field]
+		if !ok {
+			name = err.Field
+		}
+		fieldErrors = append(fieldErrors, &models.FieldError{Field: name, Message: err.Message})
+	}
+	return fieldErrors
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// Validator is the filter rejecting the requests whose bodies do not match the models of their routes with 400
+func Validator(ctx *context.Context) {
+	route := Spec.Route(ctx.Input.Method(), ctx.Request.URL.Path)
+	if route == nil || route.Request == nil {
+		return
+	}
+	req := reflect.New(reflect.TypeOf(route.Request)).Interface()
+	if fieldErrors := Validate(ctx.Input.RequestBody, req); len(fieldErrors) > 0 {
+		messages := make([]string, 0, len(fieldErrors))
+		for _, fieldError := range fieldErrors {
+			messages = append(messages, fieldError.String())
+		}
+		rsp := models.MakeErrorCodeRsp(basedef.ERROR_INVALID_PARAMETER, "request parameter is invalid: "+strings.Join(messages, "; "))
+		rsp.Fields = fieldErrors
+		ctx.Output.SetStatus(http.StatusBadRequest)
+		ctx.Output.JSON(rsp, false, false)
+	}
+}
+
+type Controller struct {
+	web.Controller
+}
+
+// Get serves the OpenAPI document
+func (c *Controller) Get() {
+	c.Data["json"] = Spec
+	c.ServeJSON()
+}
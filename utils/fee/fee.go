@@ -7,28 +7,91 @@ import (
 	"poly-bridge/basedef"
 	"poly-bridge/conf"
 	"poly-bridge/models"
+	"time"
 )
 
-func GetL1Fee(ethChainFee *models.ChainFee, chainId uint64) (l1MinFee, l1ProxyFee, l1FeeAmount *big.Float, err error) {
-	var targetFeeListenConfig, ethFeeListenConfig *conf.FeeListenConfig
-	for _, fl := range conf.GlobalConfig.FeeListenConfig {
-		if fl.ChainId == chainId {
-			targetFeeListenConfig = fl
-			break
-		}
-		continue
+// the reading of the L1 fee oracle is used within 10 minutes by default
+const defaultL1FeeMaxAge = 600
+
+// HasL1Fee reports whether the unlocks on the chain pay an L1 data fee, which is read from the oracle of the chain
+// or scaled from the fee of ethereum
+func HasL1Fee(chainId uint64) bool {
+	if conf.GlobalConfig == nil {
+		return false
 	}
-	for _, fl := range conf.GlobalConfig.FeeListenConfig {
-		if fl.ChainId == basedef.ETHEREUM_CROSSCHAIN_ID {
-			ethFeeListenConfig = fl
-			break
+	cfg := conf.GlobalConfig.GetFeeListenConfig(chainId)
+	return cfg != nil && (cfg.L1FeeOracle != "" || cfg.EthL1GasLimit > 0)
+}
+
+// GetL1Fee returns the L1 data fee of an unlock on the layer 2 chain of chainFee, in usd for the min fee and the proxy
+// fee and in the fee token of the chain for the fee amount. The fee read from the oracle of the chain is used,
+// otherwise the fee of ethereum is scaled by EthL1GasLimit.
+func GetL1Fee(chainFee, ethChainFee *models.ChainFee) (l1MinFee, l1ProxyFee, l1FeeAmount *big.Float, err error) {
+	targetFeeListenConfig := conf.GlobalConfig.GetFeeListenConfig(chainFee.ChainId)
+	if targetFeeListenConfig == nil {
+		err := fmt.Errorf("chain listen config is missing")
+		logs.Error("get L1 fee of chain %d error: %v", chainFee.ChainId, err)
+		return nil, nil, nil, err
+	}
+	if hasOracleL1Fee(targetFeeListenConfig, chainFee, time.Now().Unix()) {
+		l1MinFee, l1ProxyFee, l1FeeAmount = oracleL1Fee(targetFeeListenConfig, chainFee)
+	} else {
+		l1MinFee, l1ProxyFee, l1FeeAmount, err = scaledL1Fee(targetFeeListenConfig, ethChainFee)
+		if err != nil {
+			return
 		}
-		continue
 	}
+	logs.Info("chain:%d l1MinFee=%s, l1ProxyFee=%s, l1FeeAmount=%s", chainFee.ChainId, l1MinFee.String(), l1ProxyFee.String(), l1FeeAmount.String())
+	return
+}
 
-	if targetFeeListenConfig == nil || ethFeeListenConfig == nil {
+// hasOracleL1Fee reports whether the L1 fee of the chain fee is read from the oracle of the chain within its max age
+func hasOracleL1Fee(cfg *conf.FeeListenConfig, chainFee *models.ChainFee, now int64) bool {
+	if cfg.L1FeeOracle == "" || chainFee.L1Fee == nil || chainFee.L1Fee.Sign() <= 0 {
+		return false
+	}
+	maxAge := cfg.L1FeeMaxAge
+	if maxAge <= 0 {
+		maxAge = defaultL1FeeMaxAge
+	}
+	if now-chainFee.L1FeeTime > maxAge {
+		logs.Warn("L1 fee of chain %d read at %d is stale, it is scaled from ethereum", chainFee.ChainId, chainFee.L1FeeTime)
+		return false
+	}
+	return true
+}
+
+// oracleL1Fee takes the min fee and the proxy fee of the L1 fee read from the oracle by the percents of the chain
+func oracleL1Fee(cfg *conf.FeeListenConfig, chainFee *models.ChainFee) (l1MinFee, l1ProxyFee, l1FeeAmount *big.Float) {
+	l1Fee := new(big.Float).SetInt(&chainFee.L1Fee.Int)
+	l1FeeAmount = new(big.Float).Quo(l1Fee, new(big.Float).SetInt64(basedef.FEE_PRECISION))
+	l1FeeAmount = l1FeeAmount.Quo(l1FeeAmount, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
+	l1UsdtFee := new(big.Float).Mul(l1FeeAmount, new(big.Float).SetInt64(chainFee.TokenBasic.Price))
+	l1UsdtFee = l1UsdtFee.Quo(l1UsdtFee, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+	l1MinFee = new(big.Float).Mul(l1UsdtFee, new(big.Float).SetInt64(cfg.MinFee))
+	l1MinFee = l1MinFee.Quo(l1MinFee, new(big.Float).SetInt64(100))
+	l1ProxyFee = new(big.Float).Mul(l1UsdtFee, new(big.Float).SetInt64(cfg.ProxyFee))
+	l1ProxyFee = l1ProxyFee.Quo(l1ProxyFee, new(big.Float).SetInt64(100))
+	return
+}
+
+// scaledL1Fee scales the fee of ethereum by the gas of the L1 data of an unlock on the chain
+func scaledL1Fee(targetFeeListenConfig *conf.FeeListenConfig, ethChainFee *models.ChainFee) (l1MinFee, l1ProxyFee, l1FeeAmount *big.Float, err error) {
+	ethFeeListenConfig := conf.GlobalConfig.GetFeeListenConfig(basedef.ETHEREUM_CROSSCHAIN_ID)
+	if ethFeeListenConfig == nil {
 		err := fmt.Errorf("chain listen config is missing")
-		logs.Error("getOptimisticL1FeeMin error: %v", err)
+		logs.Error("get L1 fee of chain %d error: %v", targetFeeListenConfig.ChainId, err)
+		return nil, nil, nil, err
+	}
+	if ethChainFee == nil {
+		err := fmt.Errorf("chain: %d does not have fee", basedef.ETHEREUM_CROSSCHAIN_ID)
+		logs.Error("get L1 fee of chain %d error: %v", targetFeeListenConfig.ChainId, err)
+		return nil, nil, nil, err
+	}
+	// without the gas of the L1 data the fee would be scaled to zero, so the fee is not paid rather than free of L1 fee
+	if targetFeeListenConfig.EthL1GasLimit <= 0 || ethFeeListenConfig.GasLimit <= 0 {
+		err := fmt.Errorf("EthL1GasLimit of chain %d or GasLimit of ethereum is not configured", targetFeeListenConfig.ChainId)
+		logs.Error("get L1 fee of chain %d error: %v", targetFeeListenConfig.ChainId, err)
 		return nil, nil, nil, err
 	}
 
@@ -46,7 +109,6 @@ func GetL1Fee(ethChainFee *models.ChainFee, chainId uint64) (l1MinFee, l1ProxyFe
 	l1FeeAmount = new(big.Float).Mul(new(big.Float).SetInt(&ethChainFee.MaxFee.Int), gasLimitScale)
 	l1FeeAmount = l1FeeAmount.Quo(l1FeeAmount, new(big.Float).SetInt64(basedef.FEE_PRECISION))
 	l1FeeAmount = l1FeeAmount.Quo(l1FeeAmount, new(big.Float).SetInt64(basedef.Int64FromFigure(int(ethChainFee.TokenBasic.Precision))))
-	logs.Info("chain:%d l1MinFee=%s, l1ProxyFee=%s, l1FeeAmount=%s", chainId, l1MinFee.String(), l1ProxyFee.String(), l1FeeAmount.String())
 	return
 }
 
@@ -0,0 +1,249 @@
+package fee
+
+import (
+	"math"
+	"math/big"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCalibrationWindow     = 604800
+	defaultCalibrationMinSamples = 20
+	defaultCalibrationMaxSamples = 1000
+	defaultCalibrationPercentile = 90
+	defaultCalibrationMargin     = 110
+)
+
+// the sources of the gas limits
+const (
+	GasLimitOverride = "override"
+	GasLimitObserved = "observed"
+	GasLimitConfig   = "config"
+)
+
+var proxyTypes = []string{models.ProxyTypeLock, models.ProxyTypeNft, models.ProxyTypeSwap}
+
+// calibrationConfig returns the calibration config with the defaults, or nil if the gas limits are not calibrated
+func calibrationConfig() *conf.GasCalibrationConfig {
+	if conf.GlobalConfig == nil || conf.GlobalConfig.GasCalibrationConfig == nil {
+		return nil
+	}
+	cfg := *conf.GlobalConfig.GasCalibrationConfig
+	if cfg.Window <= 0 {
+		cfg.Window = defaultCalibrationWindow
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaultCalibrationMinSamples
+	}
+	if cfg.MaxSamples <= 0 {
+		cfg.MaxSamples = defaultCalibrationMaxSamples
+	}
+	if cfg.Percentile <= 0 || cfg.Percentile > 100 {
+		cfg.Percentile = defaultCalibrationPercentile
+	}
+	if cfg.Margin <= 0 {
+		cfg.Margin = defaultCalibrationMargin
+	}
+	return &cfg
+}
+
+// proxyContracts returns the proxy contracts of the proxy types on the chain, as the unlocks save them
+func proxyContracts(cfg *conf.ChainListenConfig) map[string][]string {
+	contracts := make(map[string][]string)
+	for _, contract := range cfg.ProxyContract {
+		contracts[models.ProxyTypeLock] = append(contracts[models.ProxyTypeLock], normalizeAddress(contract))
+	}
+	for _, proxy := range cfg.OtherProxyContract {
+		contracts[models.ProxyTypeLock] = append(contracts[models.ProxyTypeLock], normalizeAddress(proxy.ItemProxy))
+	}
+	for _, contract := range cfg.NFTProxyContract {
+		contracts[models.ProxyTypeNft] = append(contracts[models.ProxyTypeNft], normalizeAddress(contract))
+	}
+	if cfg.SwapContract != "" {
+		contracts[models.ProxyTypeSwap] = []string{normalizeAddress(cfg.SwapContract)}
+	}
+	return contracts
+}
+
+// ProxyType returns the proxy type of a cross chain transaction of the token standard sent to the contract on the chain
+func ProxyType(chainId uint64, contract string, standard uint8) string {
+	if standard == models.TokenTypeErc721 {
+		return models.ProxyTypeNft
+	}
+	if conf.GlobalConfig != nil {
+		if cfg := conf.GlobalConfig.GetChainListenConfig(chainId); cfg != nil && cfg.SwapContract != "" &&
+			normalizeAddress(cfg.SwapContract) == normalizeAddress(contract) {
+			return models.ProxyTypeSwap
+		}
+	}
+	return models.ProxyTypeLock
+}
+
+// percentile returns the nearest rank percentile of the sorted values
+func percentile(sorted []uint64, p int) uint64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func gasLimitStat(cfg *conf.GasCalibrationConfig, chainId uint64, proxyType string, gasUsed []uint64, now int64) *models.GasLimitStat {
+	sort.Slice(gasUsed, func(i, j int) bool { return gasUsed[i] < gasUsed[j] })
+	stat := &models.GasLimitStat{
+		ChainId:    chainId,
+		ProxyType:  proxyType,
+		Samples:    int64(len(gasUsed)),
+		P50:        percentile(gasUsed, 50),
+		P90:        percentile(gasUsed, 90),
+		P99:        percentile(gasUsed, 99),
+		UpdateTime: now,
+	}
+	if len(gasUsed) >= cfg.MinSamples {
+		stat.GasLimit = int64(percentile(gasUsed, cfg.Percentile)) * cfg.Margin / 100
+	}
+	return stat
+}
+
+// CalibrateGasLimits observes the gas used by the latest unlocks of the proxy types on the chains of the fee listen
+// configs, and saves their percentiles and the gas limits calibrated from them
+func CalibrateGasLimits(db *gorm.DB, now int64) ([]*models.GasLimitStat, error) {
+	cfg := calibrationConfig()
+	if cfg == nil {
+		return nil, nil
+	}
+	stats := make([]*models.GasLimitStat, 0)
+	for _, feeCfg := range conf.GlobalConfig.FeeListenConfig {
+		listenCfg := conf.GlobalConfig.GetChainListenConfig(feeCfg.ChainId)
+		if listenCfg == nil {
+			continue
+		}
+		contracts := proxyContracts(listenCfg)
+		for _, proxyType := range proxyTypes {
+			if len(contracts[proxyType]) == 0 {
+				continue
+			}
+			gasUsed := make([]uint64, 0)
+			err := db.Model(&models.DstTransaction{}).
+				Where("chain_id = ? and contract in ? and gas_used > 0 and time >= ?", feeCfg.ChainId, contracts[proxyType], now-cfg.Window).
+				Order("time desc").Limit(cfg.MaxSamples).Pluck("gas_used", &gasUsed).Error
+			if err != nil {
+				return stats, err
+			}
+			if len(gasUsed) == 0 {
+				continue
+			}
+			stat := gasLimitStat(cfg, feeCfg.ChainId, proxyType, gasUsed, now)
+			if err := db.Clauses(dbconn.UpsertOn("chain_id", "proxy_type")).Save(stat).Error; err != nil {
+				return stats, err
+			}
+			stats = append(stats, stat)
+		}
+	}
+	return stats, nil
+}
+
+// GasLimits are the gas limit stats of the proxy types of the chains
+type GasLimits map[uint64]map[string]*models.GasLimitStat
+
+func LoadGasLimits(db *gorm.DB) (GasLimits, error) {
+	stats := make([]*models.GasLimitStat, 0)
+	if err := db.Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	limits := make(GasLimits)
+	for _, stat := range stats {
+		if limits[stat.ChainId] == nil {
+			limits[stat.ChainId] = make(map[string]*models.GasLimitStat)
+		}
+		limits[stat.ChainId][stat.ProxyType] = stat
+	}
+	return limits, nil
+}
+
+// Stat returns the stat of the proxy type on the chain, nil if it is not observed
+func (limits GasLimits) Stat(chainId uint64, proxyType string) *models.GasLimitStat {
+	return limits[chainId][proxyType]
+}
+
+// GasLimit returns the gas limit of the proxy type on the chain and its source, which is the override of the fee
+// listen config, the calibrated gas limit or GasLimit of the fee listen config in order
+func (limits GasLimits) GasLimit(cfg *conf.FeeListenConfig, proxyType string) (int64, string) {
+	if limit := cfg.GasLimits[proxyType]; limit > 0 {
+		return limit, GasLimitOverride
+	}
+	if stat := limits.Stat(cfg.ChainId, proxyType); stat != nil && stat.GasLimit > 0 {
+		return stat.GasLimit, GasLimitObserved
+	}
+	return cfg.GasLimit, GasLimitConfig
+}
+
+func scaleFee(fee *models.BigInt, limit, gasLimit int64) *models.BigInt {
+	if fee == nil {
+		return nil
+	}
+	scaled := new(big.Int).Mul(&fee.Int, big.NewInt(limit))
+	return models.NewBigInt(scaled.Div(scaled, big.NewInt(gasLimit)))
+}
+
+// ChainFee returns the chain fee for the proxy type, the fees of the chain are of GasLimit of its fee listen config
+// and are scaled to the gas limit of the proxy type
+func (limits GasLimits) ChainFee(chainFee *models.ChainFee, proxyType string) *models.ChainFee {
+	if conf.GlobalConfig == nil {
+		return chainFee
+	}
+	cfg := conf.GlobalConfig.GetFeeListenConfig(chainFee.ChainId)
+	if cfg == nil || cfg.GasLimit <= 0 {
+		return chainFee
+	}
+	limit, _ := limits.GasLimit(cfg, proxyType)
+	if limit == cfg.GasLimit {
+		return chainFee
+	}
+	scaled := *chainFee
+	scaled.MinFee = scaleFee(chainFee.MinFee, limit, cfg.GasLimit)
+	scaled.MaxFee = scaleFee(chainFee.MaxFee, limit, cfg.GasLimit)
+	scaled.ProxyFee = scaleFee(chainFee.ProxyFee, limit, cfg.GasLimit)
+	return &scaled
+}
+
+// Report compares the gas limits of the proxy types on the chains of the fee listen configs with the observed ones.
+// The lock proxy is always reported, the other proxy types are reported when they are observed or overridden.
+func (limits GasLimits) Report() []*models.GasLimitRsp {
+	rsps := make([]*models.GasLimitRsp, 0)
+	if conf.GlobalConfig == nil {
+		return rsps
+	}
+	for _, cfg := range conf.GlobalConfig.FeeListenConfig {
+		for _, proxyType := range proxyTypes {
+			stat := limits.Stat(cfg.ChainId, proxyType)
+			if proxyType != models.ProxyTypeLock && stat == nil && cfg.GasLimits[proxyType] <= 0 {
+				continue
+			}
+			rsp := &models.GasLimitRsp{
+				ChainId:            cfg.ChainId,
+				ChainName:          cfg.ChainName,
+				ProxyType:          proxyType,
+				ConfiguredGasLimit: cfg.GasLimit,
+				OverrideGasLimit:   cfg.GasLimits[proxyType],
+			}
+			if stat != nil {
+				rsp.ObservedGasLimit = stat.GasLimit
+				rsp.Samples = stat.Samples
+				rsp.P50, rsp.P90, rsp.P99 = stat.P50, stat.P90, stat.P99
+				rsp.UpdateTime = stat.UpdateTime
+			}
+			rsp.GasLimit, rsp.Source = limits.GasLimit(cfg, proxyType)
+			rsps = append(rsps, rsp)
+		}
+	}
+	return rsps
+}
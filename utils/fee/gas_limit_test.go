@@ -0,0 +1,101 @@
+package fee
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestCalibrateGasLimits(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		ChainListenConfig: []*conf.ChainListenConfig{{
+			ChainId:          6,
+			ProxyContract:    []string{"0x2f7ac9436ba4B548f9582af91CA1Ef02cd2F1f03"},
+			NFTProxyContract: []string{"2cdfc90250EF967036838DA601099656e74bCfc5"},
+			SwapContract:     "af83ce8d461e8834de03a3803c968615013c6b3d",
+		}},
+		FeeListenConfig:      []*conf.FeeListenConfig{{ChainId: 6, ChainName: "BSC", GasLimit: 200000, GasLimits: map[string]int64{models.ProxyTypeSwap: 400000}}},
+		GasCalibrationConfig: &conf.GasCalibrationConfig{Window: 1000, MinSamples: 10},
+	}
+	db, err := dbconn.Open(&conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "fee_calibrate_gas_limits"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&models.DstTransaction{}, &models.GasLimitStat{}))
+
+	dstTransactions := make([]*models.DstTransaction, 0)
+	for i := 1; i <= 20; i++ {
+		// the lock proxy unlocks use 100000 to 119000 gas
+		dstTransactions = append(dstTransactions, &models.DstTransaction{
+			Hash: fmt.Sprintf("lock%d", i), ChainId: 6, Time: 2000, Fee: models.NewBigIntFromInt(0),
+			Contract: "2f7ac9436ba4b548f9582af91ca1ef02cd2f1f03", GasUsed: uint64(99000 + i*1000),
+		})
+	}
+	dstTransactions = append(dstTransactions,
+		// too few nft unlocks, an unlock out of the window and an unlock without the gas used
+		&models.DstTransaction{Hash: "nft", ChainId: 6, Time: 2000, Fee: models.NewBigIntFromInt(0), Contract: "2cdfc90250ef967036838da601099656e74bcfc5", GasUsed: 300000},
+		&models.DstTransaction{Hash: "old", ChainId: 6, Time: 500, Fee: models.NewBigIntFromInt(0), Contract: "2f7ac9436ba4b548f9582af91ca1ef02cd2f1f03", GasUsed: 900000},
+		&models.DstTransaction{Hash: "legacy", ChainId: 6, Time: 2000, Fee: models.NewBigIntFromInt(0), Contract: "2f7ac9436ba4b548f9582af91ca1ef02cd2f1f03"},
+	)
+	assert.NoError(t, db.Create(dstTransactions).Error)
+
+	stats, err := CalibrateGasLimits(db, 2100)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	// calibrated again the stats are updated
+	stats, err = CalibrateGasLimits(db, 2100)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	limits, err := LoadGasLimits(db)
+	assert.NoError(t, err)
+	lock := limits.Stat(6, models.ProxyTypeLock)
+	if assert.NotNil(t, lock) {
+		assert.Equal(t, int64(20), lock.Samples)
+		assert.Equal(t, uint64(109000), lock.P50)
+		assert.Equal(t, uint64(117000), lock.P90)
+		assert.Equal(t, uint64(119000), lock.P99)
+		assert.Equal(t, int64(117000*110/100), lock.GasLimit)
+	}
+	nft := limits.Stat(6, models.ProxyTypeNft)
+	if assert.NotNil(t, nft) {
+		assert.Equal(t, int64(1), nft.Samples)
+		assert.Equal(t, int64(0), nft.GasLimit)
+	}
+
+	feeCfg := conf.GlobalConfig.FeeListenConfig[0]
+	limit, source := limits.GasLimit(feeCfg, models.ProxyTypeLock)
+	assert.Equal(t, int64(128700), limit)
+	assert.Equal(t, GasLimitObserved, source)
+	limit, source = limits.GasLimit(feeCfg, models.ProxyTypeNft)
+	assert.Equal(t, int64(200000), limit)
+	assert.Equal(t, GasLimitConfig, source)
+	limit, source = limits.GasLimit(feeCfg, models.ProxyTypeSwap)
+	assert.Equal(t, int64(400000), limit)
+	assert.Equal(t, GasLimitOverride, source)
+
+	chainFee := &models.ChainFee{ChainId: 6, MinFee: models.NewBigIntFromInt(2000), MaxFee: models.NewBigIntFromInt(10000), ProxyFee: models.NewBigIntFromInt(20000)}
+	swapFee := limits.ChainFee(chainFee, models.ProxyTypeSwap)
+	assert.Equal(t, "4000", swapFee.MinFee.String())
+	assert.Equal(t, "40000", swapFee.ProxyFee.String())
+	assert.Equal(t, "20000", chainFee.ProxyFee.String())
+	assert.Equal(t, chainFee, limits.ChainFee(chainFee, models.ProxyTypeNft))
+	// without the stats the fees are of the configured gas limit
+	assert.Equal(t, chainFee, GasLimits(nil).ChainFee(chainFee, models.ProxyTypeLock))
+
+	assert.Equal(t, models.ProxyTypeSwap, ProxyType(6, "0xAF83CE8D461E8834DE03A3803C968615013C6B3D", models.TokenTypeErc20))
+	assert.Equal(t, models.ProxyTypeNft, ProxyType(6, "2f7ac9436ba4b548f9582af91ca1ef02cd2f1f03", models.TokenTypeErc721))
+	assert.Equal(t, models.ProxyTypeLock, ProxyType(6, "2f7ac9436ba4b548f9582af91ca1ef02cd2f1f03", models.TokenTypeErc20))
+
+	report := limits.Report()
+	if assert.Len(t, report, 3) {
+		assert.Equal(t, models.ProxyTypeLock, report[0].ProxyType)
+		assert.Equal(t, int64(200000), report[0].ConfiguredGasLimit)
+		assert.Equal(t, int64(128700), report[0].ObservedGasLimit)
+		assert.Equal(t, int64(128700), report[0].GasLimit)
+		assert.Equal(t, models.ProxyTypeSwap, report[2].ProxyType)
+		assert.Equal(t, GasLimitOverride, report[2].Source)
+	}
+}
@@ -0,0 +1,66 @@
+package fee
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+)
+
+func TestGetL1Fee(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{
+		FeeListenConfig: []*conf.FeeListenConfig{
+			{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, ChainName: "Ethereum", GasLimit: 100000},
+			{ChainId: basedef.OPTIMISTIC_CROSSCHAIN_ID, ChainName: "Optimistic", MinFee: 20, ProxyFee: 150, EthL1GasLimit: 20000, L1FeeOracle: "optimism"},
+			{ChainId: basedef.ARBITRUM_CROSSCHAIN_ID, ChainName: "Arbitrum", MinFee: 20, ProxyFee: 150},
+		},
+	}
+	assert.True(t, HasL1Fee(basedef.OPTIMISTIC_CROSSCHAIN_ID))
+	assert.False(t, HasL1Fee(basedef.ARBITRUM_CROSSCHAIN_ID))
+	assert.False(t, HasL1Fee(basedef.BSC_CROSSCHAIN_ID))
+
+	eth := &models.TokenBasic{Name: "Ethereum", Precision: 18, Price: 2000 * basedef.PRICE_PRECISION}
+	// 0.0001 eth read from the oracle
+	l1Fee := new(big.Int).Mul(big.NewInt(100000000000000), big.NewInt(basedef.FEE_PRECISION))
+	chainFee := &models.ChainFee{ChainId: basedef.OPTIMISTIC_CROSSCHAIN_ID, TokenBasic: eth, L1Fee: models.NewBigInt(l1Fee), L1FeeTime: time.Now().Unix()}
+	l1MinFee, l1ProxyFee, l1FeeAmount, err := GetL1Fee(chainFee, nil)
+	assert.NoError(t, err)
+	amount, _ := l1FeeAmount.Float64()
+	assert.InDelta(t, 0.0001, amount, 1e-12)
+	minFee, _ := l1MinFee.Float64()
+	assert.InDelta(t, 0.04, minFee, 1e-9)
+	proxyFee, _ := l1ProxyFee.Float64()
+	assert.InDelta(t, 0.3, proxyFee, 1e-9)
+
+	// without the reading of the oracle the fee of ethereum is scaled, 50 gwei for the gas limit of ethereum
+	ethFee := new(big.Int).Mul(big.NewInt(5000000000000000), big.NewInt(basedef.FEE_PRECISION))
+	ethChainFee := &models.ChainFee{
+		ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, TokenBasic: eth,
+		MinFee: models.NewBigInt(ethFee), MaxFee: models.NewBigInt(ethFee), ProxyFee: models.NewBigInt(ethFee),
+	}
+	// the reading older than the max age is not used either
+	chainFee.L1FeeTime = time.Now().Unix() - 601
+	_, _, l1FeeAmount, err = GetL1Fee(chainFee, ethChainFee)
+	assert.NoError(t, err)
+	amount, _ = l1FeeAmount.Float64()
+	assert.InDelta(t, 0.001, amount, 1e-12)
+	chainFee.L1Fee = models.NewBigIntFromInt(0)
+	_, l1ProxyFee, l1FeeAmount, err = GetL1Fee(chainFee, ethChainFee)
+	assert.NoError(t, err)
+	amount, _ = l1FeeAmount.Float64()
+	assert.InDelta(t, 0.001, amount, 1e-12)
+	proxyFee, _ = l1ProxyFee.Float64()
+	assert.InDelta(t, 2.0, proxyFee, 1e-9)
+
+	_, _, _, err = GetL1Fee(chainFee, nil)
+	assert.Error(t, err)
+
+	// the stale reading is not scaled to a zero fee without EthL1GasLimit
+	conf.GlobalConfig.FeeListenConfig[1].EthL1GasLimit = 0
+	_, _, _, err = GetL1Fee(chainFee, ethChainFee)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package fee
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"poly-bridge/conf"
+	"poly-bridge/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultQuoteExpiry    = 300
+	defaultQuoteRetention = 86400
+)
+
+func quoteConfig() *conf.FeeQuoteConfig {
+	if conf.GlobalConfig == nil || conf.GlobalConfig.FeeQuoteConfig == nil || conf.GlobalConfig.FeeQuoteConfig.Secret == "" {
+		return nil
+	}
+	return conf.GlobalConfig.FeeQuoteConfig
+}
+
+// QuoteEnabled reports whether the fees are quoted, which needs the secret of the quotes
+func QuoteEnabled() bool {
+	return quoteConfig() != nil
+}
+
+// normalizeAddress formats an address or a hash as the transactions are saved, in lower case without 0x
+func normalizeAddress(address string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(address)), "0x")
+}
+
+// SignQuote returns the hex hmac-sha256 of the fields of the quote by the secret
+func SignQuote(secret string, quote *models.FeeQuote) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:%d:%d:%s:%s:%d:%d", quote.Id, quote.User, quote.SrcChainId, quote.DstChainId,
+		quote.FeeTokenHash, quote.Amount.String(), quote.Time, quote.ExpireTime)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewQuote makes a signed quote of the fee amount of the fee token for the user on the route, which is valid from now.
+// It returns nil when the fees are not quoted.
+func NewQuote(user string, srcChainId, dstChainId uint64, feeTokenHash string, amount *big.Int, now int64) (*models.FeeQuote, error) {
+	cfg := quoteConfig()
+	if cfg == nil {
+		return nil, nil
+	}
+	expiry := cfg.Expiry
+	if expiry <= 0 {
+		expiry = defaultQuoteExpiry
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	quote := &models.FeeQuote{
+		Id:           hex.EncodeToString(id),
+		User:         normalizeAddress(user),
+		SrcChainId:   srcChainId,
+		DstChainId:   dstChainId,
+		FeeTokenHash: normalizeAddress(feeTokenHash),
+		Amount:       models.NewBigInt(amount),
+		Time:         now,
+		ExpireTime:   now + expiry,
+	}
+	quote.Signature = SignQuote(cfg.Secret, quote)
+	return quote, nil
+}
+
+// QuotedFee returns the quote honoured by the wrapper transaction, which is a quote of its user on its route valid at
+// its time with an amount of its fee token not larger than its fee amount. It returns nil if there is no such quote.
+func QuotedFee(db *gorm.DB, wrapper *models.WrapperTransaction) (*models.FeeQuote, error) {
+	cfg := quoteConfig()
+	if cfg == nil || wrapper.FeeAmount == nil {
+		return nil, nil
+	}
+	txTime := int64(wrapper.Time)
+	quotes := make([]*models.FeeQuote, 0)
+	err := db.Where("fee_quotes.user = ? and src_chain_id = ? and dst_chain_id = ? and fee_token_hash = ? and time <= ? and expire_time >= ?",
+		normalizeAddress(wrapper.User), wrapper.SrcChainId, wrapper.DstChainId, normalizeAddress(wrapper.FeeTokenHash), txTime, txTime).
+		Find(&quotes).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, quote := range quotes {
+		if quote.Amount == nil || quote.Amount.Cmp(&wrapper.FeeAmount.Int) > 0 {
+			continue
+		}
+		if hmac.Equal([]byte(quote.Signature), []byte(SignQuote(cfg.Secret, quote))) {
+			return quote, nil
+		}
+	}
+	return nil, nil
+}
+
+// PruneQuotes deletes the quotes expired for longer than the retention
+func PruneQuotes(db *gorm.DB, now int64) (int64, error) {
+	cfg := quoteConfig()
+	if cfg == nil {
+		return 0, nil
+	}
+	retention := cfg.Retention
+	if retention <= 0 {
+		retention = defaultQuoteRetention
+	}
+	res := db.Where("expire_time < ?", now-retention).Delete(&models.FeeQuote{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,59 @@
+package fee
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestQuotedFee(t *testing.T) {
+	conf.GlobalConfig = &conf.Config{FeeQuoteConfig: &conf.FeeQuoteConfig{Secret: "secret", Expiry: 60}}
+	db, err := dbconn.Open(&conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "fee_quoted_fee"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&models.FeeQuote{}))
+
+	quote, err := NewQuote("0xAD79c606bd4ef330ac45df9d2ace4e7e7c6db13f", 2, 6, "0000000000000000000000000000000000000000", big.NewInt(1000), 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "ad79c606bd4ef330ac45df9d2ace4e7e7c6db13f", quote.User)
+	assert.Equal(t, int64(1060), quote.ExpireTime)
+	assert.NoError(t, db.Create(quote).Error)
+
+	wrapper := func(amount int64, time uint64) *models.WrapperTransaction {
+		return &models.WrapperTransaction{
+			User: "ad79c606bd4ef330ac45df9d2ace4e7e7c6db13f", SrcChainId: 2, DstChainId: 6,
+			FeeTokenHash: "0000000000000000000000000000000000000000", FeeAmount: models.NewBigIntFromInt(amount), Time: time,
+		}
+	}
+	found, err := QuotedFee(db, wrapper(1000, 1030))
+	assert.NoError(t, err)
+	if assert.NotNil(t, found) {
+		assert.Equal(t, quote.Id, found.Id)
+	}
+	// underpaid, expired or on another route
+	found, _ = QuotedFee(db, wrapper(999, 1030))
+	assert.Nil(t, found)
+	found, _ = QuotedFee(db, wrapper(1000, 1061))
+	assert.Nil(t, found)
+	other := wrapper(1000, 1030)
+	other.DstChainId = 7
+	found, _ = QuotedFee(db, other)
+	assert.Nil(t, found)
+
+	// the quotes which are not signed by the secret are ignored
+	assert.NoError(t, db.Model(&models.FeeQuote{}).Where("id = ?", quote.Id).Update("amount", models.NewBigIntFromInt(1)).Error)
+	found, _ = QuotedFee(db, wrapper(500, 1030))
+	assert.Nil(t, found)
+
+	pruned, err := PruneQuotes(db, 1060+defaultQuoteRetention+1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	conf.GlobalConfig.FeeQuoteConfig = nil
+	quote, err = NewQuote("ad79c606bd4ef330ac45df9d2ace4e7e7c6db13f", 2, 6, "0000000000000000000000000000000000000000", big.NewInt(1000), 1000)
+	assert.NoError(t, err)
+	assert.Nil(t, quote)
+}
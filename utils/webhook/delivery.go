@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beego/beego/v2/core/logs"
+	"gorm.io/gorm"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+)
+
+const (
+	deliveryBatch      = 100
+	defaultMaxAttempts = 10
+	defaultTimeout     = 10
+	backoffBase        = 30
+	backoffMax         = 3600
+	lastErrorSize      = 256
+)
+
+// Deliverer posts the due deliveries to the webhooks
+type Deliverer struct {
+	db          *gorm.DB
+	client      *http.Client
+	maxAttempts int
+}
+
+func NewDeliverer(db *gorm.DB, maxAttempts int, timeout int64) *Deliverer {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Deliverer{
+		db:          db,
+		client:      &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Backoff is the seconds before the next attempt of a delivery which failed the attempts
+func Backoff(attempts int) int64 {
+	backoff := int64(backoffBase)
+	for i := 1; i < attempts && backoff < backoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > backoffMax {
+		backoff = backoffMax
+	}
+	return backoff
+}
+
+// Deliver posts the deliveries which are due at now, a failed delivery is retried with backoff and given up after
+// the max attempts. It returns the number of the delivered ones.
+func (d *Deliverer) Deliver(now int64) (int, error) {
+	deliveries := make([]*models.WebhookDelivery, 0)
+	err := d.db.Where("status = ? and next_time <= ?", basedef.WEBHOOK_DELIVERY_PENDING, now).
+		Order("next_time").Limit(deliveryBatch).Find(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+	webhookIds := make([]int64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		webhookIds = append(webhookIds, delivery.WebhookId)
+	}
+	webhookList := make([]*models.Webhook, 0)
+	if err = d.db.Where("id in ?", webhookIds).Find(&webhookList).Error; err != nil {
+		return 0, err
+	}
+	webhooks := make(map[int64]*models.Webhook, len(webhookList))
+	for _, webhook := range webhookList {
+		webhooks[webhook.Id] = webhook
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookId]
+		if !ok || webhook.Disabled {
+			delivery.Status = basedef.WEBHOOK_DELIVERY_FAILED
+			delivery.LastError = "webhook is removed or disabled"
+		} else {
+			delivery.Attempts++
+			code, err := d.post(webhook, delivery, now)
+			delivery.LastCode = code
+			delivery.LastError = ""
+			if err == nil {
+				delivery.Status = basedef.WEBHOOK_DELIVERY_DELIVERED
+				delivery.DeliveredTime = now
+				delivered++
+			} else {
+				delivery.LastError = err.Error()
+				if len(delivery.LastError) > lastErrorSize {
+					delivery.LastError = delivery.LastError[:lastErrorSize]
+				}
+				if delivery.Attempts >= d.maxAttempts {
+					delivery.Status = basedef.WEBHOOK_DELIVERY_FAILED
+					logs.Error("webhook %d delivery %d of %s %s is given up after %d attempts: %s",
+						webhook.Id, delivery.Id, delivery.Event, delivery.Hash, delivery.Attempts, delivery.LastError)
+				} else {
+					delivery.NextTime = now + Backoff(delivery.Attempts)
+				}
+			}
+		}
+		if err = d.db.Save(delivery).Error; err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+// post sends the payload with the headers of its event, delivery, timestamp and signature. A 2xx response delivers it.
+func (d *Deliverer) post(webhook *models.Webhook, delivery *models.WebhookDelivery, now int64) (int, error) {
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Poly-Bridge-Event", delivery.Event)
+	req.Header.Set("X-Poly-Bridge-Delivery", strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set("X-Poly-Bridge-Timestamp", strconv.FormatInt(now, 10))
+	req.Header.Set("X-Poly-Bridge-Signature", "sha256="+Sign(webhook.Secret, now, payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("response status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
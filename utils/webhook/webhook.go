@@ -0,0 +1,280 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"poly-bridge/basedef"
+	"poly-bridge/models"
+)
+
+var Events = []string{
+	basedef.WEBHOOK_EVENT_WRAPPER,
+	basedef.WEBHOOK_EVENT_SRC,
+	basedef.WEBHOOK_EVENT_POLY,
+	basedef.WEBHOOK_EVENT_DST,
+	basedef.WEBHOOK_EVENT_FINISHED,
+}
+
+func ValidEvent(event string) bool {
+	for _, v := range Events {
+		if v == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign is the signature of a payload sent at the timestamp, the hex of the HMAC-SHA256 of "timestamp.payload" with the secret
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+}
+
+// subject is what the filters of the webhooks match for an event, both sides of its cross chain transaction
+type subject struct {
+	chains    map[uint64]bool
+	tokens    map[string]bool
+	addresses map[string]bool
+	contracts map[string]bool
+}
+
+func newSubject() *subject {
+	return &subject{
+		chains:    make(map[uint64]bool),
+		tokens:    make(map[string]bool),
+		addresses: make(map[string]bool),
+		contracts: make(map[string]bool),
+	}
+}
+
+func addAll(set map[string]bool, values ...string) {
+	for _, v := range values {
+		if v != "" {
+			set[normalize(v)] = true
+		}
+	}
+}
+
+func (s *subject) addWrapper(wrapper *models.WrapperTransaction) {
+	s.chains[wrapper.SrcChainId] = true
+	s.chains[wrapper.DstChainId] = true
+	addAll(s.addresses, wrapper.User, wrapper.DstUser)
+}
+
+func (s *subject) addSrc(src *models.SrcTransaction) {
+	s.chains[src.ChainId] = true
+	s.chains[src.DstChainId] = true
+	addAll(s.addresses, src.User)
+	addAll(s.contracts, src.Contract)
+	if src.SrcTransfer != nil {
+		addAll(s.tokens, src.SrcTransfer.Asset, src.SrcTransfer.DstAsset)
+		addAll(s.addresses, src.SrcTransfer.From, src.SrcTransfer.DstUser)
+	}
+	if src.SrcSwap != nil {
+		addAll(s.tokens, src.SrcSwap.Asset, src.SrcSwap.DstAsset)
+		addAll(s.addresses, src.SrcSwap.From, src.SrcSwap.DstUser)
+	}
+}
+
+func (s *subject) addPoly(poly *models.PolyTransaction) {
+	s.chains[poly.SrcChainId] = true
+	s.chains[poly.DstChainId] = true
+}
+
+func (s *subject) addDst(dst *models.DstTransaction) {
+	s.chains[dst.ChainId] = true
+	s.chains[dst.SrcChainId] = true
+	addAll(s.contracts, dst.Contract)
+	if dst.DstTransfer != nil {
+		addAll(s.tokens, dst.DstTransfer.Asset)
+		addAll(s.addresses, dst.DstTransfer.To)
+	}
+	if dst.DstSwap != nil {
+		addAll(s.tokens, dst.DstSwap.DstAsset)
+		addAll(s.addresses, dst.DstSwap.DstUser)
+	}
+}
+
+func matches(webhook *models.Webhook, event string, s *subject) bool {
+	if events := webhook.EventList(); len(events) > 0 {
+		found := false
+		for _, v := range events {
+			found = found || v == event
+		}
+		if !found {
+			return false
+		}
+	}
+	return (webhook.ChainId == 0 || s.chains[webhook.ChainId]) &&
+		(webhook.Token == "" || s.tokens[normalize(webhook.Token)]) &&
+		(webhook.Address == "" || s.addresses[normalize(webhook.Address)]) &&
+		(webhook.Contract == "" || s.contracts[normalize(webhook.Contract)])
+}
+
+// event is an event of a transaction, which is matched with the source transaction of its cross chain transaction
+type event struct {
+	name        string
+	hash        string
+	chainId     uint64
+	srcHash     string
+	transaction interface{}
+	subject     *subject
+}
+
+// Emit records the deliveries of the arrival events of the saved transactions to the matching webhooks.
+// The poly and destination transactions are matched with their source transactions too.
+func Emit(db *gorm.DB, wrapperTransactions []*models.WrapperTransaction, srcTransactions []*models.SrcTransaction,
+	polyTransactions []*models.PolyTransaction, dstTransactions []*models.DstTransaction) error {
+	webhooks, err := enabledWebhooks(db)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+	events := make([]*event, 0)
+	for _, wrapper := range wrapperTransactions {
+		e := &event{name: basedef.WEBHOOK_EVENT_WRAPPER, hash: wrapper.Hash, chainId: wrapper.SrcChainId, srcHash: wrapper.Hash, transaction: wrapper, subject: newSubject()}
+		e.subject.addWrapper(wrapper)
+		events = append(events, e)
+	}
+	for _, src := range srcTransactions {
+		e := &event{name: basedef.WEBHOOK_EVENT_SRC, hash: src.Hash, chainId: src.ChainId, srcHash: src.Hash, transaction: src, subject: newSubject()}
+		e.subject.addSrc(src)
+		events = append(events, e)
+	}
+	polySrcHashes := make(map[string]string, len(polyTransactions))
+	for _, poly := range polyTransactions {
+		polySrcHashes[poly.Hash] = poly.SrcHash
+		e := &event{name: basedef.WEBHOOK_EVENT_POLY, hash: poly.Hash, chainId: poly.ChainId, srcHash: poly.SrcHash, transaction: poly, subject: newSubject()}
+		e.subject.addPoly(poly)
+		events = append(events, e)
+	}
+	if len(dstTransactions) > 0 {
+		polyHashes := make([]string, 0, len(dstTransactions))
+		for _, dst := range dstTransactions {
+			if _, ok := polySrcHashes[dst.PolyHash]; !ok && dst.PolyHash != "" {
+				polyHashes = append(polyHashes, dst.PolyHash)
+			}
+		}
+		if len(polyHashes) > 0 {
+			polys := make([]*models.PolyTransaction, 0)
+			if err = db.Where("hash in ?", polyHashes).Find(&polys).Error; err != nil {
+				return err
+			}
+			for _, poly := range polys {
+				polySrcHashes[poly.Hash] = poly.SrcHash
+			}
+		}
+	}
+	for _, dst := range dstTransactions {
+		e := &event{name: basedef.WEBHOOK_EVENT_DST, hash: dst.Hash, chainId: dst.ChainId, srcHash: polySrcHashes[dst.PolyHash], transaction: dst, subject: newSubject()}
+		e.subject.addDst(dst)
+		events = append(events, e)
+	}
+	if err = addSources(db, events, srcTransactions); err != nil {
+		return err
+	}
+	return record(db, webhooks, events)
+}
+
+// EmitFinished records the deliveries of the finished wrapper transactions to the matching webhooks
+func EmitFinished(db *gorm.DB, wrapperTransactions []*models.WrapperTransaction) error {
+	webhooks, err := enabledWebhooks(db)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+	events := make([]*event, 0)
+	for _, wrapper := range wrapperTransactions {
+		if wrapper.Status != basedef.STATE_FINISHED {
+			continue
+		}
+		e := &event{name: basedef.WEBHOOK_EVENT_FINISHED, hash: wrapper.Hash, chainId: wrapper.SrcChainId, srcHash: wrapper.Hash, transaction: wrapper, subject: newSubject()}
+		e.subject.addWrapper(wrapper)
+		events = append(events, e)
+	}
+	if err = addSources(db, events, nil); err != nil {
+		return err
+	}
+	return record(db, webhooks, events)
+}
+
+func enabledWebhooks(db *gorm.DB) ([]*models.Webhook, error) {
+	webhooks := make([]*models.Webhook, 0)
+	err := db.Where("disabled = ?", false).Find(&webhooks).Error
+	return webhooks, err
+}
+
+// addSources adds the source transactions, of the batch or of the database, to the subjects of the events
+func addSources(db *gorm.DB, events []*event, srcTransactions []*models.SrcTransaction) error {
+	srcs := make(map[string]*models.SrcTransaction, len(srcTransactions))
+	for _, src := range srcTransactions {
+		srcs[src.Hash] = src
+	}
+	missing := make([]string, 0)
+	for _, e := range events {
+		if _, ok := srcs[e.srcHash]; !ok && e.srcHash != "" {
+			missing = append(missing, e.srcHash)
+		}
+	}
+	if len(missing) > 0 {
+		found := make([]*models.SrcTransaction, 0)
+		if err := db.Preload("SrcTransfer").Preload("SrcSwap").Where("hash in ?", missing).Find(&found).Error; err != nil {
+			return err
+		}
+		for _, src := range found {
+			srcs[src.Hash] = src
+		}
+	}
+	for _, e := range events {
+		if src, ok := srcs[e.srcHash]; ok {
+			e.subject.addSrc(src)
+		}
+	}
+	return nil
+}
+
+// record creates the pending deliveries, an event is delivered once to a webhook when its transaction is saved again
+func record(db *gorm.DB, webhooks []*models.Webhook, events []*event) error {
+	now := time.Now().Unix()
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for _, e := range events {
+		var payload []byte
+		for _, webhook := range webhooks {
+			if !matches(webhook, e.name, e.subject) {
+				continue
+			}
+			if payload == nil {
+				var err error
+				payload, err = json.Marshal(&models.WebhookEvent{Event: e.name, Hash: e.hash, ChainId: e.chainId, Time: now, Transaction: e.transaction})
+				if err != nil {
+					return err
+				}
+			}
+			deliveries = append(deliveries, &models.WebhookDelivery{
+				WebhookId: webhook.Id,
+				Event:     e.name,
+				Hash:      e.hash,
+				Payload:   string(payload),
+				Status:    basedef.WEBHOOK_DELIVERY_PENDING,
+				NextTime:  now,
+				Time:      now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(deliveries).Error
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"poly-bridge/basedef"
+	"poly-bridge/conf"
+	"poly-bridge/dbconn"
+	"poly-bridge/models"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, int64(30), Backoff(1))
+	assert.Equal(t, int64(60), Backoff(2))
+	assert.Equal(t, int64(240), Backoff(4))
+	assert.Equal(t, int64(3600), Backoff(20))
+}
+
+func TestEmitAndDeliver(t *testing.T) {
+	db, err := dbconn.Open(&conf.DBConfig{Dialect: dbconn.DialectSqlite, Scheme: "webhook_emit"}, nil)
+	assert.NoError(t, err)
+
+	var responses int32
+	var signed, verified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Poly-Bridge-Timestamp"), 10, 64)
+		atomic.AddInt32(&signed, 1)
+		if r.Header.Get("X-Poly-Bridge-Signature") == "sha256="+Sign("secret", timestamp, body) {
+			atomic.AddInt32(&verified, 1)
+		}
+		// the first delivery fails once
+		if atomic.AddInt32(&responses, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	user := "8bc7e7304120b88d111431f6a4853589d10e8132"
+	webhooks := []*models.Webhook{
+		{Partner: "wallet", Url: server.URL, Secret: "secret", Address: "0x" + user},
+		{Partner: "bsc", Url: server.URL, Secret: "secret", ChainId: 6},
+		{Partner: "usdt", Url: server.URL, Secret: "secret", Token: "dAC17F958D2ee523a2206206994597C13D831ec7", Events: "dst,finished"},
+	}
+	assert.NoError(t, db.Create(webhooks).Error)
+
+	src := &models.SrcTransaction{
+		Hash: "01", ChainId: 2, DstChainId: 4, User: user, Fee: models.NewBigIntFromInt(0),
+		SrcTransfer: &models.SrcTransfer{TxHash: "01", ChainId: 2, Asset: "dac17f958d2ee523a2206206994597c13d831ec7", From: user, DstChainId: 4, Amount: models.NewBigIntFromInt(1)},
+	}
+	poly := &models.PolyTransaction{Hash: "02", SrcChainId: 2, SrcHash: "01", DstChainId: 4, Fee: models.NewBigIntFromInt(0)}
+	dst := &models.DstTransaction{Hash: "03", ChainId: 4, SrcChainId: 2, PolyHash: "02", Fee: models.NewBigIntFromInt(0),
+		DstTransfer: &models.DstTransfer{TxHash: "03", ChainId: 4, Asset: "5f4f3d4c4b4a49484746454443424140", To: "ARpuQar5CPtxEoqfcg1fxGWnwDdp7w3jj8", Amount: models.NewBigIntFromInt(1)}}
+	assert.NoError(t, db.Create(src).Error)
+	assert.NoError(t, db.Create(poly).Error)
+	assert.NoError(t, Emit(db, nil, []*models.SrcTransaction{src}, []*models.PolyTransaction{poly}, nil))
+	// the destination transaction is matched with its source transaction through the poly transaction
+	assert.NoError(t, Emit(db, nil, nil, nil, []*models.DstTransaction{dst}))
+	assert.NoError(t, Emit(db, nil, []*models.SrcTransaction{src}, nil, nil))
+	wrapper := &models.WrapperTransaction{Hash: "01", User: user, SrcChainId: 2, DstChainId: 4, Status: basedef.STATE_FINISHED}
+	assert.NoError(t, EmitFinished(db, []*models.WrapperTransaction{wrapper}))
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	assert.NoError(t, db.Order("id").Find(&deliveries).Error)
+	events := make(map[int64][]string)
+	for _, delivery := range deliveries {
+		events[delivery.WebhookId] = append(events[delivery.WebhookId], delivery.Event)
+	}
+	assert.Equal(t, []string{basedef.WEBHOOK_EVENT_SRC, basedef.WEBHOOK_EVENT_POLY, basedef.WEBHOOK_EVENT_DST, basedef.WEBHOOK_EVENT_FINISHED}, events[webhooks[0].Id])
+	assert.Empty(t, events[webhooks[1].Id])
+	assert.Equal(t, []string{basedef.WEBHOOK_EVENT_DST, basedef.WEBHOOK_EVENT_FINISHED}, events[webhooks[2].Id])
+	payload := new(models.WebhookEvent)
+	assert.NoError(t, json.Unmarshal([]byte(deliveries[0].Payload), payload))
+	assert.Equal(t, basedef.WEBHOOK_EVENT_SRC, payload.Event)
+	assert.Equal(t, "01", payload.Hash)
+
+	now := time.Now().Unix()
+	deliverer := NewDeliverer(db, 2, 5)
+	delivered, err := deliverer.Deliver(now)
+	assert.NoError(t, err)
+	assert.Equal(t, len(deliveries)-1, delivered)
+	failed := new(models.WebhookDelivery)
+	assert.NoError(t, db.Where("status = ?", basedef.WEBHOOK_DELIVERY_PENDING).First(failed).Error)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, failed.LastCode)
+	assert.Equal(t, now+30, failed.NextTime)
+
+	delivered, err = deliverer.Deliver(now + 29)
+	assert.NoError(t, err)
+	assert.Zero(t, delivered)
+	delivered, err = deliverer.Deliver(now + 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.NoError(t, db.First(failed, failed.Id).Error)
+	assert.Equal(t, basedef.WEBHOOK_DELIVERY_DELIVERED, failed.Status)
+	assert.Equal(t, now+30, failed.DeliveredTime)
+	assert.Equal(t, signed, verified)
+	assert.Equal(t, int32(len(deliveries)+1), signed)
+}